@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+var blockIDParams actions.BlockIDParams
+
+var blockIDCmd = &cobra.Command{
+	Use:   "block-id <note> <line>",
+	Short: "Gives the block on a line of a note a block ID",
+	Long: `Gives the block (paragraph or list item) on a line of a note a block ID,
+such as "^abc123", and prints a link to it. A paragraph's ID goes at the end
+of its last line. If the block already has an ID, that ID is kept.
+
+Link to the block with [[note#^id]], print it with print "note#^id" or open
+it with open note --block id.
+
+Examples:
+  notesmd-cli block-id "Plan" 12
+  notesmd-cli block-id "Plan" 12 --id goals`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		line, err := strconv.Atoi(args[1])
+		if err != nil {
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid line number %q", args[1])))
+		}
+
+		vault := obsidian.Vault{Name: vaultName}
+		note := obsidian.Note{}
+		params := blockIDParams
+		params.NoteName = args[0]
+		params.Line = line
+		ref, err := actions.AddBlockID(&vault, &note, params)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(ref, func() {
+			fmt.Println(ref.Link)
+		})
+	},
+}
+
+func init() {
+	blockIDCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	blockIDCmd.Flags().StringVar(&blockIDParams.ID, "id", "", "block ID to assign (letters, numbers and dashes); generated if not given")
+	rootCmd.AddCommand(blockIDCmd)
+}
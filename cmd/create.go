@@ -1,16 +1,23 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
+type noteResult struct {
+	Path   string `json:"path"`
+	Opened bool   `json:"opened"`
+}
+
 var shouldAppend bool
 var shouldOverwrite bool
 var content string
+var templateName string
+var templateVars []string
+var appendHeading string
+var appendTimestamp bool
 var createNoteCmd = &cobra.Command{
 	Use:     "create",
 	Aliases: []string{"c"},
@@ -21,6 +28,15 @@ var createNoteCmd = &cobra.Command{
 		uri := obsidian.Uri{}
 		noteName := args[0]
 
+		if (appendHeading != "" || appendTimestamp) && !shouldAppend {
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--heading and --timestamp require --append"))
+		}
+
+		vars, err := actions.ParseTemplateVars(templateVars)
+		if err != nil {
+			exitWithError(err)
+		}
+
 		params := actions.CreateParams{
 			NoteName:        noteName,
 			Content:         content,
@@ -28,11 +44,16 @@ var createNoteCmd = &cobra.Command{
 			ShouldOverwrite: shouldOverwrite,
 			ShouldOpen:      shouldOpen,
 			UseEditor:       resolveUseEditor(cmd, &vault),
+			Template:        templateName,
+			Vars:            vars,
+			Heading:         appendHeading,
+			Timestamp:       appendTimestamp,
 		}
-		err := actions.CreateNote(&vault, &uri, params)
+		notePath, err := actions.CreateNote(&vault, &uri, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
+		printResult(noteResult{Path: notePath, Opened: shouldOpen}, func() {})
 	},
 }
 
@@ -40,7 +61,11 @@ func init() {
 	createNoteCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
 	createNoteCmd.Flags().BoolVarP(&shouldOpen, "open", "", false, "open created note")
 	createNoteCmd.Flags().StringVarP(&content, "content", "c", "", "text to add to note")
+	createNoteCmd.Flags().StringVarP(&templateName, "template", "t", "", "template to fill in when the note is created, from the templates folder or the vault root")
+	createNoteCmd.Flags().StringArrayVar(&templateVars, "var", nil, "template variable as key=value (repeatable)")
 	createNoteCmd.Flags().BoolVarP(&shouldAppend, "append", "a", false, "append to note")
+	createNoteCmd.Flags().StringVar(&appendHeading, "heading", "", "with --append, add content at the end of the section under this heading, e.g. \"## Log\"")
+	createNoteCmd.Flags().BoolVar(&appendTimestamp, "timestamp", false, "with --append, prefix content with the current time")
 	createNoteCmd.Flags().BoolVarP(&shouldOverwrite, "overwrite", "o", false, "overwrite note")
 	createNoteCmd.Flags().BoolP("editor", "e", false, "open in editor instead of Obsidian (requires --open flag)")
 	createNoteCmd.MarkFlagsMutuallyExclusive("append", "overwrite")
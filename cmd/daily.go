@@ -1,33 +1,112 @@
 package cmd
 
 import (
-	"log"
+	"fmt"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
+type dailyListResult struct {
+	Notes []obsidian.DatedNote `json:"notes"`
+}
+
+var dailyParams actions.DailyParams
+var dailyYesterday bool
+var dailyTomorrow bool
+var dailyListParams actions.DailyListParams
+
 var DailyCmd = &cobra.Command{
 	Use:     "daily",
 	Aliases: []string{"d"},
 	Short:   "Creates or opens daily note in vault",
-	Args:    cobra.ExactArgs(0),
+	Long: `Creates or opens the daily note for today, or for another day with --date,
+--yesterday, --tomorrow or --offset.
+
+--date takes a date (YYYY-MM-DD) or a phrase such as "yesterday",
+"3 days ago", "in 2 weeks", "friday", "last friday" or "next monday".
+--offset moves the day by a number of days, e.g. --offset -3.
+
+--append adds text to the note instead of opening it, creating the note
+first if needed. With --heading the text goes at the end of that section,
+before its subsections, and the heading is added if the note does not have
+it. --timestamp prefixes the text with the current time.
+
+Examples:
+  notesmd-cli daily
+  notesmd-cli daily --date 2026-10-01
+  notesmd-cli daily --date "last friday"
+  notesmd-cli daily --yesterday
+  notesmd-cli daily --offset -3
+  notesmd-cli daily --append "- met with Ann" --heading "## Log" --timestamp
+  notesmd-cli daily list --from "2 weeks ago"`,
+	Args: cobra.ExactArgs(0),
 	Run: func(cmd *cobra.Command, args []string) {
 		vault := obsidian.Vault{Name: vaultName}
 		uri := obsidian.Uri{}
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{
-			UseEditor: resolveUseEditor(cmd, &vault),
-		})
+		params := dailyParams
+		if dailyYesterday {
+			params.Date = "yesterday"
+		}
+		if dailyTomorrow {
+			params.Date = "tomorrow"
+		}
+		if (params.Heading != "" || params.Timestamp) && params.Append == "" {
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--heading and --timestamp require --append"))
+		}
+		params.UseEditor = resolveUseEditor(cmd, &vault)
+
+		notePath, err := actions.DailyNote(&vault, &uri, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
+		printResult(noteResult{Path: notePath, Opened: params.Append == ""}, func() {})
+	},
+}
+
+var dailyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Lists existing daily notes, oldest first",
+	Long: `Lists the existing daily notes with their dates, oldest first. Notes are
+found by reading their names back with the date format configured for daily
+notes, so other notes in the daily notes folder are left out.
+
+Examples:
+  notesmd-cli daily list
+  notesmd-cli daily list --from 2026-09-01 --to 2026-09-30
+  notesmd-cli daily list --from "last monday"`,
+	Args: cobra.ExactArgs(0),
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		notes, err := actions.ListDailyNotes(&vault, dailyListParams)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(dailyListResult{Notes: notes}, func() {
+			for _, note := range notes {
+				fmt.Printf("%s  %s\n", note.Date, note.Path)
+			}
+		})
 	},
 }
 
 func init() {
-	DailyCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name (not required if default is set)")
+	DailyCmd.PersistentFlags().StringVarP(&vaultName, "vault", "v", "", "vault name (not required if default is set)")
 	DailyCmd.Flags().BoolP("editor", "e", false, "open in editor instead of Obsidian")
+	DailyCmd.Flags().StringVar(&dailyParams.Date, "date", "", "day of the note: YYYY-MM-DD or a phrase like \"last friday\"")
+	DailyCmd.Flags().BoolVar(&dailyYesterday, "yesterday", false, "open yesterday's note")
+	DailyCmd.Flags().BoolVar(&dailyTomorrow, "tomorrow", false, "open tomorrow's note")
+	DailyCmd.Flags().IntVar(&dailyParams.Offset, "offset", 0, "move the day by this many days, e.g. -3")
+	DailyCmd.Flags().StringVarP(&dailyParams.Append, "append", "a", "", "text to append to the note instead of opening it")
+	DailyCmd.Flags().StringVar(&dailyParams.Heading, "heading", "", "with --append, add the text at the end of the section under this heading, e.g. \"## Log\"")
+	DailyCmd.Flags().BoolVar(&dailyParams.Timestamp, "timestamp", false, "with --append, prefix the text with the current time")
+	DailyCmd.MarkFlagsMutuallyExclusive("date", "yesterday", "tomorrow")
+
+	dailyListCmd.Flags().StringVar(&dailyListParams.From, "from", "", "only notes on or after this day")
+	dailyListCmd.Flags().StringVar(&dailyListParams.To, "to", "", "only notes on or before this day")
+	DailyCmd.AddCommand(dailyListCmd)
 	rootCmd.AddCommand(DailyCmd)
 }
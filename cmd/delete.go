@@ -3,30 +3,51 @@ package cmd
 import (
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
-	"log"
 
 	"github.com/spf13/cobra"
 )
 
+type deleteResult struct {
+	Deleted     string   `json:"deleted"`
+	Attachments []string `json:"attachments"`
+}
+
+var deleteAttachments bool
+
 var deleteCmd = &cobra.Command{
 	Use:     "delete",
 	Aliases: []string{"d"},
 	Short:   "Delete note in vault",
-	Args:    cobra.ExactArgs(1),
+	Long: `Deletes a note, or an attachment when given its path with its extension.
+With --attachments, the attachments the note links to or embeds that no
+other note links to are deleted too.
+
+Examples:
+  notesmd-cli delete "Inbox/Idea"
+  notesmd-cli delete "Inbox/Idea" --attachments
+  notesmd-cli delete "assets/diagram.png"`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		vault := obsidian.Vault{Name: vaultName}
 		note := obsidian.Note{}
 		notePath := args[0]
-		params := actions.DeleteParams{NotePath: notePath}
-		err := actions.DeleteNote(&vault, &note, params)
+		params := actions.DeleteParams{NotePath: notePath, DeleteAttachments: deleteAttachments}
+		deleted, err := actions.DeleteNote(&vault, &note, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
+		}
+		attachments := deleted.Attachments
+		if attachments == nil {
+			attachments = []string{}
 		}
+		result := deleteResult{Deleted: deleted.Deleted, Attachments: attachments}
+		printResult(result, func() {})
 	},
 }
 
 func init() {
 	deleteCmd.Flags().BoolVarP(&shouldOpen, "open", "o", false, "open new note")
 	deleteCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	deleteCmd.Flags().BoolVar(&deleteAttachments, "attachments", false, "also delete attachments no other note links to")
 	rootCmd.AddCommand(deleteCmd)
 }
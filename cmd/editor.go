@@ -1,8 +1,6 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
@@ -13,7 +11,7 @@ import (
 func resolveUseEditor(cmd *cobra.Command, vault obsidian.VaultManager) bool {
 	useEditor, err := cmd.Flags().GetBool("editor")
 	if err != nil {
-		log.Fatalf("Failed to parse --editor flag: %v", err)
+		exitWithError(err)
 	}
 	if !cmd.Flags().Changed("editor") {
 		defaultOpenType, configErr := vault.DefaultOpenType()
@@ -2,64 +2,206 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
+type frontmatterResult struct {
+	Note        string                 `json:"note"`
+	Frontmatter map[string]interface{} `json:"frontmatter,omitempty"`
+	Operation   string                 `json:"operation,omitempty"` // "set", "delete", "rename", "append" or "remove"
+	Key         string                 `json:"key,omitempty"`
+	Value       string                 `json:"value,omitempty"`
+	NewKey      string                 `json:"new_key,omitempty"`
+}
+
+type frontmatterBulkResult struct {
+	Operation string                       `json:"operation"`
+	Key       string                       `json:"key"`
+	Value     string                       `json:"value,omitempty"`
+	NewKey    string                       `json:"new_key,omitempty"`
+	DryRun    bool                         `json:"dry_run"`
+	Notes     []obsidian.FrontmatterChange `json:"notes"`
+}
+
 var fmPrint bool
 var fmEdit bool
 var fmDelete bool
+var fmRename bool
+var fmAppend bool
+var fmRemove bool
 var fmKey string
 var fmValue string
+var fmNewKey string
+var fmGlob string
+var fmFolder string
+var fmTag string
+var fmWhere string
+var fmDryRun bool
 
 var frontmatterCmd = &cobra.Command{
-	Use:     "frontmatter <note>",
+	Use:     "frontmatter [note]",
 	Aliases: []string{"fm"},
 	Short:   "View or modify note frontmatter",
 	Long: `View or modify YAML frontmatter in a note.
 
-Use --print to display frontmatter, --edit to modify a key,
-or --delete to remove a key.
+Use --print to display frontmatter, --edit to modify a key, --delete to
+remove a key, --rename to rename a key, or --append and --remove to add items
+to or remove items from a list.
+
+Keys may be paths into nested values, such as "project.owner" or
+"aliases[0]". Values are read as YAML: numbers, true/false, null and dates
+keep their types, [a, b] is a list and {k: v} a map. Quote a value to keep
+it a string, e.g. --value '"42"'.
+
+Instead of a note, select many notes with --glob, --folder, --tag and --where
+(a condition in "query" WHERE syntax); every selector given must match. The
+change is made to all selected notes together, or to none if any fails. Notes
+that do not have the key are skipped by --delete and --rename. Add --dry-run
+to print a diff of each change without writing anything.
 
 Examples:
   notesmd-cli frontmatter "My Note" --print
   notesmd-cli frontmatter "My Note" --edit --key "status" --value "done"
-  notesmd-cli frontmatter "My Note" --delete --key "draft"`,
-	Args: cobra.ExactArgs(1),
+  notesmd-cli frontmatter "My Note" --delete --key "draft"
+  notesmd-cli frontmatter "My Note" --edit --key "project.priority" --value 2
+  notesmd-cli frontmatter "My Note" --remove --key "tags" --value "draft"
+  notesmd-cli frontmatter --glob "Projects/**/*.md" --rename --key state --new-key status --dry-run
+  notesmd-cli frontmatter --tag project --where 'status = "done"' --append --key tags --value archived`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		noteName := args[0]
 		vault := obsidian.Vault{Name: vaultName}
 		note := obsidian.Note{}
 
+		selector := obsidian.NoteSelector{Glob: fmGlob, Folder: fmFolder, Tag: fmTag, Where: fmWhere}
+		if len(args) == 0 || !selector.IsEmpty() {
+			if len(args) > 0 {
+				exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "a note name cannot be combined with --glob, --folder, --tag, or --where"))
+			}
+			runBulkFrontmatter(&vault, selector)
+			return
+		}
+		if fmDryRun {
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--dry-run requires --glob, --folder, --tag, or --where"))
+		}
+
+		noteName := args[0]
 		params := actions.FrontmatterParams{
 			NoteName: noteName,
 			Print:    fmPrint,
 			Edit:     fmEdit,
 			Delete:   fmDelete,
+			Rename:   fmRename,
+			Append:   fmAppend,
+			Remove:   fmRemove,
 			Key:      fmKey,
 			Value:    fmValue,
+			NewKey:   fmNewKey,
+		}
+
+		if structuredOutput() && fmPrint {
+			fm, err := actions.ReadFrontmatter(&vault, &note, noteName)
+			if err != nil {
+				exitWithError(err)
+			}
+			printResult(frontmatterResult{Note: noteName, Frontmatter: fm}, func() {})
+			return
 		}
 
 		output, err := actions.Frontmatter(&vault, &note, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
 
-		if output != "" {
-			fmt.Print(output)
+		result := frontmatterResult{Note: noteName, Key: fmKey}
+		if fmEdit {
+			result.Operation = "set"
+			result.Value = fmValue
+		} else if fmDelete {
+			result.Operation = "delete"
+		} else if fmRename {
+			result.Operation = "rename"
+			result.NewKey = fmNewKey
+		} else if fmAppend {
+			result.Operation = "append"
+			result.Value = fmValue
+		} else if fmRemove {
+			result.Operation = "remove"
+			result.Value = fmValue
 		}
+		printResult(result, func() {
+			if output != "" {
+				fmt.Print(output)
+			}
+		})
 	},
 }
 
+func runBulkFrontmatter(vault obsidian.VaultManager, selector obsidian.NoteSelector) {
+	changes, err := actions.BulkFrontmatter(vault, actions.BulkFrontmatterParams{
+		Selector: selector,
+		Edit:     fmEdit,
+		Delete:   fmDelete,
+		Rename:   fmRename,
+		Append:   fmAppend,
+		Remove:   fmRemove,
+		Key:      fmKey,
+		Value:    fmValue,
+		NewKey:   fmNewKey,
+		DryRun:   fmDryRun,
+	})
+	if err != nil {
+		exitWithError(err)
+	}
+
+	result := frontmatterBulkResult{Key: fmKey, NewKey: fmNewKey, DryRun: fmDryRun, Notes: changes}
+	switch {
+	case fmEdit:
+		result.Operation = obsidian.FrontmatterSet
+		result.Value = fmValue
+	case fmDelete:
+		result.Operation = obsidian.FrontmatterDelete
+	case fmRename:
+		result.Operation = obsidian.FrontmatterRename
+	case fmAppend:
+		result.Operation = obsidian.FrontmatterAppend
+		result.Value = fmValue
+	case fmRemove:
+		result.Operation = obsidian.FrontmatterRemove
+		result.Value = fmValue
+	}
+	printResult(result, func() {
+		if fmDryRun {
+			for _, change := range changes {
+				fmt.Print(change.Diff)
+			}
+			fmt.Printf("Would update frontmatter in %d notes\n", len(changes))
+			return
+		}
+		fmt.Printf("Updated frontmatter in %d notes\n", len(changes))
+		for _, change := range changes {
+			fmt.Println(change.Path)
+		}
+	})
+}
+
 func init() {
 	frontmatterCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
 	frontmatterCmd.Flags().BoolVarP(&fmPrint, "print", "p", false, "print frontmatter")
 	frontmatterCmd.Flags().BoolVarP(&fmEdit, "edit", "e", false, "edit a frontmatter key")
 	frontmatterCmd.Flags().BoolVarP(&fmDelete, "delete", "d", false, "delete a frontmatter key")
-	frontmatterCmd.Flags().StringVarP(&fmKey, "key", "k", "", "key to edit or delete")
-	frontmatterCmd.Flags().StringVar(&fmValue, "value", "", "value to set (required for --edit)")
+	frontmatterCmd.Flags().BoolVar(&fmRename, "rename", false, "rename a frontmatter key")
+	frontmatterCmd.Flags().BoolVar(&fmAppend, "append", false, "append a value to a frontmatter list")
+	frontmatterCmd.Flags().BoolVar(&fmRemove, "remove", false, "remove a value from a frontmatter list")
+	frontmatterCmd.Flags().StringVarP(&fmKey, "key", "k", "", "key or key path (e.g. project.owner, aliases[0]) to change")
+	frontmatterCmd.Flags().StringVar(&fmValue, "value", "", "YAML value to set, append or remove (required for --edit, --append and --remove)")
+	frontmatterCmd.Flags().StringVar(&fmNewKey, "new-key", "", "new key name (required for --rename)")
+	frontmatterCmd.Flags().StringVar(&fmGlob, "glob", "", "select notes whose vault-relative path matches a glob")
+	frontmatterCmd.Flags().StringVar(&fmFolder, "folder", "", "select notes in a folder")
+	frontmatterCmd.Flags().StringVar(&fmTag, "tag", "", "select notes with a tag")
+	frontmatterCmd.Flags().StringVar(&fmWhere, "where", "", "select notes whose frontmatter matches a condition")
+	frontmatterCmd.Flags().BoolVar(&fmDryRun, "dry-run", false, "print the changes to selected notes without writing them")
 	rootCmd.AddCommand(frontmatterCmd)
 }
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+var indexCmd = &cobra.Command{
+	Use:   "index",
+	Short: "Manage the persistent vault index",
+	Long: `Manage the persistent vault index.
+
+The index records the path, modification time, size, headings, links, tags
+and frontmatter of every note in .obsidian/notesmd-cli-index.json. Once it
+exists, commands consult it instead of walking the whole vault. Notes that
+changed since the index was saved are re-read in memory, without writing
+the index file; run "index update" to save those changes.`,
+}
+
+var indexRebuildCmd = &cobra.Command{
+	Use:   "rebuild",
+	Short: "Builds the vault index from scratch",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		status, err := actions.RebuildIndex(&vault)
+		if err != nil {
+			exitWithError(err)
+		}
+		printResult(status, func() {
+			fmt.Printf("Indexed %d notes into %s\n", status.Notes, status.Path)
+		})
+	},
+}
+
+var indexUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Saves changes to notes since the vault index was last saved",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		status, err := actions.UpdateIndex(&vault)
+		if err != nil {
+			exitWithError(err)
+		}
+		printResult(status, func() {
+			fmt.Printf("Updated %s: %d added, %d modified, %d removed, %d notes\n", status.Path, status.Added, status.Updated, status.Removed, status.Notes)
+		})
+	},
+}
+
+var indexStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Shows whether the vault index exists and is up to date",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		status, err := actions.IndexStatus(&vault)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(status, func() {
+			fmt.Println("Index path:", status.Path)
+			if !status.Exists {
+				fmt.Println("Index does not exist. Run 'notesmd-cli index rebuild' to create it.")
+				return
+			}
+			fmt.Println("Last updated:", status.UpdatedAt.Format("2006-01-02 15:04:05"))
+			fmt.Println("Indexed notes:", status.Notes)
+			fmt.Printf("Pending changes: %d added, %d modified, %d removed\n", status.Added, status.Updated, status.Removed)
+		})
+	},
+}
+
+func init() {
+	indexCmd.PersistentFlags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	indexCmd.AddCommand(indexRebuildCmd)
+	indexCmd.AddCommand(indexUpdateCmd)
+	indexCmd.AddCommand(indexStatusCmd)
+	rootCmd.AddCommand(indexCmd)
+}
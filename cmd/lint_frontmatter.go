@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+var lintSchema string
+
+var lintFrontmatterCmd = &cobra.Command{
+	Use:   "lint-frontmatter [files...]",
+	Short: "Checks note frontmatter against a schema",
+	Long: `Checks the frontmatter of notes against a schema file and reports every
+key that is missing or has a value the schema does not allow.
+
+The schema is YAML or JSON. Each rule selects notes by glob, folder, tag or
+where condition (all notes when none is given) and lists required keys and
+the type, enum, pattern, minimum, maximum, format and items of each key:
+
+  rules:
+    - folder: Projects
+      required: [status, due]
+      properties:
+        status: {type: string, enum: [active, paused, done]}
+        due: {type: date}
+    - tag: book
+      properties:
+        rating: {type: integer, minimum: 1, maximum: 5}
+
+A schema without rules applies to every note, so a JSON Schema document with
+"required" and "properties" can be used directly. Types are string, number,
+integer, boolean, null, list (array), map (object), date and datetime.
+
+With file arguments, only those notes are checked, which suits a pre-commit
+hook. The command exits with status 1 when any note breaks the schema, or on
+error, and 0 otherwise.
+
+Examples:
+  notesmd-cli lint-frontmatter --schema frontmatter-schema.yaml
+  notesmd-cli lint-frontmatter --schema schema.json Projects/plan.md`,
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		report, err := actions.LintFrontmatter(&vault, lintSchema, args)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(report, func() {
+			notes := make(map[string]bool)
+			for _, v := range report.Violations {
+				notes[v.Path] = true
+				if v.Key != "" {
+					fmt.Printf("%s: %s: %s\n", v.Path, v.Key, v.Message)
+				} else {
+					fmt.Printf("%s: %s\n", v.Path, v.Message)
+				}
+			}
+			if len(report.Violations) == 0 {
+				fmt.Printf("Checked %d notes, no problems found\n", report.Checked)
+				return
+			}
+			fmt.Printf("%d problems in %d of %d notes\n", len(report.Violations), len(notes), report.Checked)
+		})
+		if len(report.Violations) > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func init() {
+	lintFrontmatterCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	lintFrontmatterCmd.Flags().StringVarP(&lintSchema, "schema", "s", "", "path to the schema file (YAML or JSON)")
+	rootCmd.AddCommand(lintFrontmatterCmd)
+}
@@ -2,13 +2,23 @@ package cmd
 
 import (
 	"fmt"
-	"log"
+	"strings"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
+type listEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // "folder" or "file"
+}
+
+type listResult struct {
+	Path    string      `json:"path"`
+	Entries []listEntry `json:"entries"`
+}
+
 var listCmd = &cobra.Command{
 	Use:     "list [path]",
 	Aliases: []string{"ls"},
@@ -23,12 +33,23 @@ var listCmd = &cobra.Command{
 		vault := obsidian.Vault{Name: vaultName}
 		entries, err := actions.ListEntries(&vault, actions.ListParams{Path: targetPath})
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
 
+		result := listResult{Path: targetPath, Entries: make([]listEntry, 0, len(entries))}
 		for _, entry := range entries {
-			fmt.Printf("• %s\n", entry)
+			if strings.HasSuffix(entry, "/") {
+				result.Entries = append(result.Entries, listEntry{Name: strings.TrimSuffix(entry, "/"), Type: "folder"})
+			} else {
+				result.Entries = append(result.Entries, listEntry{Name: entry, Type: "file"})
+			}
 		}
+
+		printResult(result, func() {
+			for _, entry := range entries {
+				fmt.Printf("• %s\n", entry)
+			}
+		})
 	},
 }
 
@@ -3,18 +3,65 @@ package cmd
 import (
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
-	"log"
 
 	"github.com/spf13/cobra"
 )
 
+type moveResult struct {
+	From   string              `json:"from"`
+	To     string              `json:"to"`
+	Opened bool                `json:"opened"`
+	Files  []obsidian.FileMove `json:"files"`
+}
+
+type moveBatchResult struct {
+	Files []obsidian.FileMove `json:"files"`
+}
+
 var shouldOpen bool
+var moveAttachments bool
+var moveFromFile string
 var moveCmd = &cobra.Command{
 	Use:     "move",
 	Aliases: []string{"m"},
 	Short:   "Move or rename note in vault and updated corresponding links",
-	Args:    cobra.ExactArgs(2),
+	Long: `Moves or renames a note, or a folder with every note and attachment in it,
+and updates the links to everything that moved across the vault. An
+attachment can be moved the same way, given its path with its extension.
+
+With --attachments, the attachments only the moved note links to follow it
+to its new attachment folder, as set by "Default location for new
+attachments" (attachmentFolderPath in .obsidian/app.json). Attachments
+move only when that folder depends on the note's location.
+
+With --from-file, many notes, attachments and folders move at once, as
+listed in a mapping file: one move per line with the old and new path
+separated by a tab, or JSON, either {"old": "new", ...} or
+[{"from": "old", "to": "new"}, ...]. Every move is checked before anything
+moves, so a missing source, a taken destination or moves that form a cycle
+leave the vault untouched, and links are updated in a single pass.
+
+Examples:
+  notesmd-cli move "Inbox/Idea" "Projects/Idea"
+  notesmd-cli move "Inbox/Idea" "Projects/Idea" --attachments
+  notesmd-cli move Projects Archive/Projects
+  notesmd-cli move --from-file reorganize.tsv`,
+	Args: cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
+		if moveFromFile != "" {
+			if len(args) > 0 {
+				exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "note paths cannot be combined with --from-file"))
+			}
+			if shouldOpen || moveAttachments {
+				exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--open and --attachments cannot be used with --from-file"))
+			}
+			runBatchMove()
+			return
+		}
+		if len(args) != 2 {
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "move requires the current and the new path, or --from-file"))
+		}
+
 		currentName := args[0]
 		newName := args[1]
 		vault := obsidian.Vault{Name: vaultName}
@@ -25,17 +72,41 @@ var moveCmd = &cobra.Command{
 			NewNoteName:     newName,
 			ShouldOpen:      shouldOpen,
 			UseEditor:       resolveUseEditor(cmd, &vault),
+			MoveAttachments: moveAttachments,
 		}
-		err := actions.MoveNote(&vault, &note, &uri, params)
+		moved, err := actions.MoveNote(&vault, &note, &uri, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
+		result := moveResult{
+			From:   moved.From,
+			To:     moved.To,
+			Opened: shouldOpen,
+			Files:  moved.Files,
+		}
+		printResult(result, func() {})
 	},
 }
 
+func runBatchMove() {
+	vault := obsidian.Vault{Name: vaultName}
+	note := obsidian.Note{}
+	moves, err := actions.ReadMoveMap(moveFromFile)
+	if err != nil {
+		exitWithError(err)
+	}
+	files, err := actions.MoveBatch(&vault, &note, actions.MoveBatchParams{Moves: moves})
+	if err != nil {
+		exitWithError(err)
+	}
+	printResult(moveBatchResult{Files: files}, func() {})
+}
+
 func init() {
 	moveCmd.Flags().BoolVarP(&shouldOpen, "open", "o", false, "open new note")
 	moveCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
 	moveCmd.Flags().BoolP("editor", "e", false, "open in editor instead of Obsidian (requires --open flag)")
+	moveCmd.Flags().BoolVar(&moveAttachments, "attachments", false, "also move the note's attachments to its new attachment folder")
+	moveCmd.Flags().StringVar(&moveFromFile, "from-file", "", "move the notes listed in a TSV or JSON mapping file")
 	rootCmd.AddCommand(moveCmd)
 }
@@ -1,15 +1,21 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
+type openResult struct {
+	Note    string `json:"note"`
+	Section string `json:"section,omitempty"`
+	Block   string `json:"block,omitempty"`
+	Editor  bool   `json:"editor"`
+}
+
 var vaultName string
 var sectionName string
+var blockID string
 var OpenVaultCmd = &cobra.Command{
 	Use:     "open",
 	Aliases: []string{"o"},
@@ -20,17 +26,20 @@ var OpenVaultCmd = &cobra.Command{
 		uri := obsidian.Uri{}
 		noteName := args[0]
 
-		params := actions.OpenParams{NoteName: noteName, Section: sectionName, UseEditor: resolveUseEditor(cmd, &vault)}
+		params := actions.OpenParams{NoteName: noteName, Section: sectionName, Block: blockID, UseEditor: resolveUseEditor(cmd, &vault)}
 		err := actions.OpenNote(&vault, &uri, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
+		printResult(openResult{Note: noteName, Section: sectionName, Block: blockID, Editor: params.UseEditor}, func() {})
 	},
 }
 
 func init() {
 	OpenVaultCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name (not required if default is set)")
 	OpenVaultCmd.Flags().StringVarP(&sectionName, "section", "s", "", "heading text to open within the note (case-sensitive)")
+	OpenVaultCmd.Flags().StringVar(&blockID, "block", "", "block ID to open within the note, e.g. \"abc123\" for [[note#^abc123]]")
+	OpenVaultCmd.MarkFlagsMutuallyExclusive("section", "block")
 	OpenVaultCmd.Flags().BoolP("editor", "e", false, "open in editor instead of Obsidian")
 	rootCmd.AddCommand(OpenVaultCmd)
 }
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"gopkg.in/yaml.v3"
+)
+
+// Values accepted by the global --output flag.
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+var outputFormat string
+
+// stdout receives command results. With a structured --output, os.Stdout is
+// pointed at stderr while a command runs so that progress messages printed
+// along the way cannot corrupt the JSON or YAML document on stdout.
+var stdout io.Writer = os.Stdout
+
+// errorResult is the document printed in place of a result when a command
+// fails with a structured --output.
+type errorResult struct {
+	Error obsidian.Error `json:"error"`
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputText, "output format: text, json or yaml")
+}
+
+// setupOutput validates --output and, for structured output, diverts
+// os.Stdout to stderr.
+func setupOutput() error {
+	switch outputFormat {
+	case outputText:
+		return nil
+	case outputJSON, outputYAML:
+		os.Stdout = os.Stderr
+		return nil
+	}
+	return obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid output format %q: must be text, json or yaml", outputFormat))
+}
+
+// structuredOutput reports whether --output asks for JSON or YAML.
+func structuredOutput() bool {
+	return outputFormat == outputJSON || outputFormat == outputYAML
+}
+
+// printResult writes result as JSON or YAML according to --output, or calls
+// printText to produce the human-readable output.
+func printResult(result interface{}, printText func()) {
+	if !structuredOutput() {
+		printText()
+		return
+	}
+	if err := writeDocument(stdout, result); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// exitWithError reports err and exits with status 1. With a structured
+// --output the error is printed to stdout as {"error": {"code", "message"}}.
+func exitWithError(err error) {
+	if !structuredOutput() {
+		log.Fatal(err)
+	}
+	result := errorResult{Error: obsidian.Error{Code: obsidian.ErrorCode(err), Message: err.Error()}}
+	if writeErr := writeDocument(stdout, result); writeErr != nil {
+		log.Fatal(err)
+	}
+	os.Exit(1)
+}
+
+// writeDocument encodes v in the --output format. YAML is produced from the
+// JSON encoding so both formats share field names and field order.
+func writeDocument(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if outputFormat == outputJSON {
+		var indented []byte
+		if indented, err = json.MarshalIndent(json.RawMessage(data), "", "  "); err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", indented)
+		return err
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// resetYAMLStyle clears the flow and quoting styles JSON input parses with,
+// so the encoder emits block-style YAML and quotes only where required.
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+// newPeriodicCmd returns the command that creates or opens the current note
+// of period, described as e.g. "this week's".
+func newPeriodicCmd(period, description string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   period,
+		Short: fmt.Sprintf("Creates or opens %s note in vault", description),
+		Long: fmt.Sprintf(`Creates or opens %s note.
+
+The folder, format and template are read from the Periodic Notes plugin
+config (.obsidian/plugins/periodic-notes/data.json). Without it, the note is
+created at the vault root with the plugin's default format.`, description),
+		Args: cobra.ExactArgs(0),
+		Run: func(cmd *cobra.Command, args []string) {
+			vault := obsidian.Vault{Name: vaultName}
+			uri := obsidian.Uri{}
+
+			notePath, err := actions.PeriodicNote(&vault, &uri, actions.PeriodicParams{
+				Period:    period,
+				UseEditor: resolveUseEditor(cmd, &vault),
+			})
+			if err != nil {
+				exitWithError(err)
+			}
+			printResult(noteResult{Path: notePath, Opened: true}, func() {})
+		},
+	}
+	cmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name (not required if default is set)")
+	cmd.Flags().BoolP("editor", "e", false, "open in editor instead of Obsidian")
+	return cmd
+}
+
+func init() {
+	rootCmd.AddCommand(newPeriodicCmd(obsidian.PeriodWeekly, "this week's"))
+	rootCmd.AddCommand(newPeriodicCmd(obsidian.PeriodMonthly, "this month's"))
+	rootCmd.AddCommand(newPeriodicCmd(obsidian.PeriodQuarterly, "this quarter's"))
+	rootCmd.AddCommand(newPeriodicCmd(obsidian.PeriodYearly, "this year's"))
+}
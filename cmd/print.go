@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
+
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
-	"log"
 
 	"github.com/spf13/cobra"
 )
@@ -25,9 +25,18 @@ var printCmd = &cobra.Command{
 			NoteName:        noteName,
 			IncludeMentions: includeMentions,
 		}
+		if structuredOutput() {
+			result, err := actions.ReadNote(&vault, &note, params)
+			if err != nil {
+				exitWithError(err)
+			}
+			printResult(result, func() {})
+			return
+		}
+
 		contents, err := actions.PrintNote(&vault, &note, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
 		fmt.Println(contents)
 	},
@@ -2,12 +2,17 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
+type defaultVaultResult struct {
+	Name     string `json:"name"`
+	Path     string `json:"path"`
+	OpenType string `json:"open_type"`
+}
+
 var printPathOnly bool
 var printDefaultCmd = &cobra.Command{
 	Use:     "print-default",
@@ -18,23 +23,25 @@ var printDefaultCmd = &cobra.Command{
 		vault := obsidian.Vault{}
 		name, err := vault.DefaultName()
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
 		path, err := vault.Path()
 		if err != nil {
-			log.Fatal(err)
-		}
-
-		if printPathOnly {
-			fmt.Print(path)
-			return
+			exitWithError(err)
 		}
 
 		openType, _ := vault.DefaultOpenType()
 
-		fmt.Println("Default vault name:", name)
-		fmt.Println("Default vault path:", path)
-		fmt.Println("Default open type:", openType)
+		result := defaultVaultResult{Name: name, Path: path, OpenType: openType}
+		printResult(result, func() {
+			if printPathOnly {
+				fmt.Print(path)
+				return
+			}
+			fmt.Println("Default vault name:", name)
+			fmt.Println("Default vault path:", path)
+			fmt.Println("Default open type:", openType)
+		})
 	},
 }
 
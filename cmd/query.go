@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+var queryFormat string
+
+var queryCmd = &cobra.Command{
+	Use:   "query <query>",
+	Short: "Query note frontmatter with a Dataview-style TABLE or LIST",
+	Long: `Evaluate a Dataview-style query over the frontmatter of every note and
+print the result as a Markdown table, CSV or JSON.
+
+  TABLE [WITHOUT ID] expr [AS "Name"], ...  or  LIST [WITHOUT ID] [expr]
+  FROM "folder" | #tag                      combine with and, or, -, ( )
+  WHERE expr                                e.g. status != "done" and due < date(today)
+  SORT expr [ASC|DESC], ...
+  LIMIT n
+
+Expressions use frontmatter keys (nested keys with dots), file.name,
+file.path, file.folder, file.ext, file.tags, file.mtime and file.size,
+the comparisons = != < <= > >=, and/or/!, and the functions contains,
+icontains, startswith, endswith, length, lower, upper, default, number and
+date (date(today), date(tomorrow), date(yesterday), date(now)).
+
+Examples:
+  notesmd-cli query 'TABLE status, due FROM "Projects" WHERE status != "done" SORT due'
+  notesmd-cli query 'LIST FROM #book WHERE rating >= 4' --format csv`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		result, err := actions.RunQuery(&vault, args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(result, func() {
+			if err := actions.WriteQueryResult(stdout, result, queryFormat); err != nil {
+				exitWithError(err)
+			}
+		})
+	},
+}
+
+func init() {
+	queryCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	queryCmd.Flags().StringVarP(&queryFormat, "format", "f", actions.QueryFormatMarkdown, "output format: md, csv or json")
+	rootCmd.AddCommand(queryCmd)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
@@ -12,10 +13,16 @@ var rootCmd = &cobra.Command{
 	Short:   "Interact with Obsidian vaults from the terminal",
 	Version: "v0.3.1",
 	Long:    "Interact with Obsidian vaults from the terminal",
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return setupOutput()
+	},
 }
 
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
+		if structuredOutput() {
+			exitWithError(obsidian.WrapError(obsidian.ErrCodeInvalidArgument, err.Error(), err))
+		}
 		fmt.Fprintf(os.Stderr, "Whoops. There was an error while executing your CLI '%s'", err)
 		os.Exit(1)
 	}
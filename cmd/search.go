@@ -3,30 +3,83 @@ package cmd
 import (
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
-	"log"
 
 	"github.com/spf13/cobra"
 )
 
+var noInteractive bool
+var matchFormat string
+var matchLimit int
+
+type matchesResult struct {
+	Matches []obsidian.NoteMatch `json:"matches"`
+}
+
 var searchCmd = &cobra.Command{
-	Use:     "search",
+	Use:     "search [filter]",
 	Aliases: []string{"s"},
 	Short:   "Fuzzy searches and opens note in vault",
-	Args:    cobra.NoArgs,
+	Long: `Fuzzy searches and opens note in vault.
+
+Given a filter, or with --no-interactive, the notes whose path contains the
+filter are printed instead of opening the picker; see --format and --limit.`,
+	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		vault := obsidian.Vault{Name: vaultName}
 		note := obsidian.Note{}
 		uri := obsidian.Uri{}
 		fuzzyFinder := obsidian.FuzzyFinder{}
+
+		if listMatches(cmd) || len(args) > 0 {
+			filter := ""
+			if len(args) > 0 {
+				filter = args[0]
+			}
+			matches, err := actions.FindNotes(&vault, &note, filter, matchLimit)
+			if err != nil {
+				exitWithError(err)
+			}
+			printMatches(matches)
+			return
+		}
+
 		err := actions.SearchNotes(&vault, &note, &uri, &fuzzyFinder, resolveUseEditor(cmd, &vault))
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
 	},
 }
 
+// addMatchOutputFlags registers the flags controlling non-interactive search
+// output.
+func addMatchOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&noInteractive, "no-interactive", false, "print matches instead of opening the picker")
+	cmd.Flags().StringVarP(&matchFormat, "format", "f", actions.MatchFormatPlain, "output format with --no-interactive: plain, tsv, json or ndjson")
+	cmd.Flags().IntVarP(&matchLimit, "limit", "n", 0, "maximum number of matches to print with --no-interactive (0 for all)")
+}
+
+// listMatches reports whether a search command should print its matches
+// rather than open the picker.
+func listMatches(cmd *cobra.Command) bool {
+	return noInteractive || structuredOutput() || cmd.Flags().Changed("format") || cmd.Flags().Changed("limit")
+}
+
+// printMatches prints search matches in the --format format, or as
+// {"matches": [...]} with a structured --output.
+func printMatches(matches []obsidian.NoteMatch) {
+	if matches == nil {
+		matches = []obsidian.NoteMatch{}
+	}
+	printResult(matchesResult{Matches: matches}, func() {
+		if err := actions.WriteMatches(stdout, matches, matchFormat); err != nil {
+			exitWithError(err)
+		}
+	})
+}
+
 func init() {
 	searchCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
 	searchCmd.Flags().BoolP("editor", "e", false, "open in editor instead of Obsidian")
+	addMatchOutputFlags(searchCmd)
 	rootCmd.AddCommand(searchCmd)
 }
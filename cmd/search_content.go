@@ -1,17 +1,53 @@
 package cmd
 
 import (
-	"log"
-
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 
 	"github.com/spf13/cobra"
 )
 
+var rankedSearch bool
+var querySearch bool
+var regexSearch bool
+var caseSensitive bool
+var multilineRegex bool
+
 var searchContentCmd = &cobra.Command{
-	Use:     "search-content [search term]",
-	Short:   "Search node content for search term",
+	Use:   "search-content [search term]",
+	Short: "Search node content for search term",
+	Long: `Search note content for a search term.
+
+By default notes are matched on a case-insensitive substring. With --ranked
+the term is treated as a full-text query and results are ordered by
+relevance (BM25). Ranked queries support:
+
+  meeting notes          both words (AND is implicit)
+  "weekly sync"          exact phrase
+  alpha OR beta          either word
+  -draft, NOT draft      exclude notes containing a word
+  proj*                  prefix match
+  (alpha OR beta) -old   grouping
+
+With --query the term uses Obsidian's search syntax, so a query pasted from
+Obsidian's search pane returns the same notes:
+
+  path:Projects          note path contains a value
+  file:meeting           file name contains a value
+  tag:#todo              note has a tag (or a nested tag like #todo/later)
+  content:word           only search note content
+  line:(foo bar)         all terms on the same line
+  block:(foo bar)        all terms in the same paragraph
+  section:(foo bar)      all terms under the same heading
+  /\d{4}-\d{2}/          regular expression
+  "exact phrase", -word, alpha OR beta, (grouping)
+
+With --regex the term is a Go (RE2) regular expression, matched
+case-insensitively unless --case-sensitive is given. --multiline lets matches
+span lines. Capture groups (or the whole match) are marked «like this».
+
+Use --no-interactive to print matches instead of opening the picker; see
+--format and --limit.`,
 	Args:    cobra.ExactArgs(1),
 	Aliases: []string{"sc"},
 	Run: func(cmd *cobra.Command, args []string) {
@@ -20,10 +56,29 @@ var searchContentCmd = &cobra.Command{
 		uri := obsidian.Uri{}
 		fuzzyFinder := obsidian.FuzzyFinder{}
 
-		searchTerm := args[0]
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, searchTerm, resolveUseEditor(cmd, &vault))
+		params := actions.SearchContentParams{
+			SearchTerm:    args[0],
+			Ranked:        rankedSearch,
+			Query:         querySearch,
+			Regex:         regexSearch,
+			CaseSensitive: caseSensitive,
+			Multiline:     multilineRegex,
+		}
+
+		if listMatches(cmd) {
+			params.Limit = matchLimit
+			matches, err := actions.FindNotesContent(&vault, &note, params)
+			if err != nil {
+				exitWithError(err)
+			}
+			printMatches(matches)
+			return
+		}
+
+		params.UseEditor = resolveUseEditor(cmd, &vault)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, params)
 		if err != nil {
-			log.Fatal(err)
+			exitWithError(err)
 		}
 	},
 }
@@ -31,5 +86,12 @@ var searchContentCmd = &cobra.Command{
 func init() {
 	searchContentCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
 	searchContentCmd.Flags().BoolP("editor", "e", false, "open in editor instead of Obsidian")
+	searchContentCmd.Flags().BoolVarP(&rankedSearch, "ranked", "r", false, "full-text query with results ordered by relevance")
+	searchContentCmd.Flags().BoolVarP(&querySearch, "query", "q", false, "use Obsidian search syntax (path:, file:, tag:, line:, section:)")
+	searchContentCmd.Flags().BoolVarP(&regexSearch, "regex", "x", false, "treat the search term as a regular expression")
+	searchContentCmd.Flags().BoolVar(&caseSensitive, "case-sensitive", false, "match case in --regex searches")
+	searchContentCmd.Flags().BoolVar(&multilineRegex, "multiline", false, "allow --regex matches to span lines")
+	searchContentCmd.MarkFlagsMutuallyExclusive("ranked", "query", "regex")
+	addMatchOutputFlags(searchContentCmd)
 	rootCmd.AddCommand(searchContentCmd)
 }
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+type sectionResult struct {
+	Note      string `json:"note"`
+	Section   string `json:"section"`
+	Content   string `json:"content,omitempty"`
+	Operation string `json:"operation,omitempty"` // "replace", "prepend", "append" or "delete"
+}
+
+var sectionReplace string
+var sectionPrepend string
+var sectionAppend string
+var sectionDelete bool
+
+var sectionCmd = &cobra.Command{
+	Use:   "section <note> <heading path>",
+	Short: "Print or edit the content under a heading of a note",
+	Long: `Print, replace, prepend to, append to or delete the content under a
+heading of a note, directly on disk.
+
+The heading path is a heading, or headings separated by ">" to pick one
+inside another, such as "Projects > Alpha > Notes". Each heading may be
+nested at any depth under the previous one. Headings are matched ignoring
+case, and by level when written with "#"s, e.g. "## Notes". A section runs
+until the next heading of the same or a higher level, so it includes its
+subsections.
+
+Without an editing flag the content of the section is printed. --replace
+swaps the content (subsections included) for new text, --prepend and
+--append add lines at its start or at the end of its own content, before
+any subsections, and --delete removes the section with its heading.
+
+Examples:
+  notesmd-cli section "Plan" "Projects > Alpha > Notes"
+  notesmd-cli section "Plan" "## Tasks" --append "- [ ] review"
+  notesmd-cli section "Plan" "Projects > Alpha" --replace "Done.\nShipped in May."
+  notesmd-cli section "Plan" "Scratch" --delete`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		note := obsidian.Note{}
+		params := actions.SectionParams{NoteName: args[0], Path: args[1]}
+
+		switch {
+		case cmd.Flags().Changed("replace"):
+			params.Operation, params.Content = obsidian.SectionReplace, sectionReplace
+		case cmd.Flags().Changed("prepend"):
+			params.Operation, params.Content = obsidian.SectionPrepend, sectionPrepend
+		case cmd.Flags().Changed("append"):
+			params.Operation, params.Content = obsidian.SectionAppend, sectionAppend
+		case sectionDelete:
+			params.Operation = obsidian.SectionDelete
+		}
+
+		if params.Operation == "" {
+			content, err := actions.ReadSection(&vault, &note, params)
+			if err != nil {
+				exitWithError(err)
+			}
+			printResult(sectionResult{Note: params.NoteName, Section: params.Path, Content: content}, func() {
+				fmt.Println(content)
+			})
+			return
+		}
+
+		if err := actions.EditSection(&vault, &note, params); err != nil {
+			exitWithError(err)
+		}
+		printResult(sectionResult{Note: params.NoteName, Section: params.Path, Operation: params.Operation}, func() {})
+	},
+}
+
+func init() {
+	sectionCmd.Flags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	sectionCmd.Flags().StringVar(&sectionReplace, "replace", "", "replace the content of the section with this text")
+	sectionCmd.Flags().StringVar(&sectionPrepend, "prepend", "", "add this text at the start of the section")
+	sectionCmd.Flags().StringVar(&sectionAppend, "append", "", "add this text at the end of the section, before its subsections")
+	sectionCmd.Flags().BoolVar(&sectionDelete, "delete", false, "delete the section and its heading")
+	sectionCmd.MarkFlagsMutuallyExclusive("replace", "prepend", "append", "delete")
+	rootCmd.AddCommand(sectionCmd)
+}
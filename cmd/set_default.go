@@ -2,12 +2,17 @@ package cmd
 
 import (
 	"fmt"
-	"log"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/spf13/cobra"
 )
 
+type setDefaultResult struct {
+	Name     string `json:"name,omitempty"`
+	Path     string `json:"path,omitempty"`
+	OpenType string `json:"open_type,omitempty"`
+}
+
 var setDefaultCmd = &cobra.Command{
 	Use:     "set-default",
 	Aliases: []string{"sd"},
@@ -16,37 +21,48 @@ var setDefaultCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		openType, err := cmd.Flags().GetString("open-type")
 		if err != nil {
-			log.Fatalf("Failed to parse --open-type flag: %v", err)
+			exitWithError(err)
 		}
 
 		if len(args) == 0 && openType == "" {
-			log.Fatal("Please provide a vault name or use --open-type to set the default open type")
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, "Please provide a vault name or use --open-type to set the default open type"))
+		}
+		if openType != "" && openType != "obsidian" && openType != "editor" {
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("Invalid open type %q: must be 'obsidian' or 'editor'", openType)))
 		}
 
+		result := setDefaultResult{}
 		if len(args) > 0 {
 			name := args[0]
 			v := obsidian.Vault{Name: name}
 			if err := v.SetDefaultName(name); err != nil {
-				log.Fatal(err)
+				exitWithError(err)
 			}
 			path, err := v.Path()
 			if err != nil {
-				log.Fatal(err)
+				exitWithError(err)
 			}
-			fmt.Println("Default vault set to:", name)
-			fmt.Println("Default vault path set to:", path)
+			result.Name = name
+			result.Path = path
 		}
 
 		if openType != "" {
-			if openType != "obsidian" && openType != "editor" {
-				log.Fatalf("Invalid open type %q: must be 'obsidian' or 'editor'", openType)
-			}
 			v := obsidian.Vault{}
 			if err := v.SetDefaultOpenType(openType); err != nil {
-				log.Fatal(err)
+				exitWithError(err)
 			}
-			fmt.Println("Default open type set to:", openType)
+			result.OpenType = openType
 		}
+
+		printResult(result, func() {
+			if result.Name != "" {
+				fmt.Println("Default vault set to:", result.Name)
+				fmt.Println("Default vault path set to:", result.Path)
+			}
+			if result.OpenType != "" {
+				fmt.Println("Default open type set to:", result.OpenType)
+			}
+		})
 	},
 }
 
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+type tagsResult struct {
+	Tags []obsidian.TagCount `json:"tags"`
+}
+
+type tagNotesResult struct {
+	Tag   string   `json:"tag"`
+	Notes []string `json:"notes"`
+}
+
+type tagRenameResult struct {
+	From  string   `json:"from"`
+	To    string   `json:"to"`
+	Notes []string `json:"notes"`
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "List, find and rename tags",
+	Long: `List every tag in the vault with the number of notes using it.
+
+Tags are read from note bodies (#tag, including nested tags like #a/b/c) and
+from the "tags" frontmatter key. Tags in code blocks and inline code are
+ignored, and tags differing only in case are treated as the same tag.
+
+Examples:
+  notesmd-cli tags
+  notesmd-cli tags notes project
+  notesmd-cli tags rename project work/project`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		tags, err := actions.ListTags(&vault)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(tagsResult{Tags: tags}, func() {
+			width := 0
+			for _, t := range tags {
+				if len(t.Tag)+1 > width {
+					width = len(t.Tag) + 1
+				}
+			}
+			for _, t := range tags {
+				fmt.Printf("%-*s  %d\n", width, "#"+t.Tag, t.Count)
+			}
+		})
+	},
+}
+
+var tagsNotesCmd = &cobra.Command{
+	Use:   "notes <tag>",
+	Short: "Lists the notes with a tag or one of its nested tags",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		notes, err := actions.TagNotes(&vault, args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(tagNotesResult{Tag: args[0], Notes: notes}, func() {
+			for _, note := range notes {
+				fmt.Println(note)
+			}
+		})
+	},
+}
+
+var tagsRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Renames a tag and its nested tags across the vault",
+	Long: `Renames a tag everywhere it is used, in note bodies and frontmatter.
+
+Nested tags move with their parent, so renaming "project" to "work" also turns
+#project/alpha into #work/alpha. All affected notes are rewritten together: if
+any note cannot be written, none are changed.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		notes, err := actions.RenameTag(&vault, args[0], args[1])
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(tagRenameResult{From: args[0], To: args[1], Notes: notes}, func() {
+			fmt.Printf("Renamed tag in %d notes\n", len(notes))
+			for _, note := range notes {
+				fmt.Println(note)
+			}
+		})
+	},
+}
+
+func init() {
+	tagsCmd.PersistentFlags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	tagsCmd.AddCommand(tagsNotesCmd)
+	tagsCmd.AddCommand(tagsRenameCmd)
+	rootCmd.AddCommand(tagsCmd)
+}
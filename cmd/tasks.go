@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+type tasksResult struct {
+	Tasks []obsidian.Task `json:"tasks"`
+}
+
+type taskResult struct {
+	Task obsidian.Task `json:"task"`
+}
+
+var taskParams actions.TasksParams
+
+var tasksCmd = &cobra.Command{
+	Use:   "tasks",
+	Short: "List and toggle tasks (checkboxes) across the vault",
+	Long: `List the Markdown checkboxes ("- [ ] ...") in every note of the vault.
+
+Metadata written by the Obsidian Tasks plugin is understood: due (📅),
+scheduled (⏳), start (🛫), created (➕), done (✅) and cancelled (❌) dates,
+priority (🔺 ⏫ 🔼 🔽 ⏬) and recurrence (🔁). By default only open tasks
+(todo or in progress) are listed.
+
+Dates accept YYYY-MM-DD, today, tomorrow or yesterday.
+
+Examples:
+  notesmd-cli tasks
+  notesmd-cli tasks --due-before today
+  notesmd-cli tasks --status done --path Projects --tag work
+  notesmd-cli tasks toggle "Daily/2024-05-01" 12`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		vault := obsidian.Vault{Name: vaultName}
+		tasks, err := actions.ListTasks(&vault, taskParams)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(tasksResult{Tasks: tasks}, func() {
+			for _, task := range tasks {
+				fmt.Printf("%s:%d [%s] %s\n", task.Path, task.Line, task.Symbol, task.Description)
+			}
+		})
+	},
+}
+
+var tasksToggleCmd = &cobra.Command{
+	Use:   "toggle <note> <line>",
+	Short: "Marks the task on a line of a note done, or not done",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		line, err := strconv.Atoi(args[1])
+		if err != nil {
+			exitWithError(obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid line number %q", args[1])))
+		}
+
+		vault := obsidian.Vault{Name: vaultName}
+		task, err := actions.ToggleTask(&vault, args[0], line)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		printResult(taskResult{Task: task}, func() {
+			fmt.Printf("%s:%d [%s] %s\n", task.Path, task.Line, task.Symbol, task.Description)
+		})
+	},
+}
+
+func init() {
+	tasksCmd.PersistentFlags().StringVarP(&vaultName, "vault", "v", "", "vault name")
+	tasksCmd.Flags().StringVarP(&taskParams.Status, "status", "s", obsidian.TaskFilterOpen, "open, todo, in-progress, done, cancelled or all")
+	tasksCmd.Flags().StringVar(&taskParams.Due, "due", "", "only tasks due on this date")
+	tasksCmd.Flags().StringVar(&taskParams.DueBefore, "due-before", "", "only tasks due before this date")
+	tasksCmd.Flags().StringVar(&taskParams.DueAfter, "due-after", "", "only tasks due after this date")
+	tasksCmd.Flags().StringVarP(&taskParams.Path, "path", "p", "", "only tasks in this note or folder")
+	tasksCmd.Flags().StringVarP(&taskParams.Tag, "tag", "t", "", "only tasks with this tag or a nested tag")
+	tasksCmd.AddCommand(tasksToggleCmd)
+	rootCmd.AddCommand(tasksCmd)
+}
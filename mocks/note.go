@@ -5,6 +5,15 @@ import "github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 type MockNoteManager struct {
 	DeleteErr          error
 	MoveErr            error
+	MoveFolderErr      error
+	MoveFolderResult   []obsidian.FileMove
+	MoveFilesErr       error
+	MoveFilesArg       []obsidian.FileMove
+	PlanAttachmentsErr error
+	MoveAttachmentsErr error
+	MovedAttachments   []obsidian.FileMove
+	UnusedAttachmentsErr error
+	Unused             []string
 	UpdateLinksError   error
 	GetContentsError   error
 	SetContentsError   error
@@ -22,6 +31,30 @@ func (m *MockNoteManager) Move(string, string) error {
 	return m.MoveErr
 }
 
+func (m *MockNoteManager) MoveFolder(string, string, string) ([]obsidian.FileMove, error) {
+	return m.MoveFolderResult, m.MoveFolderErr
+}
+
+func (m *MockNoteManager) MoveFiles(_ string, moves []obsidian.FileMove) ([]obsidian.FileMove, error) {
+	m.MoveFilesArg = moves
+	if m.MoveFilesErr != nil {
+		return nil, m.MoveFilesErr
+	}
+	return moves, nil
+}
+
+func (m *MockNoteManager) PlanAttachmentMoves(string, string, string) ([]obsidian.FileMove, error) {
+	return m.MovedAttachments, m.PlanAttachmentsErr
+}
+
+func (m *MockNoteManager) MoveAttachments(string, string, string) ([]obsidian.FileMove, error) {
+	return m.MovedAttachments, m.MoveAttachmentsErr
+}
+
+func (m *MockNoteManager) UnusedAttachments(string, string) ([]string, error) {
+	return m.Unused, m.UnusedAttachmentsErr
+}
+
 func (m *MockNoteManager) UpdateLinks(string, string, string) error {
 	return m.UpdateLinksError
 }
@@ -54,6 +87,43 @@ func (m *MockNoteManager) SearchNotesWithSnippets(string, string) ([]obsidian.No
 	}, nil
 }
 
+func (m *MockNoteManager) SearchNotesRanked(string, string) ([]obsidian.NoteMatch, error) {
+	if m.GetContentsError != nil {
+		return nil, m.GetContentsError
+	}
+	if m.NoMatches {
+		return []obsidian.NoteMatch{}, nil
+	}
+	return []obsidian.NoteMatch{
+		{FilePath: "note2.md", LineNumber: 10, MatchLine: "another match", Score: 2.5},
+		{FilePath: "note1.md", LineNumber: 5, MatchLine: "example match line", Score: 1.5},
+	}, nil
+}
+
+func (m *MockNoteManager) SearchNotesQuery(string, string) ([]obsidian.NoteMatch, error) {
+	if m.GetContentsError != nil {
+		return nil, m.GetContentsError
+	}
+	if m.NoMatches {
+		return []obsidian.NoteMatch{}, nil
+	}
+	return []obsidian.NoteMatch{
+		{FilePath: "Projects/plan.md", LineNumber: 3, MatchLine: "- [ ] draft #todo"},
+	}, nil
+}
+
+func (m *MockNoteManager) SearchNotesRegex(string, string, obsidian.RegexSearchOptions) ([]obsidian.NoteMatch, error) {
+	if m.GetContentsError != nil {
+		return nil, m.GetContentsError
+	}
+	if m.NoMatches {
+		return []obsidian.NoteMatch{}, nil
+	}
+	return []obsidian.NoteMatch{
+		{FilePath: "note3.md", LineNumber: 2, MatchLine: "TODO(«alice») review"},
+	}, nil
+}
+
 func (m *MockNoteManager) FindBacklinks(string, string) ([]obsidian.NoteMatch, error) {
 	if m.FindBacklinksErr != nil {
 		return nil, m.FindBacklinksErr
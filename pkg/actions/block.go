@@ -0,0 +1,57 @@
+package actions
+
+import (
+	"strings"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+type BlockIDParams struct {
+	NoteName string
+	Line     int
+	// ID is the block ID to assign; empty generates one.
+	ID string
+}
+
+// BlockRef is a block of a note and the wikilink that embeds or links to it.
+type BlockRef struct {
+	Note string `json:"note"`
+	ID   string `json:"id"`
+	Link string `json:"link"`
+}
+
+// AddBlockID gives the block on a line of a note a block ID, keeping the ID
+// it already has, and returns a link to the block in the vault's link
+// format.
+func AddBlockID(vault obsidian.VaultManager, note obsidian.NoteManager, params BlockIDParams) (BlockRef, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	contents, err := note.GetContents(vaultPath, params.NoteName)
+	if err != nil {
+		return BlockRef{}, err
+	}
+
+	updated, id, err := obsidian.AddBlockID(contents, params.Line, strings.TrimPrefix(params.ID, "^"))
+	if err != nil {
+		return BlockRef{}, err
+	}
+	if updated != contents {
+		if err := note.SetContents(vaultPath, params.NoteName, updated); err != nil {
+			return BlockRef{}, err
+		}
+	}
+
+	link, err := obsidian.NoteLink(vaultPath, "", params.NoteName, "#^"+id)
+	if err != nil {
+		return BlockRef{}, err
+	}
+	return BlockRef{Note: obsidian.RemoveMdSuffix(params.NoteName), ID: id, Link: link}, nil
+}
@@ -0,0 +1,67 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/mocks"
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddBlockID(t *testing.T) {
+	t.Run("Writes the block ID and returns a link", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		notePath := filepath.Join(vaultPath, "plan.md")
+		assert.NoError(t, os.WriteFile(notePath, []byte("# Plan\n\n- ship it\n"), 0644))
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: vaultPath}
+
+		ref, err := actions.AddBlockID(&vault, &obsidian.Note{}, actions.BlockIDParams{NoteName: "plan.md", Line: 3, ID: "ship"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, actions.BlockRef{Note: "plan", ID: "ship", Link: "[[plan#^ship]]"}, ref)
+		written, _ := os.ReadFile(notePath)
+		assert.Equal(t, "# Plan\n\n- ship it ^ship\n", string(written))
+	})
+
+	t.Run("Existing block ID is kept", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(vaultPath, "plan.md"), []byte("- ship it ^abc123\n"), 0644))
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: vaultPath}
+		note := mocks.MockNoteManager{
+			Contents:         "- ship it ^abc123\n",
+			SetContentsError: errors.New("should not write"),
+		}
+
+		ref, err := actions.AddBlockID(&vault, &note, actions.BlockIDParams{NoteName: "plan", Line: 1})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "abc123", ref.ID)
+	})
+
+	t.Run("Link follows the vault's link settings", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		assert.NoError(t, os.MkdirAll(filepath.Join(vaultPath, ".obsidian"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(vaultPath, ".obsidian", "app.json"), []byte(`{"useMarkdownLinks": true, "newLinkFormat": "absolute"}`), 0644))
+		assert.NoError(t, os.MkdirAll(filepath.Join(vaultPath, "Projects"), 0755))
+		assert.NoError(t, os.WriteFile(filepath.Join(vaultPath, "Projects", "Big plan.md"), []byte("- ship it\n"), 0644))
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: vaultPath}
+
+		ref, err := actions.AddBlockID(&vault, &obsidian.Note{}, actions.BlockIDParams{NoteName: "Big plan", Line: 1, ID: "ship"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "[Big plan](Projects/Big%20plan.md#^ship)", ref.Link)
+	})
+
+	t.Run("Line is not a block", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "# Plan\n\n- ship it\n"}
+
+		_, err := actions.AddBlockID(&vault, &note, actions.BlockIDParams{NoteName: "plan", Line: 2})
+
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
@@ -4,11 +4,16 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 )
 
+// listItemPrefix matches the marker of a list item and its checkbox, if any.
+var listItemPrefix = regexp.MustCompile(`^[ \t]*(?:[-*+]|\d+[.)])[ \t]+(?:\[.\][ \t]+)?`)
+
 type CreateParams struct {
 	NoteName        string
 	ShouldAppend    bool
@@ -16,18 +21,30 @@ type CreateParams struct {
 	Content         string
 	ShouldOpen      bool
 	UseEditor       bool
+	// Template names a template whose expanded content is written before
+	// Content. It is not used when appending to an existing note.
+	Template string
+	// Vars are extra template variables.
+	Vars map[string]string
+	// Heading, with ShouldAppend, adds Content at the end of the section
+	// under this heading instead of the end of the note.
+	Heading string
+	// Timestamp prefixes appended content with the current time.
+	Timestamp bool
 }
 
-func CreateNote(vault obsidian.VaultManager, uri obsidian.UriManager, params CreateParams) error {
+// CreateNote writes the note and optionally opens it, returning the note's
+// path within the vault.
+func CreateNote(vault obsidian.VaultManager, uri obsidian.UriManager, params CreateParams) (string, error) {
 	// DefaultName populates vault name from config if not already set (required before Path()).
 	vaultName, err := vault.DefaultName()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	vaultPath, err := vault.Path()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Prepend configured default folder when note name has no explicit path.
@@ -36,26 +53,52 @@ func CreateNote(vault obsidian.VaultManager, uri obsidian.UriManager, params Cre
 	// Validate the note path stays within the vault directory.
 	notePath, err := obsidian.ValidatePath(vaultPath, obsidian.AddMdSuffix(params.NoteName))
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	// Create any intermediate directories the note path requires.
 	if err := os.MkdirAll(filepath.Dir(notePath), 0755); err != nil {
-		return fmt.Errorf("failed to create note directory: %w", err)
+		return "", obsidian.WrapError(obsidian.ErrCodeVaultWrite, fmt.Sprintf("failed to create note directory: %v", err), err)
 	}
 
 	// Write the file directly to disk — no Obsidian required.
 	normalizedContent := NormalizeContent(params.Content)
-	if err := WriteNoteFile(notePath, normalizedContent, params.ShouldAppend, params.ShouldOverwrite); err != nil {
-		return err
+	if params.ShouldAppend && params.Timestamp {
+		normalizedContent = timestampText(normalizedContent, time.Now())
+	}
+	_, statErr := os.Stat(notePath)
+	appending := params.ShouldAppend && statErr == nil
+	var templateText string
+	if params.Template != "" && !appending {
+		template, err := obsidian.ReadTemplate(vaultPath, params.Template)
+		if err != nil {
+			return "", err
+		}
+		templateText = expandTemplate(vaultPath, template, params.NoteName, time.Now(), params.Vars)
+	}
+	if params.ShouldAppend && params.Heading != "" {
+		if templateText != "" {
+			// The note is new: write the template first so the content goes
+			// under the heading, in the template's own section if it has one.
+			if err := WriteNoteFile(notePath, templateText, false, false); err != nil {
+				return "", err
+			}
+		}
+		err = appendToNote(notePath, params.Heading, normalizedContent)
+	} else {
+		err = WriteNoteFile(notePath, templateText+normalizedContent, params.ShouldAppend, params.ShouldOverwrite)
+	}
+	if err != nil {
+		return "", err
 	}
 
+	relPath := obsidian.AddMdSuffix(params.NoteName)
 	if !params.ShouldOpen {
-		return nil
+		return relPath, nil
 	}
 
 	if params.UseEditor {
-		return obsidian.OpenInEditor(notePath)
+		return relPath, obsidian.OpenInEditor(notePath)
 	}
 
 	// Open the note in Obsidian via URI.
@@ -63,7 +106,7 @@ func CreateNote(vault obsidian.VaultManager, uri obsidian.UriManager, params Cre
 		"vault": vaultName,
 		"file":  params.NoteName,
 	})
-	return uri.Execute(obsidianUri)
+	return relPath, uri.Execute(obsidianUri)
 }
 
 // WriteNoteFile writes content to notePath, respecting append/overwrite semantics.
@@ -75,7 +118,7 @@ func WriteNoteFile(notePath, content string, shouldAppend, shouldOverwrite bool)
 	if fileExists && shouldAppend {
 		f, err := os.OpenFile(notePath, os.O_APPEND|os.O_WRONLY, 0644)
 		if err != nil {
-			return fmt.Errorf("failed to open note for appending: %w", err)
+			return obsidian.WrapError(obsidian.ErrCodeVaultWrite, fmt.Sprintf("failed to open note for appending: %v", err), err)
 		}
 		if _, err = f.WriteString(content); err != nil {
 			f.Close()
@@ -92,6 +135,57 @@ func WriteNoteFile(notePath, content string, shouldAppend, shouldOverwrite bool)
 	return os.WriteFile(notePath, []byte(content), 0644)
 }
 
+// appendToNote adds text as new lines at the end of the section under
+// heading, or of the note when heading is empty, creating the note and the
+// heading if needed.
+func appendToNote(notePath, heading, text string) error {
+	content, err := os.ReadFile(notePath)
+	if err != nil && !os.IsNotExist(err) {
+		return obsidian.WrapError(obsidian.ErrCodeVaultRead, fmt.Sprintf("failed to read note: %v", err), err)
+	}
+	updated := obsidian.AppendToSection(string(content), heading, text)
+	if err := os.WriteFile(notePath, []byte(updated), 0644); err != nil {
+		return obsidian.WrapError(obsidian.ErrCodeVaultWrite, fmt.Sprintf("failed to write note: %v", err), err)
+	}
+	return nil
+}
+
+// timestampText prefixes text with the time of now as HH:mm, after the
+// list marker or checkbox if text is a list item, e.g. "- 14:05 met Ann".
+func timestampText(text string, now time.Time) string {
+	stamp := now.Format("15:04")
+	if m := listItemPrefix.FindString(text); m != "" {
+		return m + stamp + " " + text[len(m):]
+	}
+	return stamp + " " + text
+}
+
+// expandTemplate fills in a template for the note noteName using the date and
+// time formats configured for Obsidian's core Templates plugin.
+func expandTemplate(vaultPath, template, noteName string, now time.Time, vars map[string]string) string {
+	config := obsidian.ReadTemplatesConfig(vaultPath)
+	return obsidian.ExpandTemplate(template, obsidian.TemplateData{
+		Title:      obsidian.NoteTitle(noteName),
+		Now:        now,
+		DateFormat: config.DateFormat,
+		TimeFormat: config.TimeFormat,
+		Vars:       vars,
+	})
+}
+
+// ParseTemplateVars parses key=value pairs as given to --var.
+func ParseTemplateVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return nil, obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid template variable %q: expected key=value", pair))
+		}
+		vars[strings.TrimSpace(key)] = value
+	}
+	return vars, nil
+}
+
 func NormalizeContent(content string) string {
 	replacer := strings.NewReplacer(
 		"\\n", "\n",
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Yakitrak/notesmd-cli/mocks"
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,11 +19,12 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		notePath, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName: "note",
 		})
 		// Assert
 		assert.NoError(t, err)
+		assert.Equal(t, "note.md", notePath)
 		assert.FileExists(t, filepath.Join(tmpDir, "note.md"))
 	})
 
@@ -32,7 +34,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName: "note",
 			Content:  "hello world",
 		})
@@ -48,7 +50,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName: "folder/note",
 		})
 		// Assert
@@ -64,7 +66,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName: "note",
 			Content:  "new content",
 		})
@@ -82,7 +84,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName:        "note",
 			Content:         "overwritten",
 			ShouldOverwrite: true,
@@ -101,7 +103,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName:     "note",
 			Content:      " appended",
 			ShouldAppend: true,
@@ -118,7 +120,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName:   "note",
 			ShouldOpen: true,
 			UseEditor:  false,
@@ -138,7 +140,7 @@ func TestCreateNote(t *testing.T) {
 		os.Setenv("EDITOR", "true")
 
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName:   "note",
 			ShouldOpen: true,
 			UseEditor:  true,
@@ -153,7 +155,7 @@ func TestCreateNote(t *testing.T) {
 			DefaultNameErr: errors.New("Failed to get vault name"),
 		}
 		// Act
-		err := actions.CreateNote(&vault, &mocks.MockUriManager{}, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &mocks.MockUriManager{}, actions.CreateParams{
 			NoteName: "note-name",
 		})
 		// Assert
@@ -167,7 +169,7 @@ func TestCreateNote(t *testing.T) {
 			PathError: errors.New("Failed to get vault path"),
 		}
 		// Act
-		err := actions.CreateNote(&vault, &mocks.MockUriManager{}, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &mocks.MockUriManager{}, actions.CreateParams{
 			NoteName: "note-name",
 		})
 		// Assert
@@ -182,7 +184,7 @@ func TestCreateNote(t *testing.T) {
 			ExecuteErr: errors.New("Failed to execute URI"),
 		}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName:   "note-name",
 			ShouldOpen: true,
 			UseEditor:  false,
@@ -202,7 +204,7 @@ func TestCreateNote(t *testing.T) {
 		os.Setenv("EDITOR", "false")
 
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName:   "note",
 			ShouldOpen: true,
 			UseEditor:  true,
@@ -224,7 +226,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName: "note",
 			Content:  "hello",
 		})
@@ -246,7 +248,7 @@ func TestCreateNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 		// Act
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName: "sub/note",
 			Content:  "hello",
 		})
@@ -257,6 +259,105 @@ func TestCreateNote(t *testing.T) {
 		assert.NoFileExists(t, filepath.Join(tmpDir, "Inbox", "sub", "note.md"))
 	})
 
+	t.Run("Fills in a template before the content", func(t *testing.T) {
+		// Arrange
+		tmpDir := t.TempDir()
+		os.MkdirAll(filepath.Join(tmpDir, ".obsidian"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, ".obsidian", "templates.json"), []byte(`{"folder": "Templates"}`), 0644)
+		os.MkdirAll(filepath.Join(tmpDir, "Templates"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, "Templates", "Meeting.md"), []byte("# {{title}}\nWith {{who}}\n"), 0644)
+
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		uri := mocks.MockUriManager{}
+		// Act
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+			NoteName: "Notes/Standup",
+			Content:  "notes",
+			Template: "Meeting",
+			Vars:     map[string]string{"who": "Ann"},
+		})
+		// Assert
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(tmpDir, "Notes", "Standup.md"))
+		assert.Equal(t, "# Standup\nWith Ann\nnotes", string(content))
+	})
+
+	t.Run("Template is only used when appending creates the note", func(t *testing.T) {
+		// Arrange
+		tmpDir := t.TempDir()
+		os.WriteFile(filepath.Join(tmpDir, "Log.md"), []byte("# {{title}}\n"), 0644)
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		params := actions.CreateParams{
+			NoteName:     "today",
+			Content:      "- first\n",
+			ShouldAppend: true,
+			Template:     "Log",
+			Timestamp:    true,
+		}
+		// Act
+		_, err := actions.CreateNote(&vault, &mocks.MockUriManager{}, params)
+		assert.NoError(t, err)
+		params.Content = "- second"
+		_, err = actions.CreateNote(&vault, &mocks.MockUriManager{}, params)
+		// Assert
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(tmpDir, "today.md"))
+		assert.Regexp(t, `^# today\n- \d{2}:\d{2} first\n- \d{2}:\d{2} second$`, string(content))
+	})
+
+	t.Run("Appending under a heading creates the note from the template first", func(t *testing.T) {
+		// Arrange
+		tmpDir := t.TempDir()
+		os.WriteFile(filepath.Join(tmpDir, "T.md"), []byte("# {{title}}\n\n## Log\n\n## Notes\n"), 0644)
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		// Act
+		_, err := actions.CreateNote(&vault, &mocks.MockUriManager{}, actions.CreateParams{
+			NoteName:     "new",
+			Content:      "- x",
+			ShouldAppend: true,
+			Heading:      "## Log",
+			Template:     "T",
+			Timestamp:    true,
+		})
+		// Assert
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(tmpDir, "new.md"))
+		assert.Regexp(t, `^# new\n\n## Log\n- \d{2}:\d{2} x\n\n## Notes\n$`, string(content))
+	})
+
+	t.Run("Missing template returns an error", func(t *testing.T) {
+		// Arrange
+		tmpDir := t.TempDir()
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		// Act
+		_, err := actions.CreateNote(&vault, &mocks.MockUriManager{}, actions.CreateParams{
+			NoteName: "note",
+			Template: "Missing",
+		})
+		// Assert
+		assert.Equal(t, obsidian.ErrCodeTemplateNotFound, obsidian.ErrorCode(err))
+		assert.NoFileExists(t, filepath.Join(tmpDir, "note.md"))
+	})
+
+	t.Run("Appends under a heading", func(t *testing.T) {
+		// Arrange
+		tmpDir := t.TempDir()
+		os.WriteFile(filepath.Join(tmpDir, "note.md"), []byte("# Note\n\n## Log\n- a\n\n## End\n"), 0644)
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		// Act
+		_, err := actions.CreateNote(&vault, &mocks.MockUriManager{}, actions.CreateParams{
+			NoteName:     "note",
+			Content:      "- b",
+			ShouldAppend: true,
+			Heading:      "## Log",
+			Timestamp:    true,
+		})
+		// Assert
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(tmpDir, "note.md"))
+		assert.Regexp(t, `^# Note\n\n## Log\n- a\n- \d{2}:\d{2} b\n\n## End\n$`, string(content))
+	})
+
 	t.Run("UseEditor without open does not use editor", func(t *testing.T) {
 		// Arrange
 		tmpDir := t.TempDir()
@@ -264,7 +365,7 @@ func TestCreateNote(t *testing.T) {
 		uri := mocks.MockUriManager{}
 
 		// Act — UseEditor is true but ShouldOpen is false
-		err := actions.CreateNote(&vault, &uri, actions.CreateParams{
+		_, err := actions.CreateNote(&vault, &uri, actions.CreateParams{
 			NoteName:   "note",
 			ShouldOpen: false,
 			UseEditor:  true,
@@ -275,6 +376,21 @@ func TestCreateNote(t *testing.T) {
 	})
 }
 
+func TestParseTemplateVars(t *testing.T) {
+	t.Run("Parses key=value pairs", func(t *testing.T) {
+		vars, err := actions.ParseTemplateVars([]string{"project=Apollo", "note=a=b", "empty="})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]string{"project": "Apollo", "note": "a=b", "empty": ""}, vars)
+	})
+
+	t.Run("Rejects pairs without a key", func(t *testing.T) {
+		_, err := actions.ParseTemplateVars([]string{"project"})
+		assert.EqualError(t, err, `invalid template variable "project": expected key=value`)
+		_, err = actions.ParseTemplateVars([]string{"=value"})
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
+
 func TestNormalizeContent(t *testing.T) {
 	t.Run("Replaces escape sequences with actual characters", func(t *testing.T) {
 		// Arrange
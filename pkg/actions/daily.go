@@ -10,65 +10,202 @@ import (
 )
 
 type DailyParams struct {
+	// Date is the day of the note, as accepted by obsidian.ResolveDate.
+	// Empty means today.
+	Date string
+	// Offset moves the day by a number of days.
+	Offset int
+	// Append is text to add to the note instead of opening it, at the end
+	// of the section under Heading if set.
+	Append  string
+	Heading string
+	// Timestamp prefixes the appended text with the current time.
+	Timestamp bool
 	UseEditor bool
 }
 
-func DailyNote(vault obsidian.VaultManager, uri obsidian.UriManager, params DailyParams) error {
+type DailyListParams struct {
+	// From and To limit the notes to a range of days, as accepted by
+	// obsidian.ResolveDate. Empty means no limit.
+	From string
+	To   string
+}
+
+// DailyNote creates the daily note for the requested day (today by default)
+// if needed and opens it, or appends text to it, returning the note's path
+// within the vault.
+func DailyNote(vault obsidian.VaultManager, uri obsidian.UriManager, params DailyParams) (string, error) {
+	date, err := dailyDate(params.Date, params.Offset, time.Now())
+	if err != nil {
+		return "", err
+	}
+
 	vaultName, err := vault.DefaultName()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	vaultPath, err := vault.Path()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	config := obsidian.ReadDailyNotesConfig(vaultPath)
+	note := periodicNote{
+		folder:   config.Folder,
+		format:   dailyFormat(config),
+		template: config.Template,
+		date:     date,
+	}
 
-	// Format today's date using the configured Moment.js format.
-	format := config.Format
-	if format == "" {
-		format = "YYYY-MM-DD"
+	if params.Append == "" {
+		return openPeriodicNote(vaultName, vaultPath, uri, note, params.UseEditor)
 	}
-	noteName := time.Now().Format(obsidian.MomentToGoFormat(format))
 
-	// Prepend configured daily notes folder.
-	if config.Folder != "" {
-		noteName = config.Folder + "/" + noteName
+	noteName, notePath, err := createPeriodicNote(vaultPath, note)
+	if err != nil {
+		return "", err
+	}
+	text := NormalizeContent(params.Append)
+	if params.Timestamp {
+		text = timestampText(text, time.Now())
 	}
+	if err := appendToNote(notePath, params.Heading, text); err != nil {
+		return "", err
+	}
+	return obsidian.AddMdSuffix(noteName), nil
+}
 
-	notePath, err := obsidian.ValidatePath(vaultPath, obsidian.AddMdSuffix(noteName))
+// ListDailyNotes returns the existing daily notes, oldest first, found by
+// reading their names back with the configured date format.
+func ListDailyNotes(vault obsidian.VaultManager, params DailyListParams) ([]obsidian.DatedNote, error) {
+	now := time.Now()
+	var from, to string
+	if params.From != "" {
+		date, err := obsidian.ResolveDate(params.From, now)
+		if err != nil {
+			return nil, err
+		}
+		from = date.Format("2006-01-02")
+	}
+	if params.To != "" {
+		date, err := obsidian.ResolveDate(params.To, now)
+		if err != nil {
+			return nil, err
+		}
+		to = date.Format("2006-01-02")
+	}
+
+	_, err := vault.DefaultName()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := os.MkdirAll(filepath.Dir(notePath), 0755); err != nil {
-		return fmt.Errorf("failed to create daily note directory: %w", err)
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
 	}
 
-	// Read template content if configured.
-	content := ""
-	if config.Template != "" {
-		templatePath := filepath.Join(vaultPath, obsidian.AddMdSuffix(config.Template))
-		if templateContent, readErr := os.ReadFile(templatePath); readErr == nil {
-			content = string(templateContent)
+	config := obsidian.ReadDailyNotesConfig(vaultPath)
+	notes, err := obsidian.ListDatedNotes(vaultPath, config.Folder, dailyFormat(config))
+	if err != nil {
+		return nil, err
+	}
+
+	// Dates are YYYY-MM-DD, so they compare as strings.
+	filtered := notes[:0]
+	for _, note := range notes {
+		if (from == "" || note.Date >= from) && (to == "" || note.Date <= to) {
+			filtered = append(filtered, note)
 		}
 	}
+	return filtered, nil
+}
 
-	// WriteNoteFile leaves existing files unchanged (no append/overwrite).
-	if err := WriteNoteFile(notePath, content, false, false); err != nil {
-		return err
+// dailyDate returns the moment a daily note is created for: the day given
+// by phrase (today when empty) moved by offset days, at the current time of
+// day so that {{time}} in templates stays meaningful.
+func dailyDate(phrase string, offset int, now time.Time) (time.Time, error) {
+	day := now
+	if phrase != "" {
+		resolved, err := obsidian.ResolveDate(phrase, now)
+		if err != nil {
+			return time.Time{}, err
+		}
+		day = resolved
+	}
+	day = day.AddDate(0, 0, offset)
+	return time.Date(day.Year(), day.Month(), day.Day(), now.Hour(), now.Minute(), now.Second(), now.Nanosecond(), now.Location()), nil
+}
+
+// dailyFormat returns the configured Moment.js format of daily note names.
+func dailyFormat(config obsidian.DailyNotesConfig) string {
+	if config.Format == "" {
+		return "YYYY-MM-DD"
+	}
+	return config.Format
+}
+
+// periodicNote describes the note of a period, such as a day or a week.
+type periodicNote struct {
+	folder   string
+	format   string
+	template string
+	date     time.Time
+}
+
+// openPeriodicNote creates the periodic note from its template if it does
+// not exist yet and opens it, returning the note's path within the vault.
+func openPeriodicNote(vaultName, vaultPath string, uri obsidian.UriManager, note periodicNote, useEditor bool) (string, error) {
+	noteName, notePath, err := createPeriodicNote(vaultPath, note)
+	if err != nil {
+		return "", err
 	}
 
 	// Open the note.
-	if params.UseEditor {
-		return obsidian.OpenInEditor(notePath)
+	relPath := obsidian.AddMdSuffix(noteName)
+	if useEditor {
+		return relPath, obsidian.OpenInEditor(notePath)
 	}
 
 	obsidianUri := uri.Construct(ObsOpenUrl, map[string]string{
 		"vault": vaultName,
 		"file":  noteName,
 	})
-	return uri.Execute(obsidianUri)
+	return relPath, uri.Execute(obsidianUri)
+}
+
+// createPeriodicNote creates the periodic note from its template if it does
+// not exist yet, returning its name within the vault and its path on disk.
+func createPeriodicNote(vaultPath string, note periodicNote) (string, string, error) {
+	noteName := obsidian.FormatMoment(note.date, note.format)
+
+	// Prepend configured notes folder.
+	if note.folder != "" {
+		noteName = note.folder + "/" + noteName
+	}
+
+	notePath, err := obsidian.ValidatePath(vaultPath, obsidian.AddMdSuffix(noteName))
+	if err != nil {
+		return "", "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(notePath), 0755); err != nil {
+		return "", "", obsidian.WrapError(obsidian.ErrCodeVaultWrite, fmt.Sprintf("failed to create periodic note directory: %v", err), err)
+	}
+
+	// Read and expand template content if configured.
+	content := ""
+	if note.template != "" {
+		templatePath := filepath.Join(vaultPath, obsidian.AddMdSuffix(note.template))
+		if templateContent, readErr := os.ReadFile(templatePath); readErr == nil {
+			content = expandTemplate(vaultPath, string(templateContent), noteName, note.date, nil)
+		}
+	}
+
+	// WriteNoteFile leaves existing files unchanged (no append/overwrite).
+	if err := WriteNoteFile(notePath, content, false, false); err != nil {
+		return "", "", err
+	}
+	return noteName, notePath, nil
 }
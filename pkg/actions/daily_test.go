@@ -21,8 +21,9 @@ func TestDailyNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		notePath, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
 		assert.NoError(t, err)
+		assert.Equal(t, today+".md", notePath)
 		assert.FileExists(t, filepath.Join(tmpDir, today+".md"))
 	})
 
@@ -37,7 +38,7 @@ func TestDailyNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
 		assert.NoError(t, err)
 		assert.FileExists(t, filepath.Join(tmpDir, "Daily", today+".md"))
 	})
@@ -57,13 +58,38 @@ func TestDailyNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
 		assert.NoError(t, err)
 
 		content, _ := os.ReadFile(filepath.Join(tmpDir, today+".md"))
 		assert.Equal(t, "# Daily Note\n- [ ] Task", string(content))
 	})
 
+	t.Run("Expands template variables", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		obsDir := filepath.Join(tmpDir, ".obsidian")
+		os.MkdirAll(obsDir, 0755)
+		os.WriteFile(filepath.Join(obsDir, "daily-notes.json"), []byte(`{
+			"folder": "Daily",
+			"template": "Templates/Daily"
+		}`), 0644)
+		os.WriteFile(filepath.Join(obsDir, "templates.json"), []byte(`{
+			"dateFormat": "YYYY/MM/DD"
+		}`), 0644)
+		os.MkdirAll(filepath.Join(tmpDir, "Templates"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, "Templates", "Daily.md"), []byte("# {{title}}\n{{date}} {{date:YYYY}}"), 0644)
+
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		uri := mocks.MockUriManager{}
+
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		assert.NoError(t, err)
+
+		now := time.Now()
+		content, _ := os.ReadFile(filepath.Join(tmpDir, "Daily", today+".md"))
+		assert.Equal(t, "# "+today+"\n"+now.Format("2006/01/02")+" "+now.Format("2006"), string(content))
+	})
+
 	t.Run("Does not overwrite existing daily note", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		notePath := filepath.Join(tmpDir, today+".md")
@@ -72,7 +98,7 @@ func TestDailyNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
 		assert.NoError(t, err)
 
 		content, _ := os.ReadFile(notePath)
@@ -84,7 +110,7 @@ func TestDailyNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
 		assert.NoError(t, err)
 		assert.Equal(t, "myVault", uri.LastParams["vault"])
 		assert.Equal(t, today, uri.LastParams["file"])
@@ -99,7 +125,7 @@ func TestDailyNote(t *testing.T) {
 		defer os.Setenv("EDITOR", originalEditor)
 		os.Setenv("EDITOR", "true")
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{UseEditor: true})
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{UseEditor: true})
 		assert.NoError(t, err)
 	})
 
@@ -108,7 +134,7 @@ func TestDailyNote(t *testing.T) {
 		vaultOp := &mocks.MockVaultOperator{
 			DefaultNameErr: vaultDefaultNameErr,
 		}
-		err := actions.DailyNote(vaultOp, &mocks.MockUriManager{}, actions.DailyParams{})
+		_, err := actions.DailyNote(vaultOp, &mocks.MockUriManager{}, actions.DailyParams{})
 		assert.Error(t, err, vaultDefaultNameErr)
 	})
 
@@ -117,7 +143,7 @@ func TestDailyNote(t *testing.T) {
 			Name:      "myVault",
 			PathError: errors.New("path error"),
 		}
-		err := actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{})
+		_, err := actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{})
 		assert.Equal(t, vault.PathError, err)
 	})
 
@@ -127,7 +153,7 @@ func TestDailyNote(t *testing.T) {
 		uri := mocks.MockUriManager{
 			ExecuteErr: errors.New("Failed to execute URI"),
 		}
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
 		assert.Equal(t, uri.ExecuteErr, err)
 	})
 
@@ -142,10 +168,139 @@ func TestDailyNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
 		uri := mocks.MockUriManager{}
 
-		err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
+		_, err := actions.DailyNote(&vault, &uri, actions.DailyParams{})
 		assert.NoError(t, err)
 
 		expectedName := time.Now().Format(obsidian.MomentToGoFormat("DD-MM-YYYY"))
 		assert.FileExists(t, filepath.Join(tmpDir, expectedName+".md"))
 	})
 }
+
+func TestDailyNoteForOtherDays(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Creates the note for a given date", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+
+		notePath, err := actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{Date: "2026-10-01"})
+		assert.NoError(t, err)
+		assert.Equal(t, "2026-10-01.md", notePath)
+	})
+
+	t.Run("Applies the offset to the date", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+
+		notePath, err := actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{Date: "2026-10-01", Offset: -3})
+		assert.NoError(t, err)
+		assert.Equal(t, "2026-09-28.md", notePath)
+
+		notePath, err = actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{Offset: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, now.AddDate(0, 0, 1).Format("2006-01-02")+".md", notePath)
+	})
+
+	t.Run("Template dates refer to the note's day", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		obsDir := filepath.Join(tmpDir, ".obsidian")
+		os.MkdirAll(obsDir, 0755)
+		os.WriteFile(filepath.Join(obsDir, "daily-notes.json"), []byte(`{"template": "Daily"}`), 0644)
+		os.WriteFile(filepath.Join(tmpDir, "Daily.md"), []byte("{{date:dddd D MMMM}}"), 0644)
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+
+		_, err := actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{Date: "yesterday"})
+		assert.NoError(t, err)
+
+		yesterday := now.AddDate(0, 0, -1)
+		content, _ := os.ReadFile(filepath.Join(tmpDir, yesterday.Format("2006-01-02")+".md"))
+		assert.Equal(t, yesterday.Format("Monday 2 January"), string(content))
+	})
+
+	t.Run("Invalid date", func(t *testing.T) {
+		_, err := actions.DailyNote(&mocks.MockVaultOperator{}, &mocks.MockUriManager{}, actions.DailyParams{Date: "someday"})
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
+
+func TestListDailyNotes(t *testing.T) {
+	tmpDir := t.TempDir()
+	obsDir := filepath.Join(tmpDir, ".obsidian")
+	os.MkdirAll(obsDir, 0755)
+	os.WriteFile(filepath.Join(obsDir, "daily-notes.json"), []byte(`{"folder": "Journal", "format": "DD-MM-YYYY"}`), 0644)
+	os.MkdirAll(filepath.Join(tmpDir, "Journal"), 0755)
+	for _, name := range []string{"01-10-2026", "15-09-2026", "30-09-2026", "Ideas"} {
+		os.WriteFile(filepath.Join(tmpDir, "Journal", name+".md"), nil, 0644)
+	}
+	vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+
+	t.Run("Lists notes oldest first", func(t *testing.T) {
+		notes, err := actions.ListDailyNotes(&vault, actions.DailyListParams{})
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.DatedNote{
+			{Date: "2026-09-15", Path: "Journal/15-09-2026.md"},
+			{Date: "2026-09-30", Path: "Journal/30-09-2026.md"},
+			{Date: "2026-10-01", Path: "Journal/01-10-2026.md"},
+		}, notes)
+	})
+
+	t.Run("Limits notes to a range", func(t *testing.T) {
+		notes, err := actions.ListDailyNotes(&vault, actions.DailyListParams{From: "2026-09-16", To: "2026-09-30"})
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.DatedNote{{Date: "2026-09-30", Path: "Journal/30-09-2026.md"}}, notes)
+	})
+
+	t.Run("Invalid range", func(t *testing.T) {
+		_, err := actions.ListDailyNotes(&vault, actions.DailyListParams{From: "soon"})
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+
+	t.Run("vault.Path returns an error", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault", PathError: errors.New("path error")}
+		_, err := actions.ListDailyNotes(&vault, actions.DailyListParams{})
+		assert.Equal(t, vault.PathError, err)
+	})
+}
+
+func TestDailyNoteAppend(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+
+	t.Run("Appends under a heading without opening the note", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		notePath := filepath.Join(tmpDir, today+".md")
+		os.WriteFile(notePath, []byte("## Log\n- first\n\n## Tasks\n"), 0644)
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		uri := mocks.MockUriManager{}
+
+		relPath, err := actions.DailyNote(&vault, &uri, actions.DailyParams{Append: "- second", Heading: "## Log"})
+		assert.NoError(t, err)
+		assert.Equal(t, today+".md", relPath)
+		assert.Nil(t, uri.LastParams)
+		content, _ := os.ReadFile(notePath)
+		assert.Equal(t, "## Log\n- first\n- second\n\n## Tasks\n", string(content))
+	})
+
+	t.Run("Creates the note from its template first", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		obsDir := filepath.Join(tmpDir, ".obsidian")
+		os.MkdirAll(obsDir, 0755)
+		os.WriteFile(filepath.Join(obsDir, "daily-notes.json"), []byte(`{"template": "Daily"}`), 0644)
+		os.WriteFile(filepath.Join(tmpDir, "Daily.md"), []byte("# {{title}}\n"), 0644)
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+
+		_, err := actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{Append: "- met with X\\nand Y", Heading: "Log"})
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(tmpDir, today+".md"))
+		assert.Equal(t, "# "+today+"\n\n## Log\n- met with X\nand Y\n", string(content))
+	})
+
+	t.Run("Prefixes the text with a timestamp", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+
+		_, err := actions.DailyNote(&vault, &mocks.MockUriManager{}, actions.DailyParams{Append: "- [ ] call", Timestamp: true})
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(tmpDir, today+".md"))
+		assert.Regexp(t, `^- \[ \] \d{2}:\d{2} call\n$`, string(content))
+	})
+}
@@ -1,33 +1,66 @@
 package actions
 
 import (
+	"path/filepath"
+
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 )
 
 type DeleteParams struct {
 	NotePath string
+	// DeleteAttachments also deletes the attachments the note links to that
+	// no other note links to.
+	DeleteAttachments bool
+}
+
+// DeleteResult describes a completed delete: the vault-relative path of the
+// note and of each attachment deleted with it.
+type DeleteResult struct {
+	Deleted     string
+	Attachments []string
 }
 
-func DeleteNote(vault obsidian.VaultManager, note obsidian.NoteManager, params DeleteParams) error {
+func DeleteNote(vault obsidian.VaultManager, note obsidian.NoteManager, params DeleteParams) (DeleteResult, error) {
 	_, err := vault.DefaultName()
 	if err != nil {
-		return err
+		return DeleteResult{}, err
 	}
 
 	vaultPath, err := vault.Path()
 	if err != nil {
-		return err
+		return DeleteResult{}, err
 	}
 
 	// Validate path stays within vault directory
 	notePath, err := obsidian.ValidatePath(vaultPath, params.NotePath)
 	if err != nil {
-		return err
+		return DeleteResult{}, err
+	}
+
+	result := DeleteResult{Deleted: filepath.ToSlash(obsidian.AddMdSuffix(params.NotePath))}
+	if obsidian.IsAttachment(notePath) {
+		result.Deleted = filepath.ToSlash(params.NotePath)
+	} else if params.DeleteAttachments {
+		// Unused attachments must be found while the note still links to them.
+		result.Attachments, err = note.UnusedAttachments(vaultPath, params.NotePath)
+		if err != nil {
+			return DeleteResult{}, err
+		}
 	}
 
 	err = note.Delete(notePath)
 	if err != nil {
-		return err
+		return DeleteResult{}, err
+	}
+
+	for _, attachment := range result.Attachments {
+		attachmentPath, err := obsidian.ValidatePath(vaultPath, attachment)
+		if err != nil {
+			return DeleteResult{}, err
+		}
+		if err := note.Delete(attachmentPath); err != nil {
+			return DeleteResult{}, err
+		}
 	}
-	return nil
+	return result, nil
 }
@@ -14,11 +14,40 @@ func TestDeleteNote(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault"}
 		note := mocks.MockNoteManager{}
 		// Act
-		err := actions.DeleteNote(&vault, &note, actions.DeleteParams{
+		result, err := actions.DeleteNote(&vault, &note, actions.DeleteParams{
 			NotePath: "noteToDelete",
 		})
 		// Assert
 		assert.NoError(t, err, "Expected no error")
+		assert.Equal(t, actions.DeleteResult{Deleted: "noteToDelete.md"}, result)
+	})
+
+	t.Run("Delete note with its unused attachments", func(t *testing.T) {
+		// Arrange
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Unused: []string{"assets/a.png", "b.pdf"}}
+		// Act
+		result, err := actions.DeleteNote(&vault, &note, actions.DeleteParams{
+			NotePath:          "noteToDelete",
+			DeleteAttachments: true,
+		})
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, actions.DeleteResult{Deleted: "noteToDelete.md", Attachments: []string{"assets/a.png", "b.pdf"}}, result)
+	})
+
+	t.Run("note.UnusedAttachments returns an error", func(t *testing.T) {
+		// Arrange
+		note := mocks.MockNoteManager{
+			UnusedAttachmentsErr: errors.New("Could not read notes"),
+		}
+		// Act
+		_, err := actions.DeleteNote(&mocks.MockVaultOperator{}, &note, actions.DeleteParams{
+			NotePath:          "noteToDelete",
+			DeleteAttachments: true,
+		})
+		// Assert
+		assert.Equal(t, note.UnusedAttachmentsErr, err)
 	})
 
 	t.Run("vault.DefaultName returns an error", func(t *testing.T) {
@@ -27,7 +56,7 @@ func TestDeleteNote(t *testing.T) {
 			DefaultNameErr: errors.New("Failed to get default vault name"),
 		}
 		// Act
-		err := actions.DeleteNote(&vault, &mocks.MockNoteManager{}, actions.DeleteParams{
+		_, err := actions.DeleteNote(&vault, &mocks.MockNoteManager{}, actions.DeleteParams{
 			NotePath: "noteToDelete",
 		})
 		// Assert
@@ -40,7 +69,7 @@ func TestDeleteNote(t *testing.T) {
 			PathError: errors.New("Failed to get vault path"),
 		}
 		// Act
-		err := actions.DeleteNote(&vault, &mocks.MockNoteManager{}, actions.DeleteParams{
+		_, err := actions.DeleteNote(&vault, &mocks.MockNoteManager{}, actions.DeleteParams{
 			NotePath: "noteToDelete",
 		})
 		// Assert
@@ -53,7 +82,7 @@ func TestDeleteNote(t *testing.T) {
 			DeleteErr: errors.New("Could not delete"),
 		}
 		// Act
-		err := actions.DeleteNote(&mocks.MockVaultOperator{}, &note, actions.DeleteParams{
+		_, err := actions.DeleteNote(&mocks.MockVaultOperator{}, &note, actions.DeleteParams{
 			NotePath: "noteToDelete",
 		})
 		// Assert
@@ -1,8 +1,8 @@
 package actions
 
 import (
-	"errors"
 	"fmt"
+	"time"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
@@ -13,8 +13,12 @@ type FrontmatterParams struct {
 	Print    bool
 	Edit     bool
 	Delete   bool
+	Rename   bool
+	Append   bool
+	Remove   bool
 	Key      string
 	Value    string
+	NewKey   string
 }
 
 // Frontmatter handles viewing and modifying note frontmatter.
@@ -50,7 +54,117 @@ func Frontmatter(vault obsidian.VaultManager, note obsidian.NoteManager, params
 		return handleDelete(note, vaultPath, params.NoteName, contents, params.Key)
 	}
 
-	return "", errors.New("no operation specified: use --print, --edit, or --delete")
+	// Handle rename operation
+	if params.Rename {
+		return handleRename(note, vaultPath, params.NoteName, contents, params.Key, params.NewKey)
+	}
+
+	// Handle append operation
+	if params.Append {
+		return handleAppend(note, vaultPath, params.NoteName, contents, params.Key, params.Value)
+	}
+
+	// Handle remove operation
+	if params.Remove {
+		return handleRemove(note, vaultPath, params.NoteName, contents, params.Key, params.Value)
+	}
+
+	return "", obsidian.NewError(obsidian.ErrCodeInvalidArgument, "no operation specified: use --print, --edit, --delete, --rename, --append, or --remove")
+}
+
+type BulkFrontmatterParams struct {
+	Selector obsidian.NoteSelector
+	Edit     bool
+	Delete   bool
+	Rename   bool
+	Append   bool
+	Remove   bool
+	Key      string
+	Value    string
+	NewKey   string
+	DryRun   bool
+}
+
+// BulkFrontmatter applies one frontmatter operation to every note matched by
+// the selector and returns the notes it changed, each with a diff. With
+// DryRun set the diffs are returned but nothing is written.
+func BulkFrontmatter(vault obsidian.VaultManager, params BulkFrontmatterParams) ([]obsidian.FrontmatterChange, error) {
+	if params.Selector.IsEmpty() {
+		return nil, obsidian.NewError(obsidian.ErrCodeInvalidArgument, "no notes selected: use --glob, --folder, --tag, or --where")
+	}
+
+	edit := obsidian.FrontmatterEdit{Key: params.Key, Value: params.Value, NewKey: params.NewKey}
+	switch {
+	case params.Edit:
+		edit.Operation = obsidian.FrontmatterSet
+	case params.Delete:
+		edit.Operation = obsidian.FrontmatterDelete
+	case params.Rename:
+		edit.Operation = obsidian.FrontmatterRename
+	case params.Append:
+		edit.Operation = obsidian.FrontmatterAppend
+	case params.Remove:
+		edit.Operation = obsidian.FrontmatterRemove
+	default:
+		return nil, obsidian.NewError(obsidian.ErrCodeInvalidArgument, "no operation specified: use --edit, --delete, --rename, --append, or --remove")
+	}
+	if err := validateFrontmatterEdit(edit); err != nil {
+		return nil, err
+	}
+
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	return obsidian.EditFrontmatter(vaultPath, params.Selector, edit, params.DryRun, time.Now())
+}
+
+func validateFrontmatterEdit(edit obsidian.FrontmatterEdit) error {
+	if edit.Key == "" {
+		return obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("--key is required for %s operation", edit.Operation))
+	}
+	if (edit.Operation == obsidian.FrontmatterSet || edit.Operation == obsidian.FrontmatterAppend || edit.Operation == obsidian.FrontmatterRemove) && edit.Value == "" {
+		return obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("--value is required for %s operation", edit.Operation))
+	}
+	if edit.Operation == obsidian.FrontmatterRename && edit.NewKey == "" {
+		return obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--new-key is required for rename operation")
+	}
+	return nil
+}
+
+// ReadFrontmatter returns a note's frontmatter as JSON-compatible values, or
+// an empty map when the note has none.
+func ReadFrontmatter(vault obsidian.VaultManager, note obsidian.NoteManager, noteName string) (map[string]interface{}, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	contents, err := note.GetContents(vaultPath, noteName)
+	if err != nil {
+		return nil, err
+	}
+
+	if !frontmatter.HasFrontmatter(contents) {
+		return map[string]interface{}{}, nil
+	}
+
+	fm, _, err := frontmatter.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+	return obsidian.JSONSafeMap(fm), nil
 }
 
 func handlePrint(contents string) (string, error) {
@@ -73,10 +187,10 @@ func handlePrint(contents string) (string, error) {
 
 func handleEdit(note obsidian.NoteManager, vaultPath, noteName, contents, key, value string) (string, error) {
 	if key == "" {
-		return "", errors.New("--key is required for edit operation")
+		return "", obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--key is required for edit operation")
 	}
 	if value == "" {
-		return "", errors.New("--value is required for edit operation")
+		return "", obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--value is required for edit operation")
 	}
 
 	updatedContent, err := frontmatter.SetKey(contents, key, value)
@@ -94,7 +208,7 @@ func handleEdit(note obsidian.NoteManager, vaultPath, noteName, contents, key, v
 
 func handleDelete(note obsidian.NoteManager, vaultPath, noteName, contents, key string) (string, error) {
 	if key == "" {
-		return "", errors.New("--key is required for delete operation")
+		return "", obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--key is required for delete operation")
 	}
 
 	updatedContent, err := frontmatter.DeleteKey(contents, key)
@@ -109,3 +223,57 @@ func handleDelete(note obsidian.NoteManager, vaultPath, noteName, contents, key
 
 	return fmt.Sprintf("Deleted frontmatter key '%s' from %s", key, noteName), nil
 }
+
+func handleRename(note obsidian.NoteManager, vaultPath, noteName, contents, key, newKey string) (string, error) {
+	if err := validateFrontmatterEdit(obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterRename, Key: key, NewKey: newKey}); err != nil {
+		return "", err
+	}
+
+	updatedContent, err := frontmatter.RenameKey(contents, key, newKey)
+	if err != nil {
+		return "", err
+	}
+
+	err = note.SetContents(vaultPath, noteName, updatedContent)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Renamed frontmatter key '%s' to '%s' in %s", key, newKey, noteName), nil
+}
+
+func handleAppend(note obsidian.NoteManager, vaultPath, noteName, contents, key, value string) (string, error) {
+	if err := validateFrontmatterEdit(obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterAppend, Key: key, Value: value}); err != nil {
+		return "", err
+	}
+
+	updatedContent, err := frontmatter.AppendValue(contents, key, value)
+	if err != nil {
+		return "", err
+	}
+
+	err = note.SetContents(vaultPath, noteName, updatedContent)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Appended '%s' to frontmatter key '%s' in %s", value, key, noteName), nil
+}
+
+func handleRemove(note obsidian.NoteManager, vaultPath, noteName, contents, key, value string) (string, error) {
+	if err := validateFrontmatterEdit(obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterRemove, Key: key, Value: value}); err != nil {
+		return "", err
+	}
+
+	updatedContent, err := frontmatter.RemoveValue(contents, key, value)
+	if err != nil {
+		return "", err
+	}
+
+	err = note.SetContents(vaultPath, noteName, updatedContent)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Removed '%s' from frontmatter key '%s' in %s", value, key, noteName), nil
+}
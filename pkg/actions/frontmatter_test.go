@@ -2,10 +2,13 @@ package actions_test
 
 import (
 	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Yakitrak/notesmd-cli/mocks"
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -220,6 +223,133 @@ func TestFrontmatter_Delete(t *testing.T) {
 	})
 }
 
+func TestFrontmatter_RenameAndAppend(t *testing.T) {
+	t.Run("Rename existing key", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "---\nstate: done\n---\nBody"}
+
+		output, err := actions.Frontmatter(&vault, &note, actions.FrontmatterParams{
+			NoteName: "test-note",
+			Rename:   true,
+			Key:      "state",
+			NewKey:   "status",
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Renamed frontmatter key 'state' to 'status'")
+	})
+
+	t.Run("Rename requires new key", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "---\nstate: done\n---\nBody"}
+
+		_, err := actions.Frontmatter(&vault, &note, actions.FrontmatterParams{
+			NoteName: "test-note",
+			Rename:   true,
+			Key:      "state",
+		})
+
+		assert.EqualError(t, err, "--new-key is required for rename operation")
+	})
+
+	t.Run("Rename missing key fails", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "---\ntitle: Test\n---\nBody"}
+
+		_, err := actions.Frontmatter(&vault, &note, actions.FrontmatterParams{
+			NoteName: "test-note",
+			Rename:   true,
+			Key:      "state",
+			NewKey:   "status",
+		})
+
+		assert.Equal(t, obsidian.ErrCodeKeyNotFound, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Append to list", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "---\ntags: [a]\n---\nBody"}
+
+		output, err := actions.Frontmatter(&vault, &note, actions.FrontmatterParams{
+			NoteName: "test-note",
+			Append:   true,
+			Key:      "tags",
+			Value:    "b",
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Appended 'b' to frontmatter key 'tags'")
+	})
+}
+
+func TestFrontmatter_Remove(t *testing.T) {
+	t.Run("Remove value from list", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "---\ntags: [a, b]\n---\nBody"}
+
+		output, err := actions.Frontmatter(&vault, &note, actions.FrontmatterParams{
+			NoteName: "test-note",
+			Remove:   true,
+			Key:      "tags",
+			Value:    "a",
+		})
+
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Removed 'a' from frontmatter key 'tags'")
+	})
+
+	t.Run("Remove from a map fails", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "---\nproject:\n  owner: Ann\n---\nBody"}
+
+		_, err := actions.Frontmatter(&vault, &note, actions.FrontmatterParams{
+			NoteName: "test-note",
+			Remove:   true,
+			Key:      "project",
+			Value:    "Ann",
+		})
+
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
+
+func TestBulkFrontmatter(t *testing.T) {
+	t.Run("Edits the selected notes", func(t *testing.T) {
+		vaultDir := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(vaultDir, "a.md"), []byte("---\nstate: done\n---\n"), 0644))
+		assert.NoError(t, os.WriteFile(filepath.Join(vaultDir, "b.md"), []byte("---\nstate: open\n---\n"), 0644))
+		vault := &vaultStub{path: vaultDir}
+
+		changes, err := actions.BulkFrontmatter(vault, actions.BulkFrontmatterParams{
+			Selector: obsidian.NoteSelector{Where: `state = "done"`},
+			Rename:   true,
+			Key:      "state",
+			NewKey:   "status",
+		})
+
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, "a.md", changes[0].Path)
+		content, _ := os.ReadFile(filepath.Join(vaultDir, "a.md"))
+		assert.Equal(t, "---\nstatus: done\n---\n", string(content))
+	})
+
+	t.Run("Validates parameters", func(t *testing.T) {
+		vault := &vaultStub{path: t.TempDir()}
+		tests := map[string]actions.BulkFrontmatterParams{
+			"no notes selected: use --glob, --folder, --tag, or --where":                    {Delete: true, Key: "a"},
+			"no operation specified: use --edit, --delete, --rename, --append, or --remove": {Selector: obsidian.NoteSelector{Glob: "*"}, Key: "a"},
+			"--key is required for delete operation":                                        {Selector: obsidian.NoteSelector{Glob: "*"}, Delete: true},
+			"--value is required for append operation":                                      {Selector: obsidian.NoteSelector{Glob: "*"}, Append: true, Key: "a"},
+		}
+		for message, params := range tests {
+			_, err := actions.BulkFrontmatter(vault, params)
+			assert.EqualError(t, err, message)
+			assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+		}
+	})
+}
+
 func TestFrontmatter_NoOperation(t *testing.T) {
 	t.Run("Returns error when no operation specified", func(t *testing.T) {
 		vault := mocks.MockVaultOperator{Name: "myVault"}
@@ -235,3 +365,36 @@ func TestFrontmatter_NoOperation(t *testing.T) {
 		assert.Contains(t, err.Error(), "no operation specified")
 	})
 }
+
+func TestReadFrontmatter(t *testing.T) {
+	t.Run("Returns parsed frontmatter", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{
+			Contents: "---\ntitle: Test Note\ntags:\n  - a\n  - b\n---\nBody content",
+		}
+
+		fm, err := actions.ReadFrontmatter(&vault, &note, "test-note")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{
+			"title": "Test Note",
+			"tags":  []interface{}{"a", "b"},
+		}, fm)
+	})
+
+	t.Run("Returns empty map without frontmatter", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "Body only"}
+
+		fm, err := actions.ReadFrontmatter(&vault, &note, "test-note")
+		assert.NoError(t, err)
+		assert.Empty(t, fm)
+	})
+
+	t.Run("Missing key is an invalid argument", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "Body only"}
+
+		_, err := actions.Frontmatter(&vault, &note, actions.FrontmatterParams{NoteName: "test-note", Delete: true})
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
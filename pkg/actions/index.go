@@ -0,0 +1,53 @@
+package actions
+
+import (
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+// RebuildIndex indexes every note in the vault from scratch and writes the
+// result to disk so later commands can skip walking the vault.
+func RebuildIndex(vault obsidian.VaultManager) (obsidian.IndexStatus, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return obsidian.IndexStatus{}, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return obsidian.IndexStatus{}, err
+	}
+
+	return obsidian.RebuildIndex(vaultPath)
+}
+
+// UpdateIndex re-reads the notes that changed since the vault's index was
+// last saved and writes the result to disk, building the index if needed.
+func UpdateIndex(vault obsidian.VaultManager) (obsidian.IndexStatus, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return obsidian.IndexStatus{}, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return obsidian.IndexStatus{}, err
+	}
+
+	return obsidian.UpdateIndex(vaultPath)
+}
+
+// IndexStatus reports whether the vault has an index and how many notes have
+// changed since it was last updated.
+func IndexStatus(vault obsidian.VaultManager) (obsidian.IndexStatus, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return obsidian.IndexStatus{}, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return obsidian.IndexStatus{}, err
+	}
+
+	return obsidian.ReadIndexStatus(vaultPath)
+}
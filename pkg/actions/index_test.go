@@ -0,0 +1,51 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIndexActions(t *testing.T) {
+	t.Run("Rebuild then report status", func(t *testing.T) {
+		vaultDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(vaultDir, "note.md"), []byte("# Note"), 0644)
+		assert.NoError(t, err)
+		vault := &vaultStub{path: vaultDir}
+
+		status, err := actions.IndexStatus(vault)
+		assert.NoError(t, err)
+		assert.False(t, status.Exists)
+
+		rebuilt, err := actions.RebuildIndex(vault)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, rebuilt.Notes)
+
+		status, err = actions.IndexStatus(vault)
+		assert.NoError(t, err)
+		assert.True(t, status.Exists)
+		assert.Equal(t, 1, status.Notes)
+		assert.Zero(t, status.Added+status.Updated+status.Removed)
+
+		err = os.WriteFile(filepath.Join(vaultDir, "other.md"), []byte(""), 0644)
+		assert.NoError(t, err)
+		updated, err := actions.UpdateIndex(vault)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, updated.Added)
+		assert.Equal(t, 2, updated.Notes)
+	})
+
+	t.Run("Vault errors propagate", func(t *testing.T) {
+		vault := &vaultStub{defaultErr: errors.New("no default")}
+		_, err := actions.RebuildIndex(vault)
+		assert.EqualError(t, err, "no default")
+
+		vault = &vaultStub{pathErr: errors.New("no path")}
+		_, err = actions.IndexStatus(vault)
+		assert.EqualError(t, err, "no path")
+	})
+}
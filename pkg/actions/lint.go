@@ -0,0 +1,58 @@
+package actions
+
+import (
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+// LintFrontmatter checks note frontmatter against the schema file at
+// schemaPath. When files are given only those notes are checked; like the
+// file names a pre-commit hook passes, they are absolute or relative to the
+// working directory, and files outside the vault are ignored.
+func LintFrontmatter(vault obsidian.VaultManager, schemaPath string, files []string) (obsidian.LintReport, error) {
+	if schemaPath == "" {
+		return obsidian.LintReport{}, obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--schema is required")
+	}
+
+	_, err := vault.DefaultName()
+	if err != nil {
+		return obsidian.LintReport{}, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return obsidian.LintReport{}, err
+	}
+
+	schema, err := obsidian.LoadLintSchema(schemaPath)
+	if err != nil {
+		return obsidian.LintReport{}, err
+	}
+
+	var paths []string
+	if len(files) > 0 {
+		absVault, err := filepath.Abs(vaultPath)
+		if err != nil {
+			return obsidian.LintReport{}, obsidian.NewError(obsidian.ErrCodeVaultAccess, obsidian.VaultAccessError)
+		}
+		for _, file := range files {
+			absFile, err := filepath.Abs(file)
+			if err != nil {
+				continue
+			}
+			rel, err := filepath.Rel(absVault, absFile)
+			if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				continue
+			}
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		if len(paths) == 0 {
+			return obsidian.LintReport{Violations: []obsidian.LintViolation{}}, nil
+		}
+	}
+
+	return obsidian.LintFrontmatter(vaultPath, schema, paths, time.Now())
+}
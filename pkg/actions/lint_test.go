@@ -0,0 +1,49 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintFrontmatter(t *testing.T) {
+	vaultDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(vaultDir, "a.md"), []byte("---\nstatus: open\n---\n"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(vaultDir, "b.md"), []byte("Body"), 0644))
+	schemaPath := filepath.Join(t.TempDir(), "schema.yaml")
+	assert.NoError(t, os.WriteFile(schemaPath, []byte("required: [status]\n"), 0644))
+	vault := &vaultStub{path: vaultDir}
+
+	t.Run("Lints the whole vault", func(t *testing.T) {
+		report, err := actions.LintFrontmatter(vault, schemaPath, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, report.Checked)
+		assert.Equal(t, []obsidian.LintViolation{{Path: "b.md", Key: "status", Message: "missing required key"}}, report.Violations)
+	})
+
+	t.Run("Lints only the given files inside the vault", func(t *testing.T) {
+		report, err := actions.LintFrontmatter(vault, schemaPath, []string{filepath.Join(vaultDir, "a.md"), schemaPath})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Checked)
+		assert.Empty(t, report.Violations)
+
+		report, err = actions.LintFrontmatter(vault, schemaPath, []string{schemaPath})
+		assert.NoError(t, err)
+		assert.Equal(t, 0, report.Checked)
+	})
+
+	t.Run("Requires a schema", func(t *testing.T) {
+		_, err := actions.LintFrontmatter(vault, "", nil)
+		assert.EqualError(t, err, "--schema is required")
+	})
+
+	t.Run("Vault errors propagate", func(t *testing.T) {
+		_, err := actions.LintFrontmatter(&vaultStub{defaultErr: errors.New("no default")}, schemaPath, nil)
+		assert.EqualError(t, err, "no default")
+	})
+}
@@ -1,6 +1,9 @@
 package actions
 
 import (
+	"os"
+	"path/filepath"
+
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 )
 
@@ -9,45 +12,102 @@ type MoveParams struct {
 	NewNoteName     string
 	ShouldOpen      bool
 	UseEditor       bool
+	// MoveAttachments also moves the attachments only the note uses to its
+	// new attachment folder.
+	MoveAttachments bool
+}
+
+// MoveResult describes a completed move. From and To are the vault-relative
+// paths of the note or folder; Files lists every file that moved.
+type MoveResult struct {
+	From  string
+	To    string
+	Files []obsidian.FileMove
 }
 
-func MoveNote(vault obsidian.VaultManager, note obsidian.NoteManager, uri obsidian.UriManager, params MoveParams) error {
+// MoveNote moves a note, or a folder with everything in it, and updates the
+// links to what moved.
+func MoveNote(vault obsidian.VaultManager, note obsidian.NoteManager, uri obsidian.UriManager, params MoveParams) (MoveResult, error) {
 	vaultName, err := vault.DefaultName()
 	if err != nil {
-		return err
+		return MoveResult{}, err
 	}
 	vaultPath, err := vault.Path()
 	if err != nil {
-		return err
+		return MoveResult{}, err
 	}
 
 	// Validate paths stay within vault directory
 	currentPath, err := obsidian.ValidatePath(vaultPath, params.CurrentNoteName)
 	if err != nil {
-		return err
+		return MoveResult{}, err
 	}
 	newPath, err := obsidian.ValidatePath(vaultPath, params.NewNoteName)
 	if err != nil {
-		return err
+		return MoveResult{}, err
+	}
+
+	if info, err := os.Stat(currentPath); err == nil && info.IsDir() {
+		if params.ShouldOpen {
+			return MoveResult{}, obsidian.NewError(obsidian.ErrCodeInvalidArgument, "--open cannot be used when moving a folder")
+		}
+		files, err := note.MoveFolder(vaultPath, params.CurrentNoteName, params.NewNoteName)
+		if err != nil {
+			return MoveResult{}, err
+		}
+		return MoveResult{
+			From:  filepath.ToSlash(filepath.Clean(params.CurrentNoteName)),
+			To:    filepath.ToSlash(filepath.Clean(params.NewNoteName)),
+			Files: files,
+		}, nil
+	}
+
+	from := filepath.ToSlash(obsidian.AddMdSuffix(params.CurrentNoteName))
+	to := filepath.ToSlash(obsidian.AddMdSuffix(params.NewNoteName))
+	isAttachment := obsidian.IsAttachment(currentPath)
+	if isAttachment {
+		from = filepath.ToSlash(params.CurrentNoteName)
+		to = filepath.ToSlash(params.NewNoteName)
+	}
+
+	// Check that the attachments can move before anything moves, so a
+	// taken destination does not leave the note moved without them.
+	moveAttachments := params.MoveAttachments && !isAttachment
+	if moveAttachments {
+		if _, err := note.PlanAttachmentMoves(vaultPath, params.CurrentNoteName, params.NewNoteName); err != nil {
+			return MoveResult{}, err
+		}
 	}
 
 	err = note.Move(currentPath, newPath)
 	if err != nil {
-		return err
+		return MoveResult{}, err
 	}
 
 	err = note.UpdateLinks(vaultPath, params.CurrentNoteName, params.NewNoteName)
 	if err != nil {
-		return err
+		return MoveResult{}, err
+	}
+
+	result := MoveResult{From: from, To: to, Files: []obsidian.FileMove{{From: from, To: to}}}
+	if moveAttachments {
+		attachments, err := note.MoveAttachments(vaultPath, params.CurrentNoteName, params.NewNoteName)
+		if err != nil {
+			return MoveResult{}, err
+		}
+		result.Files = append(result.Files, attachments...)
 	}
 
 	if params.ShouldOpen {
 		if params.UseEditor {
 			filePathWithExt, err := obsidian.ValidatePath(vaultPath, obsidian.AddMdSuffix(params.NewNoteName))
 			if err != nil {
-				return err
+				return MoveResult{}, err
+			}
+			if err := obsidian.OpenInEditor(filePathWithExt); err != nil {
+				return MoveResult{}, err
 			}
-			return obsidian.OpenInEditor(filePathWithExt)
+			return result, nil
 		}
 
 		obsidianUri := uri.Construct(ObsOpenUrl, map[string]string{
@@ -57,9 +117,9 @@ func MoveNote(vault obsidian.VaultManager, note obsidian.NoteManager, uri obsidi
 
 		err := uri.Execute(obsidianUri)
 		if err != nil {
-			return err
+			return MoveResult{}, err
 		}
 	}
 
-	return nil
+	return result, nil
 }
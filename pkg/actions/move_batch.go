@@ -0,0 +1,94 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+// MoveBatchParams are the moves of a batch move, read from a mapping file
+// with ReadMoveMap.
+type MoveBatchParams struct {
+	Moves []obsidian.FileMove
+}
+
+// MoveBatch moves all notes, attachments and folders in params at once and
+// updates the links to them in a single pass. Nothing moves if any of the
+// moves is invalid.
+func MoveBatch(vault obsidian.VaultManager, note obsidian.NoteManager, params MoveBatchParams) ([]obsidian.FileMove, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+	if len(params.Moves) == 0 {
+		return nil, obsidian.NewError(obsidian.ErrCodeInvalidArgument, "mapping file has no moves")
+	}
+
+	return note.MoveFiles(vaultPath, params.Moves)
+}
+
+// ReadMoveMap reads old and new paths from a mapping file. A file ending in
+// .json, or starting with "{" or "[", holds either an object mapping old
+// paths to new ones or an array of {"from": ..., "to": ...} objects. Any
+// other file has one move per line, the old and new path separated by a
+// tab; blank lines and lines starting with "#" are skipped.
+func ReadMoveMap(path string) ([]obsidian.FileMove, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, obsidian.WrapError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("cannot read mapping file %q", path), err)
+	}
+
+	trimmed := strings.TrimSpace(string(data))
+	if strings.EqualFold(filepath.Ext(path), ".json") || strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return parseJSONMoveMap([]byte(trimmed))
+	}
+	return parseTSVMoveMap(string(data))
+}
+
+func parseJSONMoveMap(data []byte) ([]obsidian.FileMove, error) {
+	var moves []obsidian.FileMove
+	if len(data) > 0 && data[0] == '[' {
+		if err := json.Unmarshal(data, &moves); err != nil {
+			return nil, obsidian.WrapError(obsidian.ErrCodeInvalidArgument, "invalid mapping file: "+err.Error(), err)
+		}
+	} else {
+		var mapping map[string]string
+		if err := json.Unmarshal(data, &mapping); err != nil {
+			return nil, obsidian.WrapError(obsidian.ErrCodeInvalidArgument, "invalid mapping file: "+err.Error(), err)
+		}
+		for from, to := range mapping {
+			moves = append(moves, obsidian.FileMove{From: from, To: to})
+		}
+	}
+
+	for i, move := range moves {
+		if strings.TrimSpace(move.From) == "" || strings.TrimSpace(move.To) == "" {
+			return nil, obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid mapping file: move %d needs both a \"from\" and a \"to\" path", i+1))
+		}
+	}
+	return moves, nil
+}
+
+func parseTSVMoveMap(data string) ([]obsidian.FileMove, error) {
+	var moves []obsidian.FileMove
+	for i, line := range strings.Split(data, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 2 || strings.TrimSpace(fields[0]) == "" || strings.TrimSpace(fields[1]) == "" {
+			return nil, obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid mapping file: line %d must be an old and a new path separated by a tab", i+1))
+		}
+		moves = append(moves, obsidian.FileMove{From: strings.TrimSpace(fields[0]), To: strings.TrimSpace(fields[1])})
+	}
+	return moves, nil
+}
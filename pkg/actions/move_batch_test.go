@@ -0,0 +1,120 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/mocks"
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadMoveMap(t *testing.T) {
+	tests := []struct {
+		testName string
+		fileName string
+		content  string
+		expected []obsidian.FileMove
+	}{
+		{
+			"Tab-separated lines",
+			"map.tsv",
+			"# old\tnew\r\nInbox/a\tProjects/a\n\n  \nassets/pic.png\tmedia/pic.png\n",
+			[]obsidian.FileMove{{From: "Inbox/a", To: "Projects/a"}, {From: "assets/pic.png", To: "media/pic.png"}},
+		},
+		{
+			"JSON object",
+			"map.json",
+			`{"Inbox/a": "Projects/a", "Inbox/b": "Projects/b"}`,
+			[]obsidian.FileMove{{From: "Inbox/a", To: "Projects/a"}, {From: "Inbox/b", To: "Projects/b"}},
+		},
+		{
+			"JSON array without .json extension",
+			"map.txt",
+			` [{"from": "Inbox/a", "to": "Projects/a"}]`,
+			[]obsidian.FileMove{{From: "Inbox/a", To: "Projects/a"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), test.fileName)
+			assert.NoError(t, os.WriteFile(path, []byte(test.content), 0644))
+			// Act
+			moves, err := actions.ReadMoveMap(path)
+			// Assert
+			assert.NoError(t, err)
+			sort.Slice(moves, func(i, j int) bool { return moves[i].From < moves[j].From })
+			assert.Equal(t, test.expected, moves)
+		})
+	}
+
+	invalid := map[string]string{
+		"Line without a tab":    "Inbox/a Projects/a\n",
+		"Line with three paths": "a\tb\tc\n",
+		"Empty new path":        "a\t \n",
+		"Invalid JSON":          `{"a": }`,
+		"JSON move without to":  `[{"from": "a"}]`,
+	}
+	for name, content := range invalid {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			path := filepath.Join(t.TempDir(), "map")
+			assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			// Act
+			_, err := actions.ReadMoveMap(path)
+			// Assert
+			assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+		})
+	}
+
+	t.Run("Missing file", func(t *testing.T) {
+		_, err := actions.ReadMoveMap(filepath.Join(t.TempDir(), "missing.tsv"))
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
+
+func TestMoveBatch(t *testing.T) {
+	moves := []obsidian.FileMove{{From: "a", To: "b"}}
+
+	t.Run("Successful batch move", func(t *testing.T) {
+		// Arrange
+		note := mocks.MockNoteManager{}
+		// Act
+		files, err := actions.MoveBatch(&mocks.MockVaultOperator{}, &note, actions.MoveBatchParams{Moves: moves})
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, moves, note.MoveFilesArg)
+		assert.Equal(t, moves, files)
+	})
+
+	t.Run("No moves", func(t *testing.T) {
+		// Act
+		_, err := actions.MoveBatch(&mocks.MockVaultOperator{}, &mocks.MockNoteManager{}, actions.MoveBatchParams{})
+		// Assert
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+
+	t.Run("vault.Path returns an error", func(t *testing.T) {
+		// Arrange
+		vault := mocks.MockVaultOperator{PathError: errors.New("Failed to get vault path")}
+		// Act
+		_, err := actions.MoveBatch(&vault, &mocks.MockNoteManager{}, actions.MoveBatchParams{Moves: moves})
+		// Assert
+		assert.Equal(t, vault.PathError, err)
+	})
+
+	t.Run("note.MoveFiles returns an error", func(t *testing.T) {
+		// Arrange
+		note := mocks.MockNoteManager{MoveFilesErr: errors.New("Failed to move")}
+		// Act
+		_, err := actions.MoveBatch(&mocks.MockVaultOperator{}, &note, actions.MoveBatchParams{Moves: moves})
+		// Assert
+		assert.Equal(t, note.MoveFilesErr, err)
+	})
+}
@@ -3,10 +3,12 @@ package actions_test
 import (
 	"errors"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Yakitrak/notesmd-cli/mocks"
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -17,13 +19,18 @@ func TestMoveNote(t *testing.T) {
 		uri := mocks.MockUriManager{}
 		note := mocks.MockNoteManager{}
 		// Act
-		err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
+		result, err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
 			CurrentNoteName: "string",
 			NewNoteName:     "string",
 			ShouldOpen:      true,
 		})
 		// Assert
 		assert.NoError(t, err, "Expected no error")
+		assert.Equal(t, actions.MoveResult{
+			From:  "string.md",
+			To:    "string.md",
+			Files: []obsidian.FileMove{{From: "string.md", To: "string.md"}},
+		}, result)
 	})
 
 	t.Run("vault.DefaultName returns an error", func(t *testing.T) {
@@ -32,7 +39,7 @@ func TestMoveNote(t *testing.T) {
 			DefaultNameErr: errors.New("Failed to get vault name"),
 		}
 		// Act
-		err := actions.MoveNote(&vault, &mocks.MockNoteManager{}, &mocks.MockUriManager{}, actions.MoveParams{
+		_, err := actions.MoveNote(&vault, &mocks.MockNoteManager{}, &mocks.MockUriManager{}, actions.MoveParams{
 			CurrentNoteName: "string",
 			NewNoteName:     "string",
 			ShouldOpen:      true,
@@ -47,7 +54,7 @@ func TestMoveNote(t *testing.T) {
 			PathError: errors.New("Failed to get vault path"),
 		}
 		// Act
-		err := actions.MoveNote(vaultOp, &mocks.MockNoteManager{}, &mocks.MockUriManager{}, actions.MoveParams{
+		_, err := actions.MoveNote(vaultOp, &mocks.MockNoteManager{}, &mocks.MockUriManager{}, actions.MoveParams{
 			CurrentNoteName: "string",
 			NewNoteName:     "string",
 			ShouldOpen:      false,
@@ -62,7 +69,7 @@ func TestMoveNote(t *testing.T) {
 			MoveErr: errors.New("Failed to execute URI"),
 		}
 		// Act
-		err := actions.MoveNote(&mocks.MockVaultOperator{}, &note, &mocks.MockUriManager{}, actions.MoveParams{
+		_, err := actions.MoveNote(&mocks.MockVaultOperator{}, &note, &mocks.MockUriManager{}, actions.MoveParams{
 			CurrentNoteName: "string",
 			NewNoteName:     "string",
 			ShouldOpen:      false,
@@ -77,7 +84,7 @@ func TestMoveNote(t *testing.T) {
 			UpdateLinksError: errors.New("Failed to execute URI"),
 		}
 		// Act
-		err := actions.MoveNote(&mocks.MockVaultOperator{}, &note, &mocks.MockUriManager{}, actions.MoveParams{
+		_, err := actions.MoveNote(&mocks.MockVaultOperator{}, &note, &mocks.MockUriManager{}, actions.MoveParams{
 			CurrentNoteName: "string",
 			NewNoteName:     "string",
 			ShouldOpen:      false,
@@ -92,7 +99,7 @@ func TestMoveNote(t *testing.T) {
 			ExecuteErr: errors.New("Failed to execute URI"),
 		}
 		// Act
-		err := actions.MoveNote(&mocks.MockVaultOperator{}, &mocks.MockNoteManager{}, uriManager, actions.MoveParams{
+		_, err := actions.MoveNote(&mocks.MockVaultOperator{}, &mocks.MockNoteManager{}, uriManager, actions.MoveParams{
 			CurrentNoteName: "string",
 			NewNoteName:     "string",
 			ShouldOpen:      true,
@@ -114,7 +121,7 @@ func TestMoveNote(t *testing.T) {
 		os.Setenv("EDITOR", "true")
 
 		// Act
-		err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
+		_, err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
 			CurrentNoteName: "old.md",
 			NewNoteName:     "new.md",
 			ShouldOpen:      true,
@@ -137,7 +144,7 @@ func TestMoveNote(t *testing.T) {
 		os.Setenv("EDITOR", "false")
 
 		// Act
-		err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
+		_, err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
 			CurrentNoteName: "old.md",
 			NewNoteName:     "new.md",
 			ShouldOpen:      true,
@@ -155,7 +162,7 @@ func TestMoveNote(t *testing.T) {
 		note := mocks.MockNoteManager{}
 
 		// Act - UseEditor is true but ShouldOpen is false
-		err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
+		_, err := actions.MoveNote(&vault, &note, &uri, actions.MoveParams{
 			CurrentNoteName: "old.md",
 			NewNoteName:     "new.md",
 			ShouldOpen:      false,
@@ -165,4 +172,142 @@ func TestMoveNote(t *testing.T) {
 		// Assert - should succeed without opening
 		assert.NoError(t, err)
 	})
+	t.Run("Moves a folder", func(t *testing.T) {
+		// Arrange
+		vaultPath := t.TempDir()
+		assert.NoError(t, os.Mkdir(filepath.Join(vaultPath, "projects"), 0755))
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: vaultPath}
+		files := []obsidian.FileMove{{From: "projects/a.md", To: "archive/projects/a.md"}}
+		note := mocks.MockNoteManager{MoveFolderResult: files, MoveErr: errors.New("not a note")}
+		// Act
+		result, err := actions.MoveNote(&vault, &note, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "projects/",
+			NewNoteName:     "archive/projects",
+		})
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, actions.MoveResult{From: "projects", To: "archive/projects", Files: files}, result)
+	})
+
+	t.Run("note.MoveFolder returns an error", func(t *testing.T) {
+		// Arrange
+		vaultPath := t.TempDir()
+		assert.NoError(t, os.Mkdir(filepath.Join(vaultPath, "projects"), 0755))
+		vault := mocks.MockVaultOperator{PathValue: vaultPath}
+		note := mocks.MockNoteManager{MoveFolderErr: errors.New("Failed to move folder")}
+		// Act
+		_, err := actions.MoveNote(&vault, &note, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "projects",
+			NewNoteName:     "archive",
+		})
+		// Assert
+		assert.Equal(t, note.MoveFolderErr, err)
+	})
+
+	t.Run("Opening a moved folder is an error", func(t *testing.T) {
+		// Arrange
+		vaultPath := t.TempDir()
+		assert.NoError(t, os.Mkdir(filepath.Join(vaultPath, "projects"), 0755))
+		vault := mocks.MockVaultOperator{PathValue: vaultPath}
+		// Act
+		_, err := actions.MoveNote(&vault, &mocks.MockNoteManager{}, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "projects",
+			NewNoteName:     "archive",
+			ShouldOpen:      true,
+		})
+		// Assert
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+	t.Run("Moves the note's attachments", func(t *testing.T) {
+		// Arrange
+		attachments := []obsidian.FileMove{{From: "inbox/a.png", To: "projects/a.png"}}
+		note := mocks.MockNoteManager{MovedAttachments: attachments}
+		// Act
+		result, err := actions.MoveNote(&mocks.MockVaultOperator{}, &note, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "inbox/plan",
+			NewNoteName:     "projects/plan",
+			MoveAttachments: true,
+		})
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.FileMove{
+			{From: "inbox/plan.md", To: "projects/plan.md"},
+			{From: "inbox/a.png", To: "projects/a.png"},
+		}, result.Files)
+	})
+
+	t.Run("note.MoveAttachments returns an error", func(t *testing.T) {
+		// Arrange
+		note := mocks.MockNoteManager{MoveAttachmentsErr: errors.New("Failed to move attachment")}
+		// Act
+		_, err := actions.MoveNote(&mocks.MockVaultOperator{}, &note, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "inbox/plan",
+			NewNoteName:     "projects/plan",
+			MoveAttachments: true,
+		})
+		// Assert
+		assert.Equal(t, note.MoveAttachmentsErr, err)
+	})
+
+	t.Run("Taken attachment destination moves nothing", func(t *testing.T) {
+		// Arrange
+		vaultPath := t.TempDir()
+		files := map[string]string{
+			".obsidian/app.json": `{"attachmentFolderPath": "./assets"}`,
+			"a/Owner.md":         "![[pic.png]]",
+			"a/assets/pic.png":   "png",
+			"d/assets/pic.png":   "other",
+			"Linker.md":          "[[a/Owner]]",
+		}
+		for p, content := range files {
+			assert.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(vaultPath, p)), 0755))
+			assert.NoError(t, os.WriteFile(filepath.Join(vaultPath, p), []byte(content), 0644))
+		}
+		vault := mocks.MockVaultOperator{PathValue: vaultPath}
+		// Act
+		_, err := actions.MoveNote(&vault, &obsidian.Note{}, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "a/Owner",
+			NewNoteName:     "d/Owner",
+			MoveAttachments: true,
+		})
+		// Assert
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+		assert.FileExists(t, filepath.Join(vaultPath, "a", "Owner.md"))
+		assert.NoFileExists(t, filepath.Join(vaultPath, "d", "Owner.md"))
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "Linker.md"))
+		assert.Equal(t, "[[a/Owner]]", string(content))
+	})
+
+	t.Run("note.PlanAttachmentMoves returns an error", func(t *testing.T) {
+		// Arrange
+		note := mocks.MockNoteManager{PlanAttachmentsErr: errors.New("Attachment destination exists")}
+		// Act
+		_, err := actions.MoveNote(&mocks.MockVaultOperator{}, &note, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "inbox/plan",
+			NewNoteName:     "projects/plan",
+			MoveAttachments: true,
+		})
+		// Assert
+		assert.Equal(t, note.PlanAttachmentsErr, err)
+	})
+
+	t.Run("Moves an attachment", func(t *testing.T) {
+		// Arrange
+		vaultPath := t.TempDir()
+		assert.NoError(t, os.WriteFile(filepath.Join(vaultPath, "pic.png"), []byte("png"), 0644))
+		vault := mocks.MockVaultOperator{PathValue: vaultPath}
+		// Act
+		result, err := actions.MoveNote(&vault, &mocks.MockNoteManager{}, &mocks.MockUriManager{}, actions.MoveParams{
+			CurrentNoteName: "pic.png",
+			NewNoteName:     "assets/pic.png",
+			MoveAttachments: true,
+		})
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, actions.MoveResult{
+			From:  "pic.png",
+			To:    "assets/pic.png",
+			Files: []obsidian.FileMove{{From: "pic.png", To: "assets/pic.png"}},
+		}, result)
+	})
 }
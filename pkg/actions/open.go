@@ -3,6 +3,7 @@ package actions
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 )
@@ -10,6 +11,7 @@ import (
 type OpenParams struct {
 	NoteName  string
 	Section   string
+	Block     string
 	UseEditor bool
 }
 
@@ -19,24 +21,31 @@ func OpenNote(vault obsidian.VaultManager, uri obsidian.UriManager, params OpenP
 		return err
 	}
 
+	noteName, block := obsidian.SplitBlockRef(params.NoteName)
+	if params.Block != "" {
+		block = strings.TrimPrefix(params.Block, "^")
+	}
+
 	if params.UseEditor {
-		if params.Section != "" {
-			fmt.Fprintln(os.Stderr, "Warning: --section is ignored when using --editor")
+		if params.Section != "" || block != "" {
+			fmt.Fprintln(os.Stderr, "Warning: --section and --block are ignored when using --editor")
 		}
 		vaultPath, err := vault.Path()
 		if err != nil {
 			return err
 		}
-		filePath, err := obsidian.ValidatePath(vaultPath, obsidian.AddMdSuffix(params.NoteName))
+		filePath, err := obsidian.ValidatePath(vaultPath, obsidian.AddMdSuffix(noteName))
 		if err != nil {
 			return err
 		}
 		return obsidian.OpenInEditor(filePath)
 	}
 
-	fileParam := params.NoteName
-	if params.Section != "" {
-		fileParam = params.NoteName + "#" + params.Section
+	fileParam := noteName
+	if block != "" {
+		fileParam = noteName + "#^" + block
+	} else if params.Section != "" {
+		fileParam = noteName + "#" + params.Section
 	}
 
 	obsidianUri := uri.Construct(ObsOpenUrl, map[string]string{
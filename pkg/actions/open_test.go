@@ -67,6 +67,29 @@ func TestOpenNote(t *testing.T) {
 		assert.Equal(t, "note.md#Section Name", uri.LastParams["file"])
 	})
 
+	t.Run("Opens note at a block", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		uri := mocks.MockUriManager{}
+
+		err := actions.OpenNote(&vault, &uri, actions.OpenParams{
+			NoteName: "note",
+			Block:    "^abc123",
+		})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "note#^abc123", uri.LastParams["file"])
+	})
+
+	t.Run("Opens note at a block reference in its name", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		uri := mocks.MockUriManager{}
+
+		err := actions.OpenNote(&vault, &uri, actions.OpenParams{NoteName: "note#^abc123"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "note#^abc123", uri.LastParams["file"])
+	})
+
 	t.Run("Opens note in editor when UseEditor is true", func(t *testing.T) {
 		tmpDir := t.TempDir()
 		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
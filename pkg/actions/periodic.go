@@ -0,0 +1,47 @@
+package actions
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+type PeriodicParams struct {
+	Period    string
+	UseEditor bool
+}
+
+// PeriodicNote creates the current weekly, monthly, quarterly or yearly note
+// if needed and opens it, returning the note's path within the vault. The
+// folder, format and template come from the Periodic Notes plugin config.
+func PeriodicNote(vault obsidian.VaultManager, uri obsidian.UriManager, params PeriodicParams) (string, error) {
+	defaultFormat := obsidian.PeriodDefaultFormat(params.Period)
+	if defaultFormat == "" {
+		return "", obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("unknown period %q", params.Period))
+	}
+
+	vaultName, err := vault.DefaultName()
+	if err != nil {
+		return "", err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return "", err
+	}
+
+	config := obsidian.ReadPeriodicNotesConfig(vaultPath, params.Period)
+
+	format := config.Format
+	if format == "" {
+		format = defaultFormat
+	}
+
+	return openPeriodicNote(vaultName, vaultPath, uri, periodicNote{
+		folder:   config.Folder,
+		format:   format,
+		template: config.Template,
+		date:     time.Now(),
+	}, params.UseEditor)
+}
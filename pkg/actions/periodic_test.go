@@ -0,0 +1,70 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/mocks"
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeriodicNote(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Creates notes with the default formats", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		uri := mocks.MockUriManager{}
+
+		for period, format := range map[string]string{
+			obsidian.PeriodWeekly:    "gggg-[W]ww",
+			obsidian.PeriodMonthly:   "YYYY-MM",
+			obsidian.PeriodQuarterly: "YYYY-[Q]Q",
+			obsidian.PeriodYearly:    "YYYY",
+		} {
+			notePath, err := actions.PeriodicNote(&vault, &uri, actions.PeriodicParams{Period: period})
+			assert.NoError(t, err, period)
+			assert.Equal(t, obsidian.FormatMoment(now, format)+".md", notePath, period)
+			assert.FileExists(t, filepath.Join(tmpDir, notePath), period)
+		}
+	})
+
+	t.Run("Uses the Periodic Notes config", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		pluginDir := filepath.Join(tmpDir, ".obsidian", "plugins", "periodic-notes")
+		os.MkdirAll(pluginDir, 0755)
+		os.WriteFile(filepath.Join(pluginDir, "data.json"), []byte(`{
+			"monthly": {"folder": "Monthly", "format": "YYYY/MMMM", "template": "Templates/Month"}
+		}`), 0644)
+		os.MkdirAll(filepath.Join(tmpDir, "Templates"), 0755)
+		os.WriteFile(filepath.Join(tmpDir, "Templates", "Month.md"), []byte("# {{title}}"), 0644)
+
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: tmpDir}
+		uri := mocks.MockUriManager{}
+
+		notePath, err := actions.PeriodicNote(&vault, &uri, actions.PeriodicParams{Period: obsidian.PeriodMonthly})
+		assert.NoError(t, err)
+
+		name := now.Format("2006/January")
+		assert.Equal(t, "Monthly/"+name+".md", notePath)
+		assert.Equal(t, "Monthly/"+name, uri.LastParams["file"])
+		content, _ := os.ReadFile(filepath.Join(tmpDir, "Monthly", filepath.FromSlash(name)+".md"))
+		assert.Equal(t, "# "+now.Format("January"), string(content))
+	})
+
+	t.Run("Unknown period", func(t *testing.T) {
+		_, err := actions.PeriodicNote(&mocks.MockVaultOperator{}, &mocks.MockUriManager{}, actions.PeriodicParams{Period: "hourly"})
+		assert.EqualError(t, err, `unknown period "hourly"`)
+	})
+
+	t.Run("vault.Path returns an error", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault", PathError: errors.New("path error")}
+		_, err := actions.PeriodicNote(&vault, &mocks.MockUriManager{}, actions.PeriodicParams{Period: obsidian.PeriodWeekly})
+		assert.Equal(t, vault.PathError, err)
+	})
+}
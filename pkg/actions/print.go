@@ -12,34 +12,63 @@ type PrintParams struct {
 	IncludeMentions bool
 }
 
+// NoteContents is a note's raw content and, when requested, the lines in
+// other notes that link to it.
+type NoteContents struct {
+	Note     string               `json:"note"`
+	Content  string               `json:"content"`
+	Mentions []obsidian.NoteMatch `json:"mentions,omitempty"`
+}
+
 func PrintNote(vault obsidian.VaultManager, note obsidian.NoteManager, params PrintParams) (string, error) {
-	_, err := vault.DefaultName()
+	result, err := ReadNote(vault, note, params)
 	if err != nil {
 		return "", err
 	}
 
-	vaultPath, err := vault.Path()
+	contents := result.Content
+	if len(result.Mentions) > 0 {
+		contents += formatMentions(result.Mentions)
+	}
+	return contents, nil
+}
+
+// ReadNote returns a note's content and, with IncludeMentions, its linked
+// mentions, without formatting them for display. A note name ending in a
+// block reference, such as "note#^abc123", gives only that block.
+func ReadNote(vault obsidian.VaultManager, note obsidian.NoteManager, params PrintParams) (NoteContents, error) {
+	_, err := vault.DefaultName()
 	if err != nil {
-		return "", err
+		return NoteContents{}, err
 	}
 
-	contents, err := note.GetContents(vaultPath, params.NoteName)
+	vaultPath, err := vault.Path()
 	if err != nil {
-		return "", err
+		return NoteContents{}, err
 	}
 
-	if params.IncludeMentions {
-		backlinks, err := note.FindBacklinks(vaultPath, params.NoteName)
+	noteName, blockID := obsidian.SplitBlockRef(params.NoteName)
+	contents, err := note.GetContents(vaultPath, noteName)
+	if err != nil {
+		return NoteContents{}, err
+	}
+	if blockID != "" {
+		contents, err = obsidian.FindBlock(contents, blockID)
 		if err != nil {
-			return "", err
+			return NoteContents{}, err
 		}
+	}
 
-		if len(backlinks) > 0 {
-			contents += formatMentions(backlinks)
+	result := NoteContents{Note: params.NoteName, Content: contents}
+	if params.IncludeMentions {
+		backlinks, err := note.FindBacklinks(vaultPath, noteName)
+		if err != nil {
+			return NoteContents{}, err
 		}
+		result.Mentions = backlinks
 	}
 
-	return contents, nil
+	return result, nil
 }
 
 func formatMentions(backlinks []obsidian.NoteMatch) string {
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"github.com/Yakitrak/notesmd-cli/mocks"
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -50,6 +51,31 @@ func TestPrintNote(t *testing.T) {
 		assert.Equal(t, err, note.GetContentsError)
 	})
 
+	t.Run("Block reference prints only the block", func(t *testing.T) {
+		// Arrange
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "# Note\n\nFirst line\nsecond line ^abc123\n\nOther\n"}
+		// Act
+		content, err := actions.PrintNote(&vault, &note, actions.PrintParams{
+			NoteName: "note-name#^abc123",
+		})
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "First line\nsecond line", content)
+	})
+
+	t.Run("Missing block", func(t *testing.T) {
+		// Arrange
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "note content here"}
+		// Act
+		_, err := actions.PrintNote(&vault, &note, actions.PrintParams{
+			NoteName: "note-name#^abc123",
+		})
+		// Assert
+		assert.Equal(t, obsidian.ErrCodeBlockNotFound, obsidian.ErrorCode(err))
+	})
+
 	t.Run("IncludeMentions false returns just contents", func(t *testing.T) {
 		// Arrange
 		vault := mocks.MockVaultOperator{Name: "myVault"}
@@ -117,3 +143,32 @@ func TestPrintNote(t *testing.T) {
 		assert.Equal(t, "failed to find backlinks", err.Error())
 	})
 }
+
+func TestReadNote(t *testing.T) {
+	t.Run("Returns content and mentions separately", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{
+			Contents: "note content",
+			FindBacklinksResult: []obsidian.NoteMatch{
+				{FilePath: "other.md", LineNumber: 3, MatchLine: "see [[note-name]]"},
+			},
+		}
+
+		result, err := actions.ReadNote(&vault, &note, actions.PrintParams{NoteName: "note-name", IncludeMentions: true})
+		assert.NoError(t, err)
+		assert.Equal(t, actions.NoteContents{
+			Note:     "note-name",
+			Content:  "note content",
+			Mentions: note.FindBacklinksResult,
+		}, result)
+	})
+
+	t.Run("Omits mentions unless requested", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "note content"}
+
+		result, err := actions.ReadNote(&vault, &note, actions.PrintParams{NoteName: "note-name"})
+		assert.NoError(t, err)
+		assert.Nil(t, result.Mentions)
+	})
+}
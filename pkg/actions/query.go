@@ -0,0 +1,110 @@
+package actions
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/Yakitrak/notesmd-cli/pkg/query"
+)
+
+// Formats accepted by WriteQueryResult.
+const (
+	QueryFormatMarkdown = "md"
+	QueryFormatCSV      = "csv"
+	QueryFormatJSON     = "json"
+)
+
+// RunQuery evaluates a Dataview-style query over the notes in the vault.
+func RunQuery(vault obsidian.VaultManager, input string) (*query.Result, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	return obsidian.QueryVault(vaultPath, input, time.Now())
+}
+
+// WriteQueryResult prints a query result in the given format:
+//
+//   - md: a Markdown table, or a bulleted list for LIST queries
+//   - csv: a header row followed by one row per note
+//   - json: an array with one object per note, keyed by column name
+func WriteQueryResult(w io.Writer, result *query.Result, format string) error {
+	switch format {
+	case QueryFormatMarkdown, "":
+		if result.Kind == query.KindList {
+			return writeMarkdownList(w, result)
+		}
+		return writeMarkdownTable(w, result)
+	case QueryFormatCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(result.Columns); err != nil {
+			return err
+		}
+		for _, row := range result.Rows {
+			record := make([]string, len(row))
+			for i, value := range row {
+				record[i] = query.FormatValue(value)
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	case QueryFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(result.Records())
+	}
+	return obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("unknown format %q: use md, csv or json", format))
+}
+
+func writeMarkdownTable(w io.Writer, result *query.Result) error {
+	separators := make([]string, len(result.Columns))
+	for i := range separators {
+		separators[i] = "---"
+	}
+	lines := []string{markdownRow(result.Columns), markdownRow(separators)}
+	for _, row := range result.Rows {
+		cells := make([]string, len(row))
+		for i, value := range row {
+			cells[i] = query.FormatValue(value)
+		}
+		lines = append(lines, markdownRow(cells))
+	}
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+func markdownRow(cells []string) string {
+	escaped := make([]string, len(cells))
+	for i, cell := range cells {
+		escaped[i] = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(cell)
+	}
+	return "| " + strings.Join(escaped, " | ") + " |"
+}
+
+func writeMarkdownList(w io.Writer, result *query.Result) error {
+	for _, row := range result.Rows {
+		items := make([]string, len(row))
+		for i, value := range row {
+			items[i] = strings.ReplaceAll(query.FormatValue(value), "\n", " ")
+		}
+		line := strings.TrimSuffix("- "+strings.Join(items, ": "), ": ")
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package actions_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/Yakitrak/notesmd-cli/pkg/query"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunQuery(t *testing.T) {
+	t.Run("Runs the query over the vault", func(t *testing.T) {
+		vaultDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(vaultDir, "note.md"), []byte("---\nstatus: open\n---\n"), 0644)
+		assert.NoError(t, err)
+
+		result, err := actions.RunQuery(&vaultStub{path: vaultDir}, `TABLE status`)
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]interface{}{{"note.md", "open"}}, result.Rows)
+	})
+
+	t.Run("Vault errors propagate", func(t *testing.T) {
+		_, err := actions.RunQuery(&vaultStub{defaultErr: errors.New("no default")}, `LIST`)
+		assert.EqualError(t, err, "no default")
+
+		_, err = actions.RunQuery(&vaultStub{pathErr: errors.New("no path")}, `LIST`)
+		assert.EqualError(t, err, "no path")
+	})
+}
+
+func TestWriteQueryResult(t *testing.T) {
+	table := &query.Result{
+		Kind:    query.KindTable,
+		Columns: []string{"File", "status", "tags"},
+		Rows: [][]interface{}{
+			{"a.md", "open | blocked", []interface{}{"x", "y"}},
+			{"b.md", nil, 2.0},
+		},
+	}
+	list := &query.Result{
+		Kind:    query.KindList,
+		Columns: []string{"File", "status"},
+		Rows:    [][]interface{}{{"a.md", "open"}, {"b.md", nil}},
+	}
+
+	tests := []struct {
+		name     string
+		result   *query.Result
+		format   string
+		expected string
+	}{
+		{"Markdown table", table, "md", "| File | status | tags |\n| --- | --- | --- |\n| a.md | open \\| blocked | x, y |\n| b.md |  | 2 |\n"},
+		{"Markdown list", list, "md", "- a.md: open\n- b.md\n"},
+		{"CSV", table, "csv", "File,status,tags\na.md,open | blocked,\"x, y\"\nb.md,,2\n"},
+		{"JSON", list, "json", "[\n  {\n    \"File\": \"a.md\",\n    \"status\": \"open\"\n  },\n  {\n    \"File\": \"b.md\",\n    \"status\": null\n  }\n]\n"},
+		{"Empty JSON", &query.Result{Kind: query.KindList, Columns: []string{"File"}}, "json", "[]\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			err := actions.WriteQueryResult(&buf, test.result, test.format)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, buf.String())
+		})
+	}
+
+	t.Run("Unknown format", func(t *testing.T) {
+		err := actions.WriteQueryResult(&bytes.Buffer{}, table, "xml")
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
@@ -3,6 +3,8 @@ package actions
 import (
 	"fmt"
 	"path/filepath"
+	"strings"
+
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 )
 
@@ -48,3 +50,32 @@ func SearchNotes(vault obsidian.VaultManager, note obsidian.NoteManager, uri obs
 
 	return nil
 }
+
+// FindNotes lists the vault's notes whose path contains filter
+// (case-insensitive), in path order, without prompting. An empty filter
+// matches every note and a limit of 0 means no limit.
+func FindNotes(vault obsidian.VaultManager, note obsidian.NoteManager, filter string, limit int) ([]obsidian.NoteMatch, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	notes, err := note.GetNotesList(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	filterLower := strings.ToLower(filter)
+	matches := []obsidian.NoteMatch{}
+	for _, notePath := range notes {
+		if strings.Contains(strings.ToLower(notePath), filterLower) {
+			matches = append(matches, obsidian.NoteMatch{FilePath: notePath})
+		}
+	}
+	return limitMatches(matches, limit), nil
+}
@@ -7,7 +7,20 @@ import (
 	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 )
 
-func SearchNotesContent(vault obsidian.VaultManager, note obsidian.NoteManager, uri obsidian.UriManager, fuzzyFinder obsidian.FuzzyFinderManager, searchTerm string, useEditor bool) error {
+type SearchContentParams struct {
+	SearchTerm    string
+	Ranked        bool
+	Query         bool
+	Regex         bool
+	CaseSensitive bool
+	Multiline     bool
+	UseEditor     bool
+	// Limit caps the number of matches FindNotesContent returns; 0 means no
+	// limit.
+	Limit int
+}
+
+func SearchNotesContent(vault obsidian.VaultManager, note obsidian.NoteManager, uri obsidian.UriManager, fuzzyFinder obsidian.FuzzyFinderManager, params SearchContentParams) error {
 	vaultName, err := vault.DefaultName()
 	if err != nil {
 		return err
@@ -18,13 +31,14 @@ func SearchNotesContent(vault obsidian.VaultManager, note obsidian.NoteManager,
 		return err
 	}
 
-	matches, err := note.SearchNotesWithSnippets(vaultPath, searchTerm)
+	matches, err := searchContent(note, vaultPath, params)
 	if err != nil {
 		return err
 	}
 
+	useEditor := params.UseEditor
 	if len(matches) == 0 {
-		fmt.Printf("No notes found containing '%s'\n", searchTerm)
+		fmt.Printf("No notes found containing '%s'\n", params.SearchTerm)
 		return nil
 	}
 
@@ -41,7 +55,7 @@ func SearchNotesContent(vault obsidian.VaultManager, note obsidian.NoteManager,
 		return uri.Execute(obsidianUri)
 	}
 
-	displayItems := formatMatchesForDisplay(matches)
+	displayItems := FormatMatchesForDisplay(matches)
 
 	index, err := fuzzyFinder.Find(displayItems, func(i int) string {
 		return displayItems[i]
@@ -63,7 +77,45 @@ func SearchNotesContent(vault obsidian.VaultManager, note obsidian.NoteManager,
 	return uri.Execute(obsidianUri)
 }
 
-func formatMatchesForDisplay(matches []obsidian.NoteMatch) []string {
+// FindNotesContent runs a content search and returns the matches without
+// prompting for a selection or opening anything. Ranked searches are ordered
+// by relevance, all others by note path and line.
+func FindNotesContent(vault obsidian.VaultManager, note obsidian.NoteManager, params SearchContentParams) ([]obsidian.NoteMatch, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	matches, err := searchContent(note, vaultPath, params)
+	if err != nil {
+		return nil, err
+	}
+	return limitMatches(matches, params.Limit), nil
+}
+
+func searchContent(note obsidian.NoteManager, vaultPath string, params SearchContentParams) ([]obsidian.NoteMatch, error) {
+	if params.Regex {
+		return note.SearchNotesRegex(vaultPath, params.SearchTerm, obsidian.RegexSearchOptions{
+			CaseSensitive: params.CaseSensitive,
+			Multiline:     params.Multiline,
+		})
+	}
+	if params.Query {
+		return note.SearchNotesQuery(vaultPath, params.SearchTerm)
+	}
+	if params.Ranked {
+		return note.SearchNotesRanked(vaultPath, params.SearchTerm)
+	}
+	return note.SearchNotesWithSnippets(vaultPath, params.SearchTerm)
+}
+
+// FormatMatchesForDisplay renders matches as aligned "path:line | snippet" rows.
+func FormatMatchesForDisplay(matches []obsidian.NoteMatch) []string {
 	maxPathLength := calculateMaxPathLength(matches)
 
 	var displayItems []string
@@ -95,6 +147,9 @@ func formatPathWithLine(match obsidian.NoteMatch) string {
 
 func formatSingleMatch(match obsidian.NoteMatch, maxPathLength int) string {
 	pathWithLine := formatPathWithLine(match)
+	if match.MatchLine == "" {
+		return pathWithLine
+	}
 	if match.LineNumber == 0 {
 		// Filename match - show path and indicate it's a filename match
 		return fmt.Sprintf("%-*s | %s", maxPathLength, pathWithLine, match.MatchLine)
@@ -16,6 +16,11 @@ type CustomMockNoteForSingleMatch struct{}
 
 func (m *CustomMockNoteForSingleMatch) Delete(string) error                       { return nil }
 func (m *CustomMockNoteForSingleMatch) Move(string, string) error                  { return nil }
+func (m *CustomMockNoteForSingleMatch) MoveFolder(string, string, string) ([]obsidian.FileMove, error) { return nil, nil }
+func (m *CustomMockNoteForSingleMatch) MoveFiles(string, []obsidian.FileMove) ([]obsidian.FileMove, error) { return nil, nil }
+func (m *CustomMockNoteForSingleMatch) PlanAttachmentMoves(string, string, string) ([]obsidian.FileMove, error) { return nil, nil }
+func (m *CustomMockNoteForSingleMatch) MoveAttachments(string, string, string) ([]obsidian.FileMove, error) { return nil, nil }
+func (m *CustomMockNoteForSingleMatch) UnusedAttachments(string, string) ([]string, error) { return nil, nil }
 func (m *CustomMockNoteForSingleMatch) UpdateLinks(string, string, string) error   { return nil }
 func (m *CustomMockNoteForSingleMatch) GetContents(string, string) (string, error) { return "", nil }
 func (m *CustomMockNoteForSingleMatch) SetContents(string, string, string) error   { return nil }
@@ -25,6 +30,15 @@ func (m *CustomMockNoteForSingleMatch) SearchNotesWithSnippets(string, string) (
 		{FilePath: "test-note.md", LineNumber: 5, MatchLine: "test content"},
 	}, nil
 }
+func (m *CustomMockNoteForSingleMatch) SearchNotesRanked(string, string) ([]obsidian.NoteMatch, error) {
+	return m.SearchNotesWithSnippets("", "")
+}
+func (m *CustomMockNoteForSingleMatch) SearchNotesQuery(string, string) ([]obsidian.NoteMatch, error) {
+	return m.SearchNotesWithSnippets("", "")
+}
+func (m *CustomMockNoteForSingleMatch) SearchNotesRegex(string, string, obsidian.RegexSearchOptions) ([]obsidian.NoteMatch, error) {
+	return m.SearchNotesWithSnippets("", "")
+}
 func (m *CustomMockNoteForSingleMatch) FindBacklinks(string, string) ([]obsidian.NoteMatch, error) {
 	return nil, nil
 }
@@ -36,7 +50,7 @@ func TestSearchNotesContent(t *testing.T) {
 		note := mocks.MockNoteManager{}
 		fuzzyFinder := mocks.MockFuzzyFinder{}
 
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "test", false)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test"})
 		assert.NoError(t, err)
 	})
 
@@ -46,7 +60,7 @@ func TestSearchNotesContent(t *testing.T) {
 		note := mocks.MockNoteManager{NoMatches: true}
 		fuzzyFinder := mocks.MockFuzzyFinder{}
 
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "nonexistent", false)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "nonexistent"})
 		assert.NoError(t, err)
 	})
 
@@ -58,7 +72,7 @@ func TestSearchNotesContent(t *testing.T) {
 		}
 		fuzzyFinder := mocks.MockFuzzyFinder{}
 
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "test", false)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test"})
 		assert.Error(t, err)
 	})
 
@@ -70,7 +84,7 @@ func TestSearchNotesContent(t *testing.T) {
 		note := mocks.MockNoteManager{}
 		fuzzyFinder := mocks.MockFuzzyFinder{}
 
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "test", false)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test"})
 		assert.Error(t, err)
 	})
 
@@ -82,7 +96,7 @@ func TestSearchNotesContent(t *testing.T) {
 		note := mocks.MockNoteManager{}
 		fuzzyFinder := mocks.MockFuzzyFinder{}
 
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "test", false)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test"})
 		assert.Error(t, err)
 	})
 
@@ -94,7 +108,7 @@ func TestSearchNotesContent(t *testing.T) {
 			FindErr: errors.New("fuzzy finder error"),
 		}
 
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "test", false)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test"})
 		assert.Error(t, err)
 	})
 
@@ -106,7 +120,7 @@ func TestSearchNotesContent(t *testing.T) {
 		note := mocks.MockNoteManager{}
 		fuzzyFinder := mocks.MockFuzzyFinder{}
 
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "test", false)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test"})
 		assert.Error(t, err)
 	})
 
@@ -126,7 +140,7 @@ func TestSearchNotesContent(t *testing.T) {
 		os.Setenv("EDITOR", "true")
 
 		// Act - test with editor flag enabled
-		err := actions.SearchNotesContent(&vault, note, &uri, &fuzzyFinder, "test", true)
+		err := actions.SearchNotesContent(&vault, note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test", UseEditor: true})
 		
 		// Assert - should succeed without calling URI execute
 		assert.NoError(t, err)
@@ -149,7 +163,7 @@ func TestSearchNotesContent(t *testing.T) {
 		os.Setenv("EDITOR", "true")
 
 		// Act - test with editor flag enabled
-		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, "test", true)
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test", UseEditor: true})
 		
 		// Assert - should succeed without calling URI execute
 		assert.NoError(t, err)
@@ -170,9 +184,78 @@ func TestSearchNotesContent(t *testing.T) {
 		os.Setenv("EDITOR", "false") // 'false' command always fails
 
 		// Act - test with editor flag enabled
-		err := actions.SearchNotesContent(&vault, note, &uri, &fuzzyFinder, "test", true)
+		err := actions.SearchNotesContent(&vault, note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test", UseEditor: true})
 		
 		// Assert - should fail due to editor failure
 		assert.Error(t, err)
 	})
 }
+
+func TestSearchNotesContentRanked(t *testing.T) {
+	t.Run("Ranked search shows best hits first", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		uri := mocks.MockUriManager{}
+		note := mocks.MockNoteManager{}
+		fuzzyFinder := mocks.MockFuzzyFinder{SelectedIndex: 0}
+
+		err := actions.SearchNotesContent(&vault, &note, &uri, &fuzzyFinder, actions.SearchContentParams{SearchTerm: "test", Ranked: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "note2.md", uri.LastParams["file"])
+	})
+}
+
+func TestFindNotesContent(t *testing.T) {
+	t.Run("Returns substring matches", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{}
+
+		matches, err := actions.FindNotesContent(&vault, &note, actions.SearchContentParams{SearchTerm: "test"})
+		assert.NoError(t, err)
+		assert.Equal(t, "note1.md", matches[0].FilePath)
+	})
+
+	t.Run("Returns ranked matches", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{}
+
+		matches, err := actions.FindNotesContent(&vault, &note, actions.SearchContentParams{SearchTerm: "test", Ranked: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "note2.md", matches[0].FilePath)
+		assert.Equal(t, 2.5, matches[0].Score)
+	})
+
+	t.Run("Returns Obsidian query matches", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{}
+
+		matches, err := actions.FindNotesContent(&vault, &note, actions.SearchContentParams{SearchTerm: "path:Projects tag:#todo", Query: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "Projects/plan.md", matches[0].FilePath)
+	})
+
+	t.Run("Returns regex matches", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{}
+
+		matches, err := actions.FindNotesContent(&vault, &note, actions.SearchContentParams{SearchTerm: `TODO\((\w+)\)`, Regex: true})
+		assert.NoError(t, err)
+		assert.Equal(t, "note3.md", matches[0].FilePath)
+	})
+
+	t.Run("Applies limit", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{}
+
+		matches, err := actions.FindNotesContent(&vault, &note, actions.SearchContentParams{SearchTerm: "test", Limit: 1})
+		assert.NoError(t, err)
+		assert.Len(t, matches, 1)
+	})
+
+	t.Run("Vault error propagates", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{PathError: errors.New("vault path error")}
+		note := mocks.MockNoteManager{}
+
+		_, err := actions.FindNotesContent(&vault, &note, actions.SearchContentParams{SearchTerm: "test"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,81 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+// Formats accepted by WriteMatches.
+const (
+	MatchFormatPlain  = "plain"
+	MatchFormatTSV    = "tsv"
+	MatchFormatJSON   = "json"
+	MatchFormatNDJSON = "ndjson"
+)
+
+// WriteMatches prints search matches for scripts in the given format:
+//
+//   - plain: the aligned "path:line | snippet" rows shown in the picker
+//   - tsv: path, line, start, end, score and snippet separated by tabs
+//   - json: a single array of match objects
+//   - ndjson: one match object per line
+func WriteMatches(w io.Writer, matches []obsidian.NoteMatch, format string) error {
+	switch format {
+	case MatchFormatPlain, "":
+		for _, line := range FormatMatchesForDisplay(matches) {
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return err
+			}
+		}
+		return nil
+	case MatchFormatTSV:
+		for _, m := range matches {
+			fields := []string{
+				tsvField(m.FilePath),
+				strconv.Itoa(m.LineNumber),
+				strconv.Itoa(m.MatchStart),
+				strconv.Itoa(m.MatchEnd),
+				strconv.FormatFloat(m.Score, 'f', -1, 64),
+				tsvField(m.MatchLine),
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	case MatchFormatJSON:
+		if matches == nil {
+			matches = []obsidian.NoteMatch{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(matches)
+	case MatchFormatNDJSON:
+		encoder := json.NewEncoder(w)
+		for _, m := range matches {
+			if err := encoder.Encode(m); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("unknown format %q: use plain, tsv, json or ndjson", format))
+}
+
+// tsvField replaces characters that would break a TSV row.
+func tsvField(s string) string {
+	return strings.NewReplacer("\t", " ", "\r", " ", "\n", " ").Replace(s)
+}
+
+// limitMatches returns at most limit matches; a limit of 0 or less keeps all.
+func limitMatches(matches []obsidian.NoteMatch, limit int) []obsidian.NoteMatch {
+	if limit > 0 && len(matches) > limit {
+		return matches[:limit]
+	}
+	return matches
+}
@@ -0,0 +1,61 @@
+package actions_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMatches(t *testing.T) {
+	matches := []obsidian.NoteMatch{
+		{FilePath: "a.md", LineNumber: 2, MatchLine: "has\ttab", MatchStart: 10, MatchEnd: 13, Score: 1.5},
+		{FilePath: "dir/b.md", LineNumber: 0, MatchLine: "(filename match: b.md)"},
+	}
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{actions.MatchFormatPlain, "a.md:2   | has\ttab\ndir/b.md | (filename match: b.md)\n"},
+		{actions.MatchFormatTSV, "a.md\t2\t10\t13\t1.5\thas tab\ndir/b.md\t0\t0\t0\t0\t(filename match: b.md)\n"},
+		{actions.MatchFormatNDJSON, `{"path":"a.md","line":2,"snippet":"has\ttab","start":10,"end":13,"score":1.5}` + "\n" +
+			`{"path":"dir/b.md","line":0,"snippet":"(filename match: b.md)","start":0,"end":0}` + "\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var out bytes.Buffer
+			err := actions.WriteMatches(&out, matches, test.format)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, out.String())
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		err := actions.WriteMatches(&out, matches[1:], actions.MatchFormatJSON)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `[{"path":"dir/b.md","line":0,"snippet":"(filename match: b.md)","start":0,"end":0}]`, out.String())
+	})
+
+	t.Run("json with no matches is an empty array", func(t *testing.T) {
+		var out bytes.Buffer
+		err := actions.WriteMatches(&out, nil, actions.MatchFormatJSON)
+		assert.NoError(t, err)
+		assert.Equal(t, "[]\n", out.String())
+	})
+
+	t.Run("Plain output without snippets lists paths", func(t *testing.T) {
+		var out bytes.Buffer
+		err := actions.WriteMatches(&out, []obsidian.NoteMatch{{FilePath: "a.md"}}, actions.MatchFormatPlain)
+		assert.NoError(t, err)
+		assert.Equal(t, "a.md\n", out.String())
+	})
+
+	t.Run("Unknown format", func(t *testing.T) {
+		err := actions.WriteMatches(&bytes.Buffer{}, matches, "xml")
+		assert.EqualError(t, err, `unknown format "xml": use plain, tsv, json or ndjson`)
+	})
+}
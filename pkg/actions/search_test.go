@@ -7,6 +7,7 @@ import (
 
 	"github.com/Yakitrak/notesmd-cli/mocks"
 	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -125,3 +126,31 @@ func TestSearchNotes(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+func TestFindNotes(t *testing.T) {
+	t.Run("Filters notes by path", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{}
+
+		matches, err := actions.FindNotes(&vault, &note, "NOTE2", 0)
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{{FilePath: "note2"}}, matches)
+	})
+
+	t.Run("Applies limit", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{}
+
+		matches, err := actions.FindNotes(&vault, &note, "", 2)
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{{FilePath: "note1"}, {FilePath: "note2"}}, matches)
+	})
+
+	t.Run("GetNotesList returns an error", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{GetContentsError: errors.New("list error")}
+
+		_, err := actions.FindNotes(&vault, &note, "", 0)
+		assert.EqualError(t, err, "list error")
+	})
+}
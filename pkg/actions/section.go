@@ -0,0 +1,70 @@
+package actions
+
+import (
+	"strings"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+type SectionParams struct {
+	NoteName string
+	// Path is a heading, or headings separated by ">", e.g. "Projects > Alpha".
+	Path string
+	// Operation is obsidian.SectionReplace, SectionPrepend, SectionAppend or
+	// SectionDelete.
+	Operation string
+	Content   string
+}
+
+// ReadSection returns the content under the heading at params.Path, without
+// the blank lines around it.
+func ReadSection(vault obsidian.VaultManager, note obsidian.NoteManager, params SectionParams) (string, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return "", err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return "", err
+	}
+
+	contents, err := note.GetContents(vaultPath, params.NoteName)
+	if err != nil {
+		return "", err
+	}
+
+	section, err := obsidian.FindSection(contents, params.Path)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(section.Body(contents), "\r\n"), nil
+}
+
+// EditSection applies params.Operation to the section at params.Path and
+// writes the note back.
+func EditSection(vault obsidian.VaultManager, note obsidian.NoteManager, params SectionParams) error {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return err
+	}
+
+	contents, err := note.GetContents(vaultPath, params.NoteName)
+	if err != nil {
+		return err
+	}
+
+	updated, err := obsidian.EditSection(contents, params.Path, params.Operation, NormalizeContent(params.Content))
+	if err != nil {
+		return err
+	}
+	if updated == contents {
+		return nil
+	}
+	return note.SetContents(vaultPath, params.NoteName, updated)
+}
@@ -0,0 +1,83 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/mocks"
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadSection(t *testing.T) {
+	t.Run("Prints the content under the heading", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "# Projects\n## Alpha\n\nFirst\n### Notes\n- a\n\n## Beta\n"}
+
+		content, err := actions.ReadSection(&vault, &note, actions.SectionParams{NoteName: "note", Path: "Projects > Alpha"})
+
+		assert.NoError(t, err)
+		assert.Equal(t, "First\n### Notes\n- a", content)
+	})
+
+	t.Run("Missing section", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{Contents: "# Projects\n"}
+
+		_, err := actions.ReadSection(&vault, &note, actions.SectionParams{NoteName: "note", Path: "Alpha"})
+
+		assert.Equal(t, obsidian.ErrCodeSectionNotFound, obsidian.ErrorCode(err))
+	})
+
+	t.Run("GetContents returns an error", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{GetContentsError: errors.New("Failed to read note")}
+
+		_, err := actions.ReadSection(&vault, &note, actions.SectionParams{NoteName: "note", Path: "Alpha"})
+
+		assert.Equal(t, note.GetContentsError, err)
+	})
+}
+
+func TestEditSection(t *testing.T) {
+	t.Run("Writes the edited note", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		notePath := filepath.Join(vaultPath, "plan.md")
+		assert.NoError(t, os.WriteFile(notePath, []byte("## Alpha\n- a\n\n## Beta\n- b\n"), 0644))
+		vault := mocks.MockVaultOperator{Name: "myVault", PathValue: vaultPath}
+
+		err := actions.EditSection(&vault, &obsidian.Note{}, actions.SectionParams{
+			NoteName:  "plan",
+			Path:      "Alpha",
+			Operation: obsidian.SectionAppend,
+			Content:   `- new\n- newer`,
+		})
+
+		assert.NoError(t, err)
+		written, _ := os.ReadFile(notePath)
+		assert.Equal(t, "## Alpha\n- a\n- new\n- newer\n\n## Beta\n- b\n", string(written))
+	})
+
+	t.Run("SetContents returns an error", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{Name: "myVault"}
+		note := mocks.MockNoteManager{
+			Contents:         "## Alpha\n- a\n",
+			SetContentsError: errors.New("Failed to write note"),
+		}
+
+		err := actions.EditSection(&vault, &note, actions.SectionParams{NoteName: "note", Path: "Alpha", Operation: obsidian.SectionDelete})
+
+		assert.Equal(t, note.SetContentsError, err)
+	})
+
+	t.Run("vault.DefaultName returns an error", func(t *testing.T) {
+		vault := mocks.MockVaultOperator{DefaultNameErr: errors.New("Failed to get vault name")}
+
+		err := actions.EditSection(&vault, &mocks.MockNoteManager{}, actions.SectionParams{NoteName: "note", Path: "Alpha", Operation: obsidian.SectionDelete})
+
+		assert.Equal(t, vault.DefaultNameErr, err)
+	})
+}
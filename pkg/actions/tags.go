@@ -0,0 +1,51 @@
+package actions
+
+import (
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+// ListTags returns every tag in the vault with the number of notes using it.
+func ListTags(vault obsidian.VaultManager) ([]obsidian.TagCount, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	return obsidian.ListTags(vaultPath)
+}
+
+// TagNotes returns the notes tagged with tag or one of its nested tags.
+func TagNotes(vault obsidian.VaultManager, tag string) ([]string, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	return obsidian.NotesWithTag(vaultPath, tag)
+}
+
+// RenameTag renames a tag, and the tags nested under it, across the whole
+// vault and returns the notes that were changed.
+func RenameTag(vault obsidian.VaultManager, oldTag, newTag string) ([]string, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	return obsidian.RenameTag(vaultPath, oldTag, newTag)
+}
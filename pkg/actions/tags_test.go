@@ -0,0 +1,47 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTagActions(t *testing.T) {
+	t.Run("List, find and rename tags", func(t *testing.T) {
+		vaultDir := t.TempDir()
+		err := os.WriteFile(filepath.Join(vaultDir, "note.md"), []byte("---\ntags: [draft]\n---\n#draft/idea"), 0644)
+		assert.NoError(t, err)
+		vault := &vaultStub{path: vaultDir}
+
+		tags, err := actions.ListTags(vault)
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.TagCount{{Tag: "draft", Count: 1}, {Tag: "draft/idea", Count: 1}}, tags)
+
+		notes, err := actions.TagNotes(vault, "draft")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"note.md"}, notes)
+
+		changed, err := actions.RenameTag(vault, "draft", "final")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"note.md"}, changed)
+		content, _ := os.ReadFile(filepath.Join(vaultDir, "note.md"))
+		assert.Equal(t, "---\ntags: [final]\n---\n#final/idea", string(content))
+	})
+
+	t.Run("Vault errors propagate", func(t *testing.T) {
+		vault := &vaultStub{defaultErr: errors.New("no default")}
+		_, err := actions.ListTags(vault)
+		assert.EqualError(t, err, "no default")
+
+		vault = &vaultStub{pathErr: errors.New("no path")}
+		_, err = actions.TagNotes(vault, "draft")
+		assert.EqualError(t, err, "no path")
+		_, err = actions.RenameTag(vault, "draft", "final")
+		assert.EqualError(t, err, "no path")
+	})
+}
@@ -0,0 +1,84 @@
+package actions
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+)
+
+type TasksParams struct {
+	Status    string
+	DueBefore string
+	DueAfter  string
+	Due       string
+	Path      string
+	Tag       string
+}
+
+// ListTasks returns the tasks in the vault selected by params. Dates may be
+// given as YYYY-MM-DD or as "today", "tomorrow" or "yesterday".
+func ListTasks(vault obsidian.VaultManager, params TasksParams) ([]obsidian.Task, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return nil, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return nil, err
+	}
+
+	if !obsidian.ValidTaskStatusFilter(params.Status) {
+		return nil, obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid task status %q: use open, todo, in-progress, done, cancelled or all", params.Status))
+	}
+
+	filter := obsidian.TaskFilter{Status: params.Status, Path: params.Path, Tag: params.Tag}
+	now := time.Now()
+	if filter.DueBefore, err = resolveTaskDate(params.DueBefore, now); err != nil {
+		return nil, err
+	}
+	if filter.DueAfter, err = resolveTaskDate(params.DueAfter, now); err != nil {
+		return nil, err
+	}
+	if filter.DueOn, err = resolveTaskDate(params.Due, now); err != nil {
+		return nil, err
+	}
+
+	return obsidian.FindTasks(vaultPath, filter)
+}
+
+// ToggleTask marks the task on the given line of a note done, or not done if
+// it already is.
+func ToggleTask(vault obsidian.VaultManager, noteName string, line int) (obsidian.Task, error) {
+	_, err := vault.DefaultName()
+	if err != nil {
+		return obsidian.Task{}, err
+	}
+
+	vaultPath, err := vault.Path()
+	if err != nil {
+		return obsidian.Task{}, err
+	}
+
+	return obsidian.ToggleTask(vaultPath, noteName, line, time.Now())
+}
+
+// resolveTaskDate turns a date filter into YYYY-MM-DD form.
+func resolveTaskDate(value string, now time.Time) (string, error) {
+	switch strings.ToLower(value) {
+	case "":
+		return "", nil
+	case "today":
+		return now.Format("2006-01-02"), nil
+	case "tomorrow":
+		return now.AddDate(0, 0, 1).Format("2006-01-02"), nil
+	case "yesterday":
+		return now.AddDate(0, 0, -1).Format("2006-01-02"), nil
+	}
+	if _, err := time.Parse("2006-01-02", value); err != nil {
+		return "", obsidian.NewError(obsidian.ErrCodeInvalidArgument, fmt.Sprintf("invalid date %q: use YYYY-MM-DD, today, tomorrow or yesterday", value))
+	}
+	return value, nil
+}
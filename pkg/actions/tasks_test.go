@@ -0,0 +1,60 @@
+package actions_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/actions"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskActions(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	tomorrow := time.Now().AddDate(0, 0, 1).Format("2006-01-02")
+
+	t.Run("List and toggle tasks", func(t *testing.T) {
+		vaultDir := t.TempDir()
+		content := "- [ ] Due today 📅 " + today + "\n- [ ] Due tomorrow 📅 " + tomorrow + "\n- [x] Done\n"
+		err := os.WriteFile(filepath.Join(vaultDir, "todo.md"), []byte(content), 0644)
+		assert.NoError(t, err)
+		vault := &vaultStub{path: vaultDir}
+
+		tasks, err := actions.ListTasks(vault, actions.TasksParams{Status: "open", DueBefore: "tomorrow"})
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1)
+		assert.Equal(t, "Due today", tasks[0].Text)
+
+		task, err := actions.ToggleTask(vault, "todo", 1)
+		assert.NoError(t, err)
+		assert.Equal(t, "done", task.Status)
+		assert.Equal(t, today, task.Completed)
+
+		tasks, err = actions.ListTasks(vault, actions.TasksParams{Status: "done"})
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 2)
+	})
+
+	t.Run("Invalid filters are rejected", func(t *testing.T) {
+		vault := &vaultStub{path: t.TempDir()}
+
+		_, err := actions.ListTasks(vault, actions.TasksParams{Status: "finished"})
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+
+		_, err = actions.ListTasks(vault, actions.TasksParams{Due: "next week"})
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Vault errors propagate", func(t *testing.T) {
+		vault := &vaultStub{defaultErr: errors.New("no default")}
+		_, err := actions.ListTasks(vault, actions.TasksParams{})
+		assert.EqualError(t, err, "no default")
+
+		vault = &vaultStub{pathErr: errors.New("no path")}
+		_, err = actions.ToggleTask(vault, "todo", 1)
+		assert.EqualError(t, err, "no path")
+	})
+}
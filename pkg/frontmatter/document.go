@@ -0,0 +1,323 @@
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultIndent is the indentation of nested values when the frontmatter
+// has none to copy, matching what Obsidian writes.
+const defaultIndent = 2
+
+const quotedStyles = yaml.DoubleQuotedStyle | yaml.SingleQuotedStyle
+
+// document is note content with its frontmatter parsed into a yaml.Node
+// tree. Edits replace only the lines of the key they touch, so everything
+// else in the frontmatter keeps its order, comments and formatting.
+type document struct {
+	head  string   // opening delimiter line, with its line ending
+	lines []string // frontmatter lines, without line endings
+	tail  string   // closing delimiter line and everything after it
+	eol   string
+	// mapping is the top-level mapping, or nil when the frontmatter is empty.
+	mapping *yaml.Node
+}
+
+func parseDocument(content string) (*document, error) {
+	if !HasFrontmatter(content) {
+		return nil, errors.New(NoFrontmatterError)
+	}
+
+	first := strings.Index(content, "\n")
+	if first < 0 {
+		return nil, errors.New(InvalidFrontmatterError)
+	}
+	doc := &document{head: content[:first+1], eol: "\n"}
+	if strings.HasSuffix(content[:first], "\r") {
+		doc.eol = "\r\n"
+	}
+
+	rest := content[first+1:]
+	for {
+		end := strings.Index(rest, "\n")
+		line := rest
+		if end >= 0 {
+			line = rest[:end]
+		}
+		if strings.TrimSpace(line) == Delimiter {
+			doc.tail = rest
+			break
+		}
+		if end < 0 {
+			return nil, errors.New(InvalidFrontmatterError)
+		}
+		doc.lines = append(doc.lines, strings.TrimSuffix(line, "\r"))
+		rest = rest[end+1:]
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(doc.lines, "\n")), &root); err != nil {
+		return nil, errors.New(InvalidFrontmatterError)
+	}
+	if len(root.Content) > 0 {
+		if root.Content[0].Kind != yaml.MappingNode {
+			return nil, errors.New(InvalidFrontmatterError)
+		}
+		doc.mapping = root.Content[0]
+	}
+	return doc, nil
+}
+
+// loadDocument is parseDocument, except that content without frontmatter
+// gets an empty frontmatter block for keys to be added to.
+func loadDocument(content string) (*document, error) {
+	if !HasFrontmatter(content) {
+		return &document{head: Delimiter + "\n", tail: Delimiter + "\n" + content, eol: "\n"}, nil
+	}
+	return parseDocument(content)
+}
+
+// String reassembles the note content.
+func (d *document) String() string {
+	var b strings.Builder
+	b.WriteString(d.head)
+	for _, line := range d.lines {
+		b.WriteString(line)
+		b.WriteString(d.eol)
+	}
+	b.WriteString(d.tail)
+	return b.String()
+}
+
+// body returns the content after the closing delimiter line.
+func (d *document) body() string {
+	if i := strings.Index(d.tail, "\n"); i >= 0 {
+		return d.tail[i+1:]
+	}
+	return ""
+}
+
+// find returns the index in d.mapping.Content of key's key node, or -1.
+func (d *document) find(key string) int {
+	if d.mapping == nil {
+		return -1
+	}
+	for i := 0; i < len(d.mapping.Content); i += 2 {
+		if d.mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// value returns the value of a top-level key, or nil.
+func (d *document) value(key string) *yaml.Node {
+	if i := d.find(key); i >= 0 {
+		return d.mapping.Content[i+1]
+	}
+	return nil
+}
+
+// keyRange returns the lines [start, end) holding the key at index i and its
+// value. Blank lines and comments between the value and the next key are
+// left out.
+func (d *document) keyRange(i int) (int, int) {
+	start := d.mapping.Content[i].Line - 1
+	end := len(d.lines)
+	if i+2 < len(d.mapping.Content) {
+		end = d.mapping.Content[i+2].Line - 1
+	}
+	return start, d.trimComments(start, end, d.mapping.Content[i+1])
+}
+
+// trimComments returns end moved back past the blank lines and comments at
+// the end of the lines [start, end) holding value, keeping the first line.
+func (d *document) trimComments(start, end int, value *yaml.Node) int {
+	// Lines starting with "#" can be content of a block scalar, unless they
+	// are not indented.
+	blockScalar := value.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0
+	for end > start+1 {
+		line := d.lines[end-1]
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !(strings.HasPrefix(trimmed, "#") && (!blockScalar || strings.HasPrefix(line, "#"))) {
+			break
+		}
+		end--
+	}
+	return end
+}
+
+// headCommentStart extends start, the first line of the key at index i, to
+// take in the comment lines directly above the key.
+func (d *document) headCommentStart(i, start int) int {
+	n := 0
+	if comment := d.mapping.Content[i].HeadComment; comment != "" {
+		n = strings.Count(comment, "\n") + 1
+	}
+	for ; n > 0 && start > 0 && strings.HasPrefix(d.lines[start-1], "#"); n-- {
+		start--
+	}
+	return start
+}
+
+// splice replaces the lines [start, end) with lines.
+func (d *document) splice(start, end int, lines []string) {
+	updated := make([]string, 0, len(d.lines)-(end-start)+len(lines))
+	updated = append(updated, d.lines[:start]...)
+	updated = append(updated, lines...)
+	d.lines = append(updated, d.lines[end:]...)
+}
+
+// set replaces the value of key, or adds the key after the last one.
+func (d *document) set(key string, value *yaml.Node) error {
+	if i := d.find(key); i >= 0 {
+		lines, err := d.encodePair(d.mapping.Content[i], value)
+		if err != nil {
+			return err
+		}
+		start, end := d.keyRange(i)
+		d.splice(start, end, lines)
+		return nil
+	}
+
+	lines, err := d.encodePair(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, value)
+	if err != nil {
+		return err
+	}
+	end := len(d.lines)
+	for end > 0 && strings.TrimSpace(d.lines[end-1]) == "" {
+		end--
+	}
+	d.splice(end, end, lines)
+	return nil
+}
+
+// encodePair encodes a single key and value as frontmatter lines, indented
+// like the rest of the document.
+func (d *document) encodePair(key, value *yaml.Node) ([]string, error) {
+	// Comments above and below the pair stay where they are in the document.
+	k, v := *key, *value
+	k.HeadComment, k.FootComment = "", ""
+	v.HeadComment, v.FootComment = "", ""
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(d.indent())
+	if err := enc.Encode(&yaml.Node{Kind: yaml.MappingNode, Content: []*yaml.Node{&k, &v}}); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+// indent returns the indentation of the first nested block list or map, or
+// defaultIndent when there is none.
+func (d *document) indent() int {
+	if d.mapping == nil {
+		return defaultIndent
+	}
+	for i := 1; i < len(d.mapping.Content); i += 2 {
+		value := d.mapping.Content[i]
+		if value.Style&yaml.FlowStyle != 0 || len(value.Content) == 0 {
+			continue
+		}
+		n := 0
+		switch value.Kind {
+		case yaml.SequenceNode:
+			line := d.lines[value.Content[0].Line-1]
+			n = len(line) - len(strings.TrimLeft(line, " "))
+		case yaml.MappingNode:
+			n = value.Content[0].Column - d.mapping.Content[i-1].Column
+		default:
+			continue
+		}
+		// yaml.v3 cannot write lists level with their key, or indent
+		// beyond 9 spaces.
+		if n < 2 {
+			n = 2
+		} else if n > 9 {
+			n = 9
+		}
+		return n
+	}
+	return defaultIndent
+}
+
+// valueNode prepares node to replace old. Lists and maps are written in
+// block style, like frontmatter usually is, unless old was in flow style. A
+// plain string keeps the quoting of an old string, and old's line comment is
+// kept.
+func valueNode(node, old *yaml.Node) *yaml.Node {
+	if node.Kind == yaml.SequenceNode || node.Kind == yaml.MappingNode {
+		node.Style &^= yaml.FlowStyle
+		if old != nil && old.Kind == node.Kind {
+			node.Style |= old.Style & yaml.FlowStyle
+		}
+	}
+	if old == nil {
+		return node
+	}
+	if node.Kind == yaml.ScalarNode && old.Kind == yaml.ScalarNode && node.Tag == "!!str" && node.Style == 0 {
+		node.Style = old.Style & quotedStyles
+	}
+	node.LineComment = old.LineComment
+	return node
+}
+
+// encodeScalar returns the YAML text of a scalar node.
+func encodeScalar(node *yaml.Node) (string, error) {
+	data, err := yaml.Marshal(node)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// scalarEnd returns the byte offset just past the scalar starting at start
+// in line, written in the given style.
+func scalarEnd(line string, start int, style yaml.Style) int {
+	switch {
+	case style&yaml.DoubleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			switch line[i] {
+			case '\\':
+				i++
+			case '"':
+				return i + 1
+			}
+		}
+	case style&yaml.SingleQuotedStyle != 0:
+		for i := start + 1; i < len(line); i++ {
+			if line[i] == '\'' {
+				if i+1 < len(line) && line[i+1] == '\'' {
+					i++
+					continue
+				}
+				return i + 1
+			}
+		}
+	default:
+		for i := start; i < len(line); i++ {
+			if line[i] == ':' && (i+1 == len(line) || line[i+1] == ' ' || line[i+1] == '\t') {
+				return len(strings.TrimRight(line[:i], " \t"))
+			}
+		}
+	}
+	return len(line)
+}
+
+// runeOffset returns the byte offset of the n-th rune of s.
+func runeOffset(s string, n int) int {
+	offset := 0
+	for i := 0; i < n && offset < len(s); i++ {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
@@ -9,9 +9,15 @@ import (
 )
 
 const (
-	Delimiter              = "---"
-	NoFrontmatterError     = "note does not contain frontmatter"
+	Delimiter               = "---"
+	NoFrontmatterError      = "note does not contain frontmatter"
 	InvalidFrontmatterError = "frontmatter contains invalid YAML"
+	KeyNotFoundError        = "frontmatter key not found"
+	KeyExistsError          = "frontmatter key already exists"
+	InvalidKeyPathError     = "invalid frontmatter key path"
+	KeyPathTypeError        = "frontmatter key path goes through a value that is not a map or list"
+	IndexOutOfRangeError    = "frontmatter list index out of range"
+	NotAListError           = "frontmatter value is not a list"
 )
 
 // Parse extracts and parses frontmatter from note content.
@@ -46,100 +52,384 @@ func HasFrontmatter(content string) bool {
 	return strings.TrimSpace(lines[0]) == Delimiter
 }
 
-// SetKey updates or adds a key in the frontmatter, returning the full updated content.
-// If no frontmatter exists, it creates new frontmatter with the key.
+// SetKey sets the value at a key path in the frontmatter, returning the full
+// updated content. If no frontmatter exists, it creates new frontmatter with
+// the key. The path is a key, or a dotted path through nested maps in which
+// [n] selects a list item, e.g. "project.owner" or "aliases[0]"; maps missing
+// along the path are created, and so are lists for [0]. An existing top-level
+// key containing dots, such as "file.name", is matched as it is rather than
+// as a path. The value is read as YAML (see parseValue). Only the lines of
+// the top-level key change: the order, comments and styles of the other keys
+// are kept, as are the quoting and list style of the old value.
 func SetKey(content, key, value string) (string, error) {
-	parsedValue := parseValue(value)
+	doc, err := loadDocument(content)
+	if err != nil {
+		return "", err
+	}
+	parts, err := resolvePath(doc.mapping, key)
+	if err != nil {
+		return "", err
+	}
 
-	if !HasFrontmatter(content) {
-		// Create new frontmatter
-		fm := map[string]interface{}{key: parsedValue}
-		fmStr, err := yaml.Marshal(fm)
-		if err != nil {
+	top := doc.value(parts[0].key)
+	node := valueNode(parseValue(value), lookup(top, parts[1:]))
+	updated, err := setIn(top, parts[1:], node)
+	if err != nil {
+		return "", err
+	}
+	if err := doc.set(parts[0].key, updated); err != nil {
+		return "", err
+	}
+	return doc.String(), nil
+}
+
+// DeleteKey removes the value at a key path from the frontmatter, returning
+// the full updated content. Deleting a top-level key also removes the
+// comment lines directly above it. A missing key leaves content unchanged.
+func DeleteKey(content, key string) (string, error) {
+	doc, err := parseDocument(content)
+	if err != nil {
+		return "", err
+	}
+	parts, err := resolvePath(doc.mapping, key)
+	if err != nil {
+		return "", err
+	}
+
+	if doc.mapping == nil {
+		return "", errors.New(NoFrontmatterError)
+	}
+
+	i := doc.find(parts[0].key)
+	if i < 0 {
+		return content, nil
+	}
+
+	if len(parts) > 1 {
+		top := doc.mapping.Content[i+1]
+		if !deleteIn(top, parts[1:]) {
+			return content, nil
+		}
+		if err := doc.set(parts[0].key, top); err != nil {
 			return "", err
 		}
-		return Delimiter + "\n" + string(fmStr) + Delimiter + "\n" + content, nil
+		return doc.String(), nil
 	}
 
-	// Parse existing frontmatter
-	fm, body, err := Parse(content)
+	// If no keys left, return just the body
+	if len(doc.mapping.Content) == 2 {
+		return strings.TrimPrefix(doc.body(), doc.eol), nil
+	}
+
+	start, end := doc.keyRange(i)
+	start = doc.headCommentStart(i, start)
+	doc.splice(start, end, nil)
+	return doc.String(), nil
+}
+
+// RenameKey renames the key at a key path to newKey within the same map,
+// returning the full updated content. It fails if the key is missing or
+// newKey is already present. Only the key itself is rewritten; its value and
+// position are kept.
+func RenameKey(content, oldKey, newKey string) (string, error) {
+	doc, err := parseDocument(content)
+	if err != nil {
+		return "", err
+	}
+	parts, err := resolvePath(doc.mapping, oldKey)
 	if err != nil {
 		return "", err
 	}
+	last := parts[len(parts)-1]
+	if last.index >= 0 {
+		return "", errors.New(InvalidKeyPathError)
+	}
 
-	if fm == nil {
-		fm = make(map[string]interface{})
+	parent := lookup(doc.mapping, parts[:len(parts)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return "", errors.New(KeyNotFoundError)
+	}
+	var keyNode *yaml.Node
+	for i := 0; i < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == last.key {
+			keyNode = parent.Content[i]
+		}
+	}
+	if keyNode == nil {
+		return "", errors.New(KeyNotFoundError)
+	}
+	if child(parent, pathPart{key: newKey, index: -1}) != nil {
+		return "", errors.New(KeyExistsError)
 	}
 
-	// Update the key
-	fm[key] = parsedValue
+	encoded, err := encodeScalar(&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: newKey, Style: keyNode.Style & quotedStyles})
+	if err != nil {
+		return "", err
+	}
+	line := doc.lines[keyNode.Line-1]
+	start := runeOffset(line, keyNode.Column-1)
+	end := scalarEnd(line, start, keyNode.Style)
+	doc.lines[keyNode.Line-1] = line[:start] + encoded + line[end:]
+	return doc.String(), nil
+}
 
-	// Reconstruct content
-	fmStr, err := yaml.Marshal(fm)
+// AppendValue adds value to the list at a key path, returning the full
+// updated content. A missing key becomes a one-item list and a single value
+// becomes a list holding both values. A bracketed list value adds each of
+// its items. Items already in the list are not added again, so content is
+// returned unchanged when the list already contains every value. Items are
+// added in the list's existing style.
+func AppendValue(content, key, value string) (string, error) {
+	doc, err := loadDocument(content)
+	if err != nil {
+		return "", err
+	}
+	parts, err := resolvePath(doc.mapping, key)
 	if err != nil {
 		return "", err
 	}
 
-	return Delimiter + "\n" + string(fmStr) + Delimiter + "\n" + body, nil
-}
+	top := doc.value(parts[0].key)
+	old := lookup(top, parts[1:])
+	var list *yaml.Node
+	switch {
+	case old == nil || isNull(old):
+		list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq"}
+	case old.Kind == yaml.SequenceNode:
+		list = old
+	case old.Kind == yaml.ScalarNode:
+		existing := *old
+		existing.LineComment = ""
+		list = &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{&existing}, LineComment: old.LineComment}
+	default:
+		return "", errors.New(NotAListError)
+	}
 
-// DeleteKey removes a key from the frontmatter, returning the full updated content.
-func DeleteKey(content, key string) (string, error) {
-	if !HasFrontmatter(content) {
-		return "", errors.New(NoFrontmatterError)
+	var added []*yaml.Node
+	for _, item := range listItems(parseValue(value)) {
+		if !containsScalar(list.Content, item) && !containsScalar(added, item) {
+			added = append(added, item)
+		}
+	}
+	if len(added) == 0 {
+		return content, nil
 	}
 
-	fm, body, err := Parse(content)
+	if len(parts) == 1 && list == old && old.Style&yaml.FlowStyle == 0 && len(old.Content) > 0 {
+		// Add lines to a block list rather than re-encoding it.
+		if lines, ok := blockItemLines(doc.lines[old.Content[0].Line-1], added); ok {
+			_, end := doc.keyRange(doc.find(parts[0].key))
+			doc.splice(end, end, lines)
+			return doc.String(), nil
+		}
+	}
+
+	list.Content = append(list.Content, added...)
+	updated, err := setIn(top, parts[1:], list)
 	if err != nil {
 		return "", err
 	}
+	if err := doc.set(parts[0].key, updated); err != nil {
+		return "", err
+	}
+	return doc.String(), nil
+}
 
-	if fm == nil {
-		return "", errors.New(NoFrontmatterError)
+// RemoveValue removes value from the list at a key path, returning the full
+// updated content. A bracketed list value removes each of its items, and a
+// single value equal to value becomes an empty list. Content is returned
+// unchanged when there is nothing to remove.
+func RemoveValue(content, key, value string) (string, error) {
+	doc, err := parseDocument(content)
+	if err != nil {
+		return "", err
+	}
+	parts, err := resolvePath(doc.mapping, key)
+	if err != nil {
+		return "", err
 	}
 
-	// Delete the key
-	delete(fm, key)
+	top := doc.value(parts[0].key)
+	old := lookup(top, parts[1:])
+	if old == nil || isNull(old) {
+		return content, nil
+	}
+	if old.Kind == yaml.MappingNode {
+		return "", errors.New(NotAListError)
+	}
+	items := listItems(parseValue(value))
 
-	// If no keys left, return just the body
-	if len(fm) == 0 {
-		return strings.TrimPrefix(body, "\n"), nil
+	if old.Kind == yaml.ScalarNode {
+		if !containsScalar(items, old) {
+			return content, nil
+		}
+		empty := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", LineComment: old.LineComment}
+		updated, err := setIn(top, parts[1:], empty)
+		if err != nil {
+			return "", err
+		}
+		if err := doc.set(parts[0].key, updated); err != nil {
+			return "", err
+		}
+		return doc.String(), nil
+	}
+
+	var kept, removed []int
+	for j, existing := range old.Content {
+		if containsScalar(items, existing) {
+			removed = append(removed, j)
+		} else {
+			kept = append(kept, j)
+		}
+	}
+	if len(removed) == 0 {
+		return content, nil
+	}
+
+	if len(parts) == 1 && len(kept) > 0 && old.Style&yaml.FlowStyle == 0 {
+		// Drop the items' lines from a block list rather than re-encoding it,
+		// keeping the comments between them.
+		_, end := doc.keyRange(doc.find(parts[0].key))
+		for k := len(removed) - 1; k >= 0; k-- {
+			j := removed[k]
+			itemStart, itemEnd := old.Content[j].Line-1, end
+			if j+1 < len(old.Content) {
+				itemEnd = old.Content[j+1].Line - 1
+			}
+			doc.splice(itemStart, doc.trimComments(itemStart, itemEnd, old.Content[j]), nil)
+		}
+		return doc.String(), nil
 	}
 
-	// Reconstruct content
-	fmStr, err := yaml.Marshal(fm)
+	remaining := make([]*yaml.Node, 0, len(kept))
+	for _, j := range kept {
+		remaining = append(remaining, old.Content[j])
+	}
+	old.Content = remaining
+	updated, err := setIn(top, parts[1:], old)
 	if err != nil {
 		return "", err
 	}
+	if err := doc.set(parts[0].key, updated); err != nil {
+		return "", err
+	}
+	return doc.String(), nil
+}
+
+// HasKey reports whether the frontmatter has a value at a key path.
+func HasKey(content, key string) (bool, error) {
+	if _, err := parsePath(key); err != nil {
+		return false, err
+	}
+	if !HasFrontmatter(content) {
+		return false, nil
+	}
 
-	return Delimiter + "\n" + string(fmStr) + Delimiter + "\n" + body, nil
+	doc, err := parseDocument(content)
+	if err != nil {
+		return false, err
+	}
+	parts, err := resolvePath(doc.mapping, key)
+	if err != nil {
+		return false, err
+	}
+	return lookup(doc.mapping, parts) != nil, nil
 }
 
-// parseValue attempts to parse the value into appropriate Go types.
-// Supports: booleans, arrays (comma-separated in brackets), strings.
-func parseValue(value string) interface{} {
-	// Try boolean
-	if value == "true" {
-		return true
+// parseValue reads a value given on the command line as YAML, so that
+// numbers, booleans, null, dates and quoted strings keep their types and
+// bracketed lists and braced maps (JSON or YAML flow syntax) become lists
+// and maps. Anything else, including text YAML would read differently from
+// how it is written, is a plain string.
+func parseValue(value string) *yaml.Node {
+	str := stringNode(value)
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &doc); err != nil || len(doc.Content) != 1 {
+		return str
 	}
-	if value == "false" {
-		return false
+	node := doc.Content[0]
+	if hasComments(node) || node.Anchor != "" {
+		return str
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		if node.Style == 0 && node.Value != value {
+			return str
+		}
+		if node.Style&(yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			return str
+		}
+		return node
+	case yaml.SequenceNode, yaml.MappingNode:
+		if node.Style&yaml.FlowStyle == 0 {
+			return str
+		}
+		unquoteKeys(node)
+		return node
+	}
+	return str
+}
+
+// unquoteKeys drops the quotes around the string keys of the maps in node,
+// so that JSON such as {"owner": "Ann"} is written as owner: "Ann" like
+// other frontmatter keys. Keys that need quotes get them back when encoded.
+func unquoteKeys(node *yaml.Node) {
+	if node.Kind == yaml.MappingNode {
+		for i := 0; i < len(node.Content); i += 2 {
+			if key := node.Content[i]; key.Kind == yaml.ScalarNode && key.Tag == "!!str" {
+				key.Style &^= quotedStyles
+			}
+		}
+	}
+	for _, c := range node.Content {
+		unquoteKeys(c)
 	}
+}
 
-	// Try array (comma-separated in brackets)
-	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
-		inner := value[1 : len(value)-1]
-		if inner == "" {
-			return []string{}
+func hasComments(node *yaml.Node) bool {
+	if node.HeadComment != "" || node.LineComment != "" || node.FootComment != "" {
+		return true
+	}
+	for _, c := range node.Content {
+		if hasComments(c) {
+			return true
 		}
-		parts := strings.Split(inner, ",")
-		result := make([]string, 0, len(parts))
-		for _, p := range parts {
-			result = append(result, strings.TrimSpace(p))
+	}
+	return false
+}
+
+// listItems returns the items of a list value, or the value itself.
+func listItems(node *yaml.Node) []*yaml.Node {
+	if node.Kind == yaml.SequenceNode {
+		return node.Content
+	}
+	return []*yaml.Node{node}
+}
+
+func containsScalar(nodes []*yaml.Node, node *yaml.Node) bool {
+	for _, n := range nodes {
+		if sameScalar(n, node) {
+			return true
 		}
-		return result
 	}
+	return false
+}
 
-	// Default to string
-	return value
+// blockItemLines returns lines adding scalar items to a block list whose
+// first item is on first, or false if an item is not a scalar.
+func blockItemLines(first string, items []*yaml.Node) ([]string, bool) {
+	indent := first[:len(first)-len(strings.TrimLeft(first, " \t"))]
+	lines := make([]string, len(items))
+	for i, item := range items {
+		if item.Kind != yaml.ScalarNode {
+			return nil, false
+		}
+		encoded, err := encodeScalar(item)
+		if err != nil || strings.Contains(encoded, "\n") {
+			return nil, false
+		}
+		lines[i] = indent + "- " + encoded
+	}
+	return lines, true
 }
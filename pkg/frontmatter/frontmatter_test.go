@@ -170,3 +170,320 @@ func TestDeleteKey(t *testing.T) {
 		assert.Contains(t, result, "title: Test")
 	})
 }
+
+func TestRenameKey(t *testing.T) {
+	t.Run("Rename existing key", func(t *testing.T) {
+		content := "---\nstate: done\ntitle: Test\n---\nBody"
+		result, err := frontmatter.RenameKey(content, "state", "status")
+		assert.NoError(t, err)
+		assert.Contains(t, result, "status: done")
+		assert.NotContains(t, result, "state")
+		assert.Contains(t, result, "title: Test")
+		assert.Contains(t, result, "Body")
+	})
+
+	t.Run("Missing key returns error", func(t *testing.T) {
+		content := "---\ntitle: Test\n---\nBody"
+		_, err := frontmatter.RenameKey(content, "state", "status")
+		assert.EqualError(t, err, frontmatter.KeyNotFoundError)
+	})
+
+	t.Run("Existing new key returns error", func(t *testing.T) {
+		content := "---\nstate: done\nstatus: open\n---\nBody"
+		_, err := frontmatter.RenameKey(content, "state", "status")
+		assert.EqualError(t, err, frontmatter.KeyExistsError)
+	})
+
+	t.Run("No frontmatter returns error", func(t *testing.T) {
+		_, err := frontmatter.RenameKey("Just body content", "state", "status")
+		assert.EqualError(t, err, frontmatter.NoFrontmatterError)
+	})
+}
+
+func TestAppendValue(t *testing.T) {
+	t.Run("Append to existing list", func(t *testing.T) {
+		content := "---\ntags:\n  - a\n---\nBody"
+		result, err := frontmatter.AppendValue(content, "tags", "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\ntags:\n  - a\n  - b\n---\nBody", result)
+	})
+
+	t.Run("Value already in list leaves content unchanged", func(t *testing.T) {
+		content := "---\ntags:\n  - a\n---\nBody"
+		result, err := frontmatter.AppendValue(content, "tags", "a")
+		assert.NoError(t, err)
+		assert.Equal(t, content, result)
+	})
+
+	t.Run("Single value becomes a list", func(t *testing.T) {
+		content := "---\nalias: one\n---\nBody"
+		result, err := frontmatter.AppendValue(content, "alias", "two, three")
+		assert.NoError(t, err)
+		assert.Contains(t, result, "alias:\n  - one\n  - two, three\n")
+	})
+
+	t.Run("Creates frontmatter when none exists", func(t *testing.T) {
+		result, err := frontmatter.AppendValue("Just body content", "tags", "a")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\ntags:\n  - a\n---\nJust body content", result)
+	})
+}
+
+func TestEditsPreserveFormatting(t *testing.T) {
+	content := "---\n" +
+		"# Note metadata\n" +
+		"title: \"Old title\" # shown in lists\n" +
+		"state: draft\n" +
+		"tags: [one, two]\n" +
+		"aliases:\n" +
+		"- first\n" +
+		"notes: |\n" +
+		"  line one\n" +
+		"  # not a comment\n" +
+		"\n" +
+		"# Dates\n" +
+		"created: 2024-01-01\n" +
+		"---\n" +
+		"Body"
+
+	tests := []struct {
+		name     string
+		edit     func(string) (string, error)
+		expected string
+	}{
+		{
+			"Set keeps quoting and comments",
+			func(c string) (string, error) { return frontmatter.SetKey(c, "title", "New title") },
+			strings.Replace(content, `title: "Old title" # shown in lists`, `title: "New title" # shown in lists`, 1),
+		},
+		{
+			"Set keeps flow lists",
+			func(c string) (string, error) { return frontmatter.SetKey(c, "tags", "[three]") },
+			strings.Replace(content, "tags: [one, two]", "tags: [three]", 1),
+		},
+		{
+			"Set adds new keys last",
+			func(c string) (string, error) { return frontmatter.SetKey(c, "status", "done") },
+			strings.Replace(content, "created: 2024-01-01\n", "created: 2024-01-01\nstatus: done\n", 1),
+		},
+		{
+			"Set replaces a block scalar",
+			func(c string) (string, error) { return frontmatter.SetKey(c, "notes", "short") },
+			strings.Replace(content, "notes: |\n  line one\n  # not a comment\n", "notes: short\n", 1),
+		},
+		{
+			"Delete removes the key and its comment",
+			func(c string) (string, error) { return frontmatter.DeleteKey(c, "created") },
+			strings.Replace(content, "# Dates\ncreated: 2024-01-01\n", "", 1),
+		},
+		{
+			"Rename rewrites only the key",
+			func(c string) (string, error) { return frontmatter.RenameKey(c, "state", "status") },
+			strings.Replace(content, "state: draft", "status: draft", 1),
+		},
+		{
+			"Append to a flow list",
+			func(c string) (string, error) { return frontmatter.AppendValue(c, "tags", "three") },
+			strings.Replace(content, "tags: [one, two]", "tags: [one, two, three]", 1),
+		},
+		{
+			"Append to a block list keeps its indentation",
+			func(c string) (string, error) { return frontmatter.AppendValue(c, "aliases", "second") },
+			strings.Replace(content, "- first\n", "- first\n- second\n", 1),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := test.edit(content)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+
+	t.Run("Windows line endings are kept", func(t *testing.T) {
+		result, err := frontmatter.SetKey("---\r\nb: 1\r\na: 2\r\n---\r\nBody", "b", "3")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\r\nb: 3\r\na: 2\r\n---\r\nBody", result)
+	})
+
+	t.Run("Rename keeps quoted keys quoted", func(t *testing.T) {
+		result, err := frontmatter.RenameKey("---\n'old key': 1\n---\n", "old key", "new key")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\n'new key': 1\n---\n", result)
+	})
+}
+
+func TestTypedValues(t *testing.T) {
+	tests := map[string]string{
+		"42":                       "value: 42\n",
+		"-1.5":                     "value: -1.5\n",
+		"2024-05-01":               "value: 2024-05-01\n",
+		"null":                     "value: null\n",
+		"true":                     "value: true\n",
+		`"42"`:                     "value: \"42\"\n",
+		"'quoted'":                 "value: 'quoted'\n",
+		`{"owner": "Ann", "n": 2}`: "value:\n  owner: \"Ann\"\n  n: 2\n",
+		"[1, two]":                 "value:\n  - 1\n  - two\n",
+		`{"a: b": {"42": [1]}}`:    "value:\n  'a: b': {\"42\": [1]}\n",
+		"Note: important":          "value: 'Note: important'\n",
+		"a # not a comment":        "value: 'a # not a comment'\n",
+		"plain text":               "value: plain text\n",
+	}
+	for value, expected := range tests {
+		result, err := frontmatter.SetKey("---\n---\n", "value", value)
+		assert.NoError(t, err, value)
+		assert.Equal(t, "---\n"+expected+"---\n", result, value)
+	}
+
+	t.Run("Typed values parse back to their types", func(t *testing.T) {
+		result, err := frontmatter.SetKey("---\n---\n", "count", "3")
+		assert.NoError(t, err)
+		fm, _, err := frontmatter.Parse(result)
+		assert.NoError(t, err)
+		assert.Equal(t, 3, fm["count"])
+	})
+}
+
+func TestKeyPaths(t *testing.T) {
+	content := "---\ntitle: Test\nproject:\n  owner: Ann # lead\n  due: 2024-06-01\naliases:\n  - one\n  - two\n---\nBody"
+
+	t.Run("Set a nested key", func(t *testing.T) {
+		result, err := frontmatter.SetKey(content, "project.owner", "Bob")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "owner: Ann", "owner: Bob", 1), result)
+	})
+
+	t.Run("Set creates missing maps", func(t *testing.T) {
+		result, err := frontmatter.SetKey(content, "review.by.name", "Cy")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "  - two\n", "  - two\nreview:\n  by:\n    name: Cy\n", 1), result)
+	})
+
+	t.Run("Set a list item", func(t *testing.T) {
+		result, err := frontmatter.SetKey(content, "aliases[1]", "second")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "- two", "- second", 1), result)
+	})
+
+	t.Run("Set creates a missing list for index 0", func(t *testing.T) {
+		result, err := frontmatter.SetKey(content, "links[0].url", "a.md")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "  - two\n", "  - two\nlinks:\n  - url: a.md\n", 1), result)
+
+		_, err = frontmatter.SetKey(content, "links[1]", "x")
+		assert.EqualError(t, err, frontmatter.IndexOutOfRangeError)
+	})
+
+	t.Run("Delete a nested key", func(t *testing.T) {
+		result, err := frontmatter.DeleteKey(content, "project.due")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "  due: 2024-06-01\n", "", 1), result)
+	})
+
+	t.Run("Delete a list item", func(t *testing.T) {
+		result, err := frontmatter.DeleteKey(content, "aliases[0]")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "  - one\n", "", 1), result)
+	})
+
+	t.Run("Rename a nested key", func(t *testing.T) {
+		result, err := frontmatter.RenameKey(content, "project.owner", "lead")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "owner: Ann", "lead: Ann", 1), result)
+	})
+
+	t.Run("Has key", func(t *testing.T) {
+		for key, expected := range map[string]bool{"project.owner": true, "aliases[1]": true, "aliases[2]": false, "project.missing": false, "title.x": false} {
+			has, err := frontmatter.HasKey(content, key)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, has, key)
+		}
+	})
+
+	t.Run("Existing keys with dots are matched literally", func(t *testing.T) {
+		dotted := "---\nfile.name: a\nfile.tags:\n  - x\n---\n"
+
+		result, err := frontmatter.SetKey(dotted, "file.name", "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\nfile.name: b\nfile.tags:\n  - x\n---\n", result)
+
+		result, err = frontmatter.SetKey(dotted, "file.tags[0]", "y")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\nfile.name: a\nfile.tags:\n  - y\n---\n", result)
+
+		result, err = frontmatter.DeleteKey(dotted, "file.name")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\nfile.tags:\n  - x\n---\n", result)
+
+		has, err := frontmatter.HasKey(dotted, "file.name")
+		assert.NoError(t, err)
+		assert.True(t, has)
+
+		// Without such a key, dots still separate nested keys.
+		result, err = frontmatter.SetKey(dotted, "file.size", "3")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\nfile.name: a\nfile.tags:\n  - x\nfile:\n  size: 3\n---\n", result)
+	})
+
+	t.Run("Invalid paths and mismatched values return errors", func(t *testing.T) {
+		for _, key := range []string{"", "a..b", ".a", "a[", "a[x]", "a[-1]", "a]b"} {
+			_, err := frontmatter.SetKey(content, key, "x")
+			assert.EqualError(t, err, frontmatter.InvalidKeyPathError, key)
+		}
+		_, err := frontmatter.SetKey(content, "title.sub", "x")
+		assert.EqualError(t, err, frontmatter.KeyPathTypeError)
+		_, err = frontmatter.SetKey(content, "aliases[5]", "x")
+		assert.EqualError(t, err, frontmatter.IndexOutOfRangeError)
+		_, err = frontmatter.AppendValue(content, "project", "x")
+		assert.EqualError(t, err, frontmatter.NotAListError)
+	})
+}
+
+func TestRemoveValue(t *testing.T) {
+	content := "---\ntags:\n  - a\n  - b # keep\n  - c\nflow: [1, 2, 3]\nsingle: x\n---\nBody"
+
+	t.Run("Remove from a block list", func(t *testing.T) {
+		result, err := frontmatter.RemoveValue(content, "tags", "[a, c]")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "  - a\n  - b # keep\n  - c\n", "  - b # keep\n", 1), result)
+	})
+
+	t.Run("Comments between items are kept", func(t *testing.T) {
+		commented := "---\ntags:\n  - a\n  # about b\n  - b\n\n  # about c\n  - c\n  # trailing\nnext: 1\n---\n"
+		result, err := frontmatter.RemoveValue(commented, "tags", "[a, c]")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\ntags:\n  # about b\n  - b\n\n  # about c\n  # trailing\nnext: 1\n---\n", result)
+	})
+
+	t.Run("Remove from a flow list matches typed values", func(t *testing.T) {
+		result, err := frontmatter.RemoveValue(content, "flow", "2")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "[1, 2, 3]", "[1, 3]", 1), result)
+	})
+
+	t.Run("Removing the last item leaves an empty list", func(t *testing.T) {
+		result, err := frontmatter.RemoveValue(content, "single", "x")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "single: x", "single: []", 1), result)
+	})
+
+	t.Run("Missing values leave content unchanged", func(t *testing.T) {
+		for key, value := range map[string]string{"tags": "z", "missing": "a", "single": "y"} {
+			result, err := frontmatter.RemoveValue(content, key, value)
+			assert.NoError(t, err)
+			assert.Equal(t, content, result)
+		}
+	})
+
+	t.Run("Append several items to a block list", func(t *testing.T) {
+		result, err := frontmatter.AppendValue(content, "tags", "[c, d, 5]")
+		assert.NoError(t, err)
+		assert.Equal(t, strings.Replace(content, "  - c\n", "  - c\n  - d\n  - 5\n", 1), result)
+	})
+
+	t.Run("Append to a nested list", func(t *testing.T) {
+		result, err := frontmatter.AppendValue("---\nproject:\n  members: [Ann]\n---\n", "project.members", "Bob")
+		assert.NoError(t, err)
+		assert.Equal(t, "---\nproject:\n  members: [Ann, Bob]\n---\n", result)
+	})
+}
@@ -0,0 +1,225 @@
+package frontmatter
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// pathPart is one step of a key path: a map key, or a list index when index
+// is not negative.
+type pathPart struct {
+	key   string
+	index int
+}
+
+// parsePath splits a key path such as "project.owner" or "aliases[0]" into
+// its parts. Dots separate map keys and [n] selects the n-th list item.
+func parsePath(path string) ([]pathPart, error) {
+	var parts []pathPart
+	for _, segment := range strings.Split(path, ".") {
+		segmentParts, err := parseSegment(segment, false)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, segmentParts...)
+	}
+	return parts, nil
+}
+
+// resolvePath is parsePath for the frontmatter whose top-level mapping is
+// mapping, which may be nil. A top-level key that itself contains dots, such
+// as "file.name", is matched literally rather than split, so paths can start
+// with it, e.g. "file.name" or "file.name[0]".
+func resolvePath(mapping *yaml.Node, path string) ([]pathPart, error) {
+	literal := ""
+	if mapping != nil {
+		for i := 0; i < len(mapping.Content); i += 2 {
+			key := mapping.Content[i].Value
+			if !strings.Contains(key, ".") || len(key) <= len(literal) || !strings.HasPrefix(path, key) {
+				continue
+			}
+			if len(path) == len(key) || path[len(key)] == '.' || path[len(key)] == '[' {
+				literal = key
+			}
+		}
+	}
+	if literal == "" {
+		return parsePath(path)
+	}
+
+	parts := []pathPart{{key: literal, index: -1}}
+	segments := strings.Split(path[len(literal):], ".")
+	for i, segment := range segments {
+		if i == 0 && segment == "" && len(segments) > 1 {
+			continue // the dot right after the literal key
+		}
+		segmentParts, err := parseSegment(segment, i == 0)
+		if err != nil {
+			return nil, err
+		}
+		parts = append(parts, segmentParts...)
+	}
+	return parts, nil
+}
+
+// parseSegment parses one dot-separated segment of a key path: a key
+// followed by any number of [n] indexes. The key may only be left out when
+// indexOnly is set.
+func parseSegment(segment string, indexOnly bool) ([]pathPart, error) {
+	name := segment
+	if i := strings.IndexByte(segment, '['); i >= 0 {
+		name = segment[:i]
+	}
+	if indexOnly && name != "" || !indexOnly && name == "" || strings.ContainsAny(name, "]") {
+		return nil, errors.New(InvalidKeyPathError)
+	}
+
+	var parts []pathPart
+	if name != "" {
+		parts = append(parts, pathPart{key: name, index: -1})
+	}
+	for rest := segment[len(name):]; rest != ""; {
+		end := strings.IndexByte(rest, ']')
+		if rest[0] != '[' || end < 0 {
+			return nil, errors.New(InvalidKeyPathError)
+		}
+		n, err := strconv.Atoi(rest[1:end])
+		if err != nil || n < 0 {
+			return nil, errors.New(InvalidKeyPathError)
+		}
+		parts = append(parts, pathPart{index: n})
+		rest = rest[end+1:]
+	}
+	return parts, nil
+}
+
+// lookup returns the node at parts below node, or nil if there is none.
+func lookup(node *yaml.Node, parts []pathPart) *yaml.Node {
+	for _, part := range parts {
+		if node == nil {
+			return nil
+		}
+		node = child(node, part)
+	}
+	return node
+}
+
+func child(node *yaml.Node, part pathPart) *yaml.Node {
+	if part.index >= 0 {
+		if node.Kind != yaml.SequenceNode || part.index >= len(node.Content) {
+			return nil
+		}
+		return node.Content[part.index]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == part.key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// setIn returns node with the value at parts replaced by value. Missing map
+// keys are added, and a missing or null node becomes a map, or a list when
+// the index is 0; other list indexes must already exist.
+func setIn(node *yaml.Node, parts []pathPart, value *yaml.Node) (*yaml.Node, error) {
+	if len(parts) == 0 {
+		return value, nil
+	}
+	part := parts[0]
+
+	if part.index >= 0 {
+		if node == nil || isNull(node) {
+			if part.index > 0 {
+				return nil, errors.New(IndexOutOfRangeError)
+			}
+			item, err := setIn(nil, parts[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			return &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Content: []*yaml.Node{item}}, nil
+		}
+		if node.Kind != yaml.SequenceNode {
+			return nil, errors.New(KeyPathTypeError)
+		}
+		if part.index >= len(node.Content) {
+			return nil, errors.New(IndexOutOfRangeError)
+		}
+		updated, err := setIn(node.Content[part.index], parts[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		node.Content[part.index] = updated
+		return node, nil
+	}
+
+	if node == nil || isNull(node) {
+		node = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil, errors.New(KeyPathTypeError)
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == part.key {
+			updated, err := setIn(node.Content[i+1], parts[1:], value)
+			if err != nil {
+				return nil, err
+			}
+			node.Content[i+1] = updated
+			return node, nil
+		}
+	}
+	updated, err := setIn(nil, parts[1:], value)
+	if err != nil {
+		return nil, err
+	}
+	node.Content = append(node.Content, stringNode(part.key), updated)
+	return node, nil
+}
+
+// deleteIn removes the value at parts below node and reports whether it was
+// there.
+func deleteIn(node *yaml.Node, parts []pathPart) bool {
+	parent := lookup(node, parts[:len(parts)-1])
+	if parent == nil {
+		return false
+	}
+	last := parts[len(parts)-1]
+	if last.index >= 0 {
+		if parent.Kind != yaml.SequenceNode || last.index >= len(parent.Content) {
+			return false
+		}
+		parent.Content = append(parent.Content[:last.index], parent.Content[last.index+1:]...)
+		return true
+	}
+	if parent.Kind != yaml.MappingNode {
+		return false
+	}
+	for i := 0; i < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == last.key {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func isNull(node *yaml.Node) bool {
+	return node.Kind == yaml.ScalarNode && node.Tag == "!!null"
+}
+
+func stringNode(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+// sameScalar reports whether a and b are scalars with the same value, so
+// that 5, "5" and '5' all match.
+func sameScalar(a, b *yaml.Node) bool {
+	return a.Kind == yaml.ScalarNode && b.Kind == yaml.ScalarNode && a.Value == b.Value
+}
@@ -0,0 +1,330 @@
+package frontmatter
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Type names accepted by Property.Type. They are JSON Schema's types plus
+// "date" and "datetime"; "list" and "map" may be used for "array" and
+// "object".
+const (
+	TypeString   = "string"
+	TypeNumber   = "number"
+	TypeInteger  = "integer"
+	TypeBoolean  = "boolean"
+	TypeNull     = "null"
+	TypeArray    = "array"
+	TypeObject   = "object"
+	TypeDate     = "date"
+	TypeDateTime = "datetime"
+)
+
+var typeAliases = map[string]string{
+	"list": TypeArray,
+	"map":  TypeObject,
+	"bool": TypeBoolean,
+	"int":  TypeInteger,
+}
+
+const (
+	dateLayout     = "2006-01-02"
+	dateTimeLayout = time.RFC3339
+)
+
+// Schema lists the keys a note's frontmatter must have and the values its
+// keys may take. Its fields follow JSON Schema, so a JSON Schema document
+// using these keywords can be used as is.
+type Schema struct {
+	Required   []string             `yaml:"required" json:"required,omitempty"`
+	Properties map[string]*Property `yaml:"properties" json:"properties,omitempty"`
+}
+
+// Property describes the values allowed for a key. Unset fields allow
+// anything.
+type Property struct {
+	Type    Types         `yaml:"type" json:"type,omitempty"`
+	Enum    []interface{} `yaml:"enum" json:"enum,omitempty"`
+	Pattern string        `yaml:"pattern" json:"pattern,omitempty"`
+	Minimum *float64      `yaml:"minimum" json:"minimum,omitempty"`
+	Maximum *float64      `yaml:"maximum" json:"maximum,omitempty"`
+	// Format "date" or "date-time" requires a string to hold a date.
+	Format string `yaml:"format" json:"format,omitempty"`
+	// Items applies to every item of a list.
+	Items *Property `yaml:"items" json:"items,omitempty"`
+
+	pattern *regexp.Regexp
+}
+
+// Types is one or more type names. In YAML it may be written as a single
+// name or a list.
+type Types []string
+
+func (t *Types) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*t = Types{node.Value}
+		return nil
+	}
+	var names []string
+	if err := node.Decode(&names); err != nil {
+		return err
+	}
+	*t = names
+	return nil
+}
+
+// Violation is a frontmatter key that does not satisfy a schema.
+type Violation struct {
+	Key     string `json:"key"`
+	Message string `json:"message"`
+}
+
+// Compile checks the schema for unknown types and invalid patterns and
+// prepares it for Validate.
+func (s *Schema) Compile() error {
+	for _, key := range sortedPropertyKeys(s.Properties) {
+		if err := s.Properties[key].compile(); err != nil {
+			return fmt.Errorf("property %q: %s", key, err.Error())
+		}
+	}
+	return nil
+}
+
+func (p *Property) compile() error {
+	if p == nil {
+		return nil
+	}
+	for i, name := range p.Type {
+		if alias, ok := typeAliases[name]; ok {
+			p.Type[i] = alias
+			continue
+		}
+		switch name {
+		case TypeString, TypeNumber, TypeInteger, TypeBoolean, TypeNull, TypeArray, TypeObject, TypeDate, TypeDateTime:
+		default:
+			return fmt.Errorf("unknown type %q", name)
+		}
+	}
+	switch p.Format {
+	case "", "date", "date-time":
+	default:
+		return fmt.Errorf("unknown format %q", p.Format)
+	}
+	if p.Pattern != "" {
+		pattern, err := regexp.Compile(p.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid pattern %q", p.Pattern)
+		}
+		p.pattern = pattern
+	}
+	return p.Items.compile()
+}
+
+// Validate returns the ways fm breaks the schema, ordered by key. The schema
+// must have been compiled.
+func (s *Schema) Validate(fm map[string]interface{}) []Violation {
+	var violations []Violation
+	for _, key := range s.Required {
+		if _, ok := fm[key]; !ok {
+			violations = append(violations, Violation{Key: key, Message: "missing required key"})
+		}
+	}
+	for _, key := range sortedPropertyKeys(s.Properties) {
+		if value, ok := fm[key]; ok && s.Properties[key] != nil {
+			violations = append(violations, s.Properties[key].validate(key, value)...)
+		}
+	}
+	sort.SliceStable(violations, func(i, j int) bool { return violations[i].Key < violations[j].Key })
+	return violations
+}
+
+func (p *Property) validate(key string, value interface{}) []Violation {
+	if len(p.Type) > 0 && !p.hasType(value) {
+		return []Violation{{Key: key, Message: "must be " + typeDescription(p.Type)}}
+	}
+
+	var violations []Violation
+	fail := func(format string, args ...interface{}) {
+		violations = append(violations, Violation{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+	if len(p.Enum) > 0 && !inEnum(p.Enum, value) {
+		options := make([]string, len(p.Enum))
+		for i, option := range p.Enum {
+			options[i] = scalarText(option)
+		}
+		fail("must be one of %s", strings.Join(options, ", "))
+	}
+	if s, ok := value.(string); ok {
+		if p.pattern != nil && !p.pattern.MatchString(s) {
+			fail("must match %s", p.Pattern)
+		}
+		if p.Format == "date" && !isDate(value) {
+			fail("must be a date (YYYY-MM-DD)")
+		}
+		if p.Format == "date-time" && !isDateTime(value) {
+			fail("must be a date and time (RFC 3339)")
+		}
+	}
+	if n, ok := number(value); ok {
+		if p.Minimum != nil && n < *p.Minimum {
+			fail("must be at least %s", scalarText(*p.Minimum))
+		}
+		if p.Maximum != nil && n > *p.Maximum {
+			fail("must be at most %s", scalarText(*p.Maximum))
+		}
+	}
+	if items, ok := value.([]interface{}); ok && p.Items != nil {
+		for i, item := range items {
+			violations = append(violations, p.Items.validate(fmt.Sprintf("%s[%d]", key, i), item)...)
+		}
+	}
+	return violations
+}
+
+func (p *Property) hasType(value interface{}) bool {
+	for _, name := range p.Type {
+		if isType(name, value, p.Format) {
+			return true
+		}
+	}
+	return false
+}
+
+func isType(name string, value interface{}, format string) bool {
+	switch name {
+	case TypeString:
+		if _, ok := value.(string); ok {
+			return true
+		}
+		// YAML reads unquoted dates as timestamps rather than strings.
+		_, ok := value.(time.Time)
+		return ok && format != ""
+	case TypeNumber:
+		_, ok := number(value)
+		return ok
+	case TypeInteger:
+		n, ok := number(value)
+		return ok && n == math.Trunc(n)
+	case TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	case TypeNull:
+		return value == nil
+	case TypeArray:
+		_, ok := value.([]interface{})
+		return ok
+	case TypeObject:
+		_, ok := value.(map[string]interface{})
+		return ok
+	case TypeDate:
+		return isDate(value)
+	case TypeDateTime:
+		return isDateTime(value)
+	}
+	return false
+}
+
+func typeDescription(types Types) string {
+	names := make([]string, len(types))
+	for i, name := range types {
+		switch name {
+		case TypeArray:
+			names[i] = "a list"
+		case TypeObject:
+			names[i] = "a map"
+		case TypeInteger:
+			names[i] = "an integer"
+		case TypeNull:
+			names[i] = "null"
+		case TypeDate:
+			names[i] = "a date (YYYY-MM-DD)"
+		case TypeDateTime:
+			names[i] = "a date and time"
+		default:
+			names[i] = "a " + name
+		}
+	}
+	return strings.Join(names, " or ")
+}
+
+func isDate(value interface{}) bool {
+	switch v := value.(type) {
+	case time.Time:
+		return true
+	case string:
+		_, err := time.Parse(dateLayout, v)
+		return err == nil
+	}
+	return false
+}
+
+func isDateTime(value interface{}) bool {
+	switch v := value.(type) {
+	case time.Time:
+		return true
+	case string:
+		_, err := time.Parse(dateTimeLayout, v)
+		return err == nil
+	}
+	return false
+}
+
+func number(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, option := range enum {
+		if a, ok := number(option); ok {
+			if b, ok := number(value); ok && a == b {
+				return true
+			}
+			continue
+		}
+		if scalarText(option) == scalarText(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// scalarText renders a decoded YAML value for comparison and messages.
+func scalarText(value interface{}) string {
+	switch v := value.(type) {
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) {
+			return v.Format(dateLayout)
+		}
+		return v.Format(dateTimeLayout)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return fmt.Sprintf("%d", int64(v))
+		}
+	}
+	return fmt.Sprint(value)
+}
+
+func sortedPropertyKeys(properties map[string]*Property) []string {
+	keys := make([]string, 0, len(properties))
+	for key := range properties {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package frontmatter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func compileSchema(t *testing.T, source string) *frontmatter.Schema {
+	t.Helper()
+	var schema frontmatter.Schema
+	if err := yaml.Unmarshal([]byte(source), &schema); err != nil {
+		t.Fatal(err)
+	}
+	if err := schema.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	return &schema
+}
+
+func TestSchemaValidate(t *testing.T) {
+	schema := compileSchema(t, `
+required: [status, due]
+properties:
+  status: {type: string, enum: [active, done]}
+  due: {type: date}
+  started: {type: string, format: date}
+  priority: {type: integer, minimum: 1, maximum: 5}
+  tags: {type: list, items: {type: string, pattern: "^[a-z0-9/]+$"}}
+  owner: {type: [string, "null"]}
+`)
+
+	t.Run("Valid frontmatter", func(t *testing.T) {
+		fm := map[string]interface{}{
+			"status":   "done",
+			"due":      time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			"started":  "2024-05-01",
+			"priority": 3,
+			"tags":     []interface{}{"work/q2"},
+			"owner":    nil,
+			"extra":    "anything",
+		}
+		assert.Empty(t, schema.Validate(fm))
+	})
+
+	t.Run("Reports every violation by key", func(t *testing.T) {
+		fm := map[string]interface{}{
+			"status":   "open",
+			"started":  "May 1st",
+			"priority": 7.5,
+			"tags":     []interface{}{"ok", "Bad Tag", 3},
+			"owner":    []interface{}{},
+		}
+		assert.Equal(t, []frontmatter.Violation{
+			{Key: "due", Message: "missing required key"},
+			{Key: "owner", Message: "must be a string or null"},
+			{Key: "priority", Message: "must be an integer"},
+			{Key: "started", Message: "must be a date (YYYY-MM-DD)"},
+			{Key: "status", Message: "must be one of active, done"},
+			{Key: "tags[1]", Message: "must match ^[a-z0-9/]+$"},
+			{Key: "tags[2]", Message: "must be a string"},
+		}, schema.Validate(fm))
+	})
+
+	t.Run("Range checks", func(t *testing.T) {
+		assert.Equal(t, []frontmatter.Violation{{Key: "priority", Message: "must be at most 5"}},
+			schema.Validate(map[string]interface{}{"status": "done", "due": "2024-01-01", "priority": 9}))
+	})
+}
+
+func TestSchemaCompile(t *testing.T) {
+	for source, message := range map[string]string{
+		`properties: {a: {type: strin}}`:          `property "a": unknown type "strin"`,
+		`properties: {a: {pattern: "("}}`:         `property "a": invalid pattern "("`,
+		`properties: {a: {format: email}}`:        `property "a": unknown format "email"`,
+		`properties: {a: {items: {type: thing}}}`: `property "a": unknown type "thing"`,
+	} {
+		var schema frontmatter.Schema
+		assert.NoError(t, yaml.Unmarshal([]byte(source), &schema))
+		assert.EqualError(t, schema.Compile(), message, source)
+	}
+}
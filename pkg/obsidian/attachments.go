@@ -0,0 +1,206 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// IsAttachment reports whether path is an existing file other than a note,
+// such as an image or a PDF.
+func IsAttachment(path string) bool {
+	if filepath.Ext(path) == ".md" {
+		return false
+	}
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// noteFilePath returns path unchanged when it is an attachment and with the
+// .md extension added otherwise.
+func noteFilePath(path string) string {
+	if IsAttachment(path) {
+		return path
+	}
+	return AddMdSuffix(path)
+}
+
+// attachmentFolder returns the vault-relative folder new attachments of the
+// note at the vault-relative path note go to under Obsidian's
+// attachmentFolderPath setting: "/" or unset for the vault root, "./" for
+// the note's folder, "./name" for a folder under it, or a fixed folder.
+// The vault root is "".
+func attachmentFolder(setting, note string) string {
+	setting = strings.TrimSpace(normalizePathSeparators(setting))
+	folder := ""
+	switch {
+	case setting == "" || setting == "/":
+		return ""
+	case setting == "." || strings.HasPrefix(setting, "./"):
+		folder = path.Join(path.Dir(note), setting)
+	default:
+		folder = path.Clean(strings.Trim(setting, "/"))
+	}
+	if folder == "." {
+		return ""
+	}
+	return folder
+}
+
+// attachmentLinks returns the vault-relative paths of the attachments the
+// note at source links to or embeds.
+func attachmentLinks(content, source string, files *vaultFiles) map[string]bool {
+	attachments := make(map[string]bool)
+	for _, link := range ParseLinks(content) {
+		if dest, ok := files.resolve(link, source); ok && !strings.HasSuffix(dest, ".md") {
+			attachments[dest] = true
+		}
+	}
+	return attachments
+}
+
+// dropSharedAttachments removes from attachments those that a note other than
+// note links to. paths are the vault's files.
+func dropSharedAttachments(vaultPath string, paths []string, files *vaultFiles, note string, attachments map[string]bool) error {
+	if len(attachments) == 0 {
+		return nil
+	}
+	candidates := make(map[string]string, len(attachments))
+	for attachment := range attachments {
+		candidates[attachment] = attachment
+	}
+	linking := linkingFilter(vaultPath, candidates)
+
+	for _, other := range paths {
+		if other == note || !strings.HasSuffix(other, ".md") || (linking != nil && !linking(other)) {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(other)))
+		if err != nil {
+			return NewError(ErrCodeVaultRead, VaultReadError)
+		}
+		for attachment := range attachmentLinks(string(content), other, files) {
+			delete(attachments, attachment)
+		}
+		if len(attachments) == 0 {
+			return nil
+		}
+	}
+	return nil
+}
+
+// ownAttachments returns the attachments that only the note at the
+// vault-relative path note links to.
+func ownAttachments(vaultPath, note string) (map[string]bool, error) {
+	paths, err := listVaultFiles(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	files := newVaultFiles(paths)
+
+	content, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(note)))
+	if err != nil {
+		return nil, NewError(ErrCodeNoteNotFound, NoteDoesNotExistError)
+	}
+	attachments := attachmentLinks(string(content), note, files)
+	if err := dropSharedAttachments(vaultPath, paths, files, note, attachments); err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// UnusedAttachments returns the sorted vault-relative paths of the
+// attachments the note noteName links to or embeds that no other note
+// links to, which would be left unused if the note were deleted.
+func (m *Note) UnusedAttachments(vaultPath string, noteName string) ([]string, error) {
+	note := path.Clean(AddMdSuffix(normalizePathSeparators(noteName)))
+	attachments, err := ownAttachments(vaultPath, note)
+	if err != nil {
+		return nil, err
+	}
+
+	unused := []string{}
+	for attachment := range attachments {
+		unused = append(unused, attachment)
+	}
+	sort.Strings(unused)
+	return unused, nil
+}
+
+// PlanAttachmentMoves returns the attachments MoveAttachments would move for
+// a note moving from oldNoteName to newNoteName, without moving anything,
+// and fails if one of their destinations is taken. It reads the note at
+// oldNoteName, so it is meant to be called before the note moves.
+func (m *Note) PlanAttachmentMoves(vaultPath string, oldNoteName string, newNoteName string) ([]FileMove, error) {
+	oldNote := path.Clean(AddMdSuffix(normalizePathSeparators(oldNoteName)))
+	newNote := path.Clean(AddMdSuffix(normalizePathSeparators(newNoteName)))
+	return attachmentMoves(vaultPath, oldNote, oldNote, newNote)
+}
+
+// MoveAttachments moves the attachments of a note that moved from oldNoteName
+// to newNoteName, both relative to the vault, to the note's new attachment
+// folder under the vault's attachmentFolderPath setting, and updates the
+// links to them. Only attachments stored directly in the note's old
+// attachment folder and not linked to from other notes move. Nothing moves
+// when the attachment folder does not depend on the note's location. If a
+// rename fails, the attachments already moved are moved back.
+func (m *Note) MoveAttachments(vaultPath string, oldNoteName string, newNoteName string) ([]FileMove, error) {
+	oldNote := path.Clean(AddMdSuffix(normalizePathSeparators(oldNoteName)))
+	newNote := path.Clean(AddMdSuffix(normalizePathSeparators(newNoteName)))
+	moves, err := attachmentMoves(vaultPath, newNote, oldNote, newNote)
+	if err != nil || len(moves) == 0 {
+		return moves, err
+	}
+
+	if err := renameFiles(vaultPath, moves); err != nil {
+		return nil, err
+	}
+	moved := make(map[string]string, len(moves))
+	for _, move := range moves {
+		moved[move.From] = move.To
+		fmt.Printf("Moved attachment\nfrom %s\nto %s\n", filepath.Join(vaultPath, filepath.FromSlash(move.From)), filepath.Join(vaultPath, filepath.FromSlash(move.To)))
+	}
+
+	if err := updateMovedLinks(vaultPath, moved, linkingFilter(vaultPath, moved)); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+// attachmentMoves returns the moves, sorted by old path, of the attachments
+// of a note moving from oldNote to newNote, checking that each destination
+// is free. The note's links are read from the note at source, its path
+// before or after the move.
+func attachmentMoves(vaultPath, source, oldNote, newNote string) ([]FileMove, error) {
+	setting := ReadObsidianAppConfig(vaultPath).AttachmentFolderPath
+	oldFolder := attachmentFolder(setting, oldNote)
+	newFolder := attachmentFolder(setting, newNote)
+	moves := []FileMove{}
+	if oldFolder == newFolder {
+		return moves, nil
+	}
+
+	attachments, err := ownAttachments(vaultPath, source)
+	if err != nil {
+		return nil, err
+	}
+	for attachment := range attachments {
+		dir := path.Dir(attachment)
+		if dir == "." {
+			dir = ""
+		}
+		if dir != oldFolder {
+			continue
+		}
+		dest := path.Join(newFolder, path.Base(attachment))
+		if _, err := os.Stat(filepath.Join(vaultPath, filepath.FromSlash(dest))); err == nil {
+			return nil, NewError(ErrCodeInvalidArgument, fmt.Sprintf("cannot move attachment %q: %q already exists in vault", attachment, dest))
+		}
+		moves = append(moves, FileMove{From: attachment, To: dest})
+	}
+	sort.Slice(moves, func(i, j int) bool { return moves[i].From < moves[j].From })
+	return moves, nil
+}
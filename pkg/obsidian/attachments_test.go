@@ -0,0 +1,196 @@
+package obsidian_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAttachment(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"note.md":        "",
+		"assets/pic.png": "png",
+	})
+
+	assert.True(t, obsidian.IsAttachment(filepath.Join(vaultPath, "assets", "pic.png")))
+	assert.False(t, obsidian.IsAttachment(filepath.Join(vaultPath, "note.md")))
+	assert.False(t, obsidian.IsAttachment(filepath.Join(vaultPath, "note")))
+	assert.False(t, obsidian.IsAttachment(filepath.Join(vaultPath, "assets")))
+	assert.False(t, obsidian.IsAttachment(filepath.Join(vaultPath, "missing.png")))
+}
+
+func TestNote_MoveAndDeleteAttachment(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"pic.png": "png",
+		"note.md": "![[pic.png]] ![](pic.png)",
+	})
+	note := obsidian.Note{}
+
+	err := note.Move(filepath.Join(vaultPath, "pic.png"), filepath.Join(vaultPath, "chart.png"))
+	assert.NoError(t, err)
+	assert.FileExists(t, filepath.Join(vaultPath, "chart.png"))
+	assert.NoFileExists(t, filepath.Join(vaultPath, "chart.png.md"))
+
+	err = note.UpdateLinks(vaultPath, "pic.png", "chart.png")
+	assert.NoError(t, err)
+	content, _ := os.ReadFile(filepath.Join(vaultPath, "note.md"))
+	assert.Equal(t, "![[chart.png]] ![](chart.png)", string(content))
+
+	err = note.Delete(filepath.Join(vaultPath, "chart.png"))
+	assert.NoError(t, err)
+	assert.NoFileExists(t, filepath.Join(vaultPath, "chart.png"))
+}
+
+func TestNote_UnusedAttachments(t *testing.T) {
+	t.Run("Returns attachments no other note links to", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"notes/plan.md":         "![[diagram.png]] ![chart](../assets/chart.png) [doc](<../assets/spec sheet.pdf>) [[other]]",
+			"notes/other.md":        "![[chart.png]]",
+			"assets/diagram.png":    "png",
+			"assets/chart.png":      "png",
+			"assets/spec sheet.pdf": "pdf",
+		})
+		note := obsidian.Note{}
+
+		unused, err := note.UnusedAttachments(vaultPath, "notes/plan")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"assets/diagram.png", "assets/spec sheet.pdf"}, unused)
+	})
+
+	t.Run("Uses the vault index", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"plan.md":  "![[a.png]] ![[b.png]]",
+			"other.md": "![[b.png]]",
+			"a.png":    "png",
+			"b.png":    "png",
+		})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		note := obsidian.Note{}
+
+		unused, err := note.UnusedAttachments(vaultPath, "plan.md")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.png"}, unused)
+	})
+
+	t.Run("Missing note", func(t *testing.T) {
+		note := obsidian.Note{}
+		_, err := note.UnusedAttachments(t.TempDir(), "missing")
+		assert.Equal(t, obsidian.ErrCodeNoteNotFound, obsidian.ErrorCode(err))
+	})
+}
+
+func TestNote_MoveAttachments(t *testing.T) {
+	tests := []struct {
+		name     string
+		setting  string
+		files    map[string]string
+		expected []obsidian.FileMove
+		links    string
+	}{
+		{
+			name:    "Same folder as the note",
+			setting: "./",
+			files: map[string]string{
+				"inbox/pic.png":    "png",
+				"inbox/shared.png": "png",
+				"inbox/other.md":   "![[shared.png]]",
+			},
+			expected: []obsidian.FileMove{{From: "inbox/pic.png", To: "projects/pic.png"}},
+			links:    "![[pic.png]] ![](pic.png) ![[shared.png]]",
+		},
+		{
+			name:    "Subfolder under the note's folder",
+			setting: "./assets",
+			files: map[string]string{
+				"inbox/assets/pic.png":    "png",
+				"inbox/assets/shared.png": "png",
+				"inbox/other.md":          "![[shared.png]]",
+			},
+			expected: []obsidian.FileMove{{From: "inbox/assets/pic.png", To: "projects/assets/pic.png"}},
+			links:    "![[pic.png]] ![](assets/pic.png) ![[shared.png]]",
+		},
+		{
+			name:    "Fixed folder",
+			setting: "attachments",
+			files: map[string]string{
+				"attachments/pic.png":    "png",
+				"attachments/shared.png": "png",
+				"inbox/other.md":         "![[shared.png]]",
+			},
+			expected: []obsidian.FileMove{},
+			links:    "![[pic.png]] ![](../attachments/pic.png) ![[shared.png]]",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			vaultPath := t.TempDir()
+			writeVaultFiles(t, vaultPath, test.files)
+			writeVaultFiles(t, vaultPath, map[string]string{
+				".obsidian/app.json": `{"attachmentFolderPath": "` + test.setting + `"}`,
+			})
+			var link string
+			for p := range test.files {
+				if filepath.Base(p) == "pic.png" {
+					link, _ = filepath.Rel("projects", p)
+				}
+			}
+			writeVaultFiles(t, vaultPath, map[string]string{
+				"projects/plan.md": "![[pic.png]] ![](" + filepath.ToSlash(link) + ") ![[shared.png]]",
+			})
+			note := obsidian.Note{}
+
+			moves, err := note.MoveAttachments(vaultPath, "inbox/plan", "projects/plan")
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, moves)
+			for _, move := range test.expected {
+				assert.FileExists(t, filepath.Join(vaultPath, filepath.FromSlash(move.To)))
+				assert.NoFileExists(t, filepath.Join(vaultPath, filepath.FromSlash(move.From)))
+			}
+			content, _ := os.ReadFile(filepath.Join(vaultPath, "projects", "plan.md"))
+			assert.Equal(t, test.links, string(content))
+		})
+	}
+
+	t.Run("Plans the moves before the note moves", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			".obsidian/app.json": `{"attachmentFolderPath": "./"}`,
+			"inbox/pic.png":      "png",
+			"inbox/plan.md":      "![[pic.png]]",
+		})
+		note := obsidian.Note{}
+
+		moves, err := note.PlanAttachmentMoves(vaultPath, "inbox/plan", "projects/plan")
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.FileMove{{From: "inbox/pic.png", To: "projects/pic.png"}}, moves)
+		assert.FileExists(t, filepath.Join(vaultPath, "inbox", "pic.png"))
+
+		writeVaultFiles(t, vaultPath, map[string]string{"projects/pic.png": "other"})
+		_, err = note.PlanAttachmentMoves(vaultPath, "inbox/plan", "projects/plan")
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Destination already exists", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			".obsidian/app.json": `{"attachmentFolderPath": "./"}`,
+			"inbox/pic.png":      "old",
+			"projects/pic.png":   "other",
+			"projects/plan.md":   "![](../inbox/pic.png)",
+		})
+		note := obsidian.Note{}
+
+		_, err := note.MoveAttachments(vaultPath, "inbox/plan", "projects/plan")
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+		assert.FileExists(t, filepath.Join(vaultPath, "inbox", "pic.png"))
+	})
+}
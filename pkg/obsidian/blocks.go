@@ -0,0 +1,169 @@
+package obsidian
+
+import (
+	"crypto/rand"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	blockIDMarkerRegex = regexp.MustCompile(`(?:^|[ \t])\^([A-Za-z0-9-]+)[ \t]*$`)
+	validBlockIDRegex  = regexp.MustCompile(`^[A-Za-z0-9-]+$`)
+	blockListItemRegex = regexp.MustCompile(`^\s*(?:>\s*)*(?:[-*+]|\d+[.)])\s`)
+)
+
+// blockIDAlphabet is the set of characters Obsidian uses for the block IDs
+// it generates.
+const blockIDAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// SplitBlockRef splits a link target such as "note#^abc123" into the note
+// and the block ID. Without a "#^" the block ID is empty.
+func SplitBlockRef(target string) (string, string) {
+	if i := strings.Index(target, "#^"); i != -1 {
+		return target[:i], target[i+2:]
+	}
+	return target, ""
+}
+
+// FindBlock returns the block of a note marked with the block ID id, without
+// the "^id" marker. A marker at the end of a line identifies that line's
+// paragraph, list item or heading; a marker on a line of its own identifies
+// the block just above it, such as a table, quote or code block.
+func FindBlock(content, id string) (string, error) {
+	lines := splitMarkdownLines(content)
+	for i, line := range lines {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		text := strings.TrimRight(line.Text, "\r")
+		loc := blockIDMarkerRegex.FindStringSubmatchIndex(text)
+		if loc == nil || text[loc[2]:loc[3]] != id {
+			continue
+		}
+
+		var block []string
+		if strings.TrimSpace(text[:loc[0]]) == "" {
+			if i == 0 {
+				break
+			}
+			start := blockStart(lines, i-1)
+			for _, l := range lines[start:i] {
+				block = append(block, strings.TrimRight(l.Text, "\r"))
+			}
+		} else {
+			start := blockStart(lines, i)
+			for _, l := range lines[start:i] {
+				block = append(block, strings.TrimRight(l.Text, "\r"))
+			}
+			block = append(block, strings.TrimRight(text[:loc[0]], " \t"))
+		}
+		if len(block) > 0 {
+			return strings.Join(block, "\n"), nil
+		}
+	}
+	return "", NewError(ErrCodeBlockNotFound, BlockNotFoundError)
+}
+
+// AddBlockID marks the block on line (1-based) of content with a block ID
+// and returns the new content and the ID. For a paragraph the marker goes at
+// the end of its last line; headings cannot be marked, as links reach them
+// by their text. When the block already has an ID, that ID is returned with
+// the content unchanged. An empty id generates a random one the way Obsidian
+// does.
+func AddBlockID(content string, line int, id string) (string, string, error) {
+	lines := splitMarkdownLines(content)
+	if line < 1 || line > len(lines) {
+		return "", "", NewError(ErrCodeInvalidArgument, NotABlockError)
+	}
+	target := line - 1
+	if isBlockBoundary(lines[target]) || isHeadingLine(lines[target]) {
+		return "", "", NewError(ErrCodeInvalidArgument, NotABlockError)
+	}
+	if !isBlockLeader(lines[target]) {
+		for target+1 < len(lines) && !isBlockBoundary(lines[target+1]) && !isBlockLeader(lines[target+1]) {
+			target++
+		}
+	}
+
+	text := strings.TrimRight(lines[target].Text, "\r")
+	if m := blockIDMarkerRegex.FindStringSubmatch(text); m != nil {
+		return content, m[1], nil
+	}
+
+	if id == "" {
+		generated, err := newBlockID(lines)
+		if err != nil {
+			return "", "", WrapError(ErrCodeUnknown, "Failed to generate block ID", err)
+		}
+		id = generated
+	} else if !validBlockIDRegex.MatchString(id) {
+		return "", "", NewError(ErrCodeInvalidArgument, fmt.Sprintf("invalid block ID %q: use only letters, numbers and dashes", id))
+	} else if hasBlockID(lines, id) {
+		return "", "", NewError(ErrCodeInvalidArgument, fmt.Sprintf("block ID %q is already used in the note", id))
+	}
+
+	offset := lines[target].Offset + len(text)
+	return content[:offset] + " ^" + id + content[offset:], id, nil
+}
+
+// blockStart returns the first line of the block that ends on line end.
+func blockStart(lines []markdownLine, end int) int {
+	if lines[end].InCode {
+		start := end
+		for start > 0 && lines[start-1].InCode {
+			start--
+		}
+		return start
+	}
+
+	start := end
+	for !isBlockLeader(lines[start]) && start > 0 && !isBlockBoundary(lines[start-1]) && !isHeadingLine(lines[start-1]) {
+		start--
+	}
+	return start
+}
+
+// isBlockBoundary reports whether line cannot be part of a paragraph: a
+// blank line or a line of frontmatter or code.
+func isBlockBoundary(line markdownLine) bool {
+	return line.InCode || line.InFrontmatter || strings.TrimSpace(line.Text) == ""
+}
+
+// isBlockLeader reports whether line is a block of its own, a heading or a
+// list item, rather than a line of a paragraph.
+func isBlockLeader(line markdownLine) bool {
+	return isHeadingLine(line) || blockListItemRegex.MatchString(line.Text)
+}
+
+func isHeadingLine(line markdownLine) bool {
+	return headingRegex.MatchString(strings.TrimRight(line.Text, "\r"))
+}
+
+func hasBlockID(lines []markdownLine, id string) bool {
+	for _, line := range lines {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		if m := blockIDMarkerRegex.FindStringSubmatch(strings.TrimRight(line.Text, "\r")); m != nil && m[1] == id {
+			return true
+		}
+	}
+	return false
+}
+
+// newBlockID returns a random six character block ID not yet used in lines.
+func newBlockID(lines []markdownLine) (string, error) {
+	buf := make([]byte, 6)
+	for {
+		if _, err := rand.Read(buf); err != nil {
+			return "", err
+		}
+		for i, b := range buf {
+			buf[i] = blockIDAlphabet[int(b)%len(blockIDAlphabet)]
+		}
+		if id := string(buf); !hasBlockID(lines, id) {
+			return id, nil
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package obsidian_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitBlockRef(t *testing.T) {
+	note, id := obsidian.SplitBlockRef("folder/note#^abc123")
+	assert.Equal(t, "folder/note", note)
+	assert.Equal(t, "abc123", id)
+
+	note, id = obsidian.SplitBlockRef("note#Heading")
+	assert.Equal(t, "note#Heading", note)
+	assert.Equal(t, "", id)
+}
+
+func TestFindBlock(t *testing.T) {
+	content := "---\nid: ^front\n---\n# Title\n\nFirst line\nsecond line ^para\n\n- one\n- two ^item\n  more\n\n| a | b |\n| - | - |\n\n> quote\n> lines\n^quote\n\n```\ncode ^code\n```\n^fenced\n"
+	tests := []struct {
+		name     string
+		id       string
+		expected string
+	}{
+		{"Paragraph", "para", "First line\nsecond line"},
+		{"List item", "item", "- two"},
+		{"Block above a marker line", "quote", "> quote\n> lines"},
+		{"Code block above a marker line", "fenced", "```\ncode ^code\n```"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			block, err := obsidian.FindBlock(content, test.id)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, block)
+		})
+	}
+
+	t.Run("Markers in code and frontmatter are ignored", func(t *testing.T) {
+		for _, id := range []string{"code", "front", "missing"} {
+			_, err := obsidian.FindBlock(content, id)
+			assert.Equal(t, obsidian.ErrCodeBlockNotFound, obsidian.ErrorCode(err), id)
+		}
+	})
+
+	t.Run("Windows line endings", func(t *testing.T) {
+		block, err := obsidian.FindBlock("a\r\nb ^x\r\n", "x")
+		assert.NoError(t, err)
+		assert.Equal(t, "a\nb", block)
+	})
+}
+
+func TestAddBlockID(t *testing.T) {
+	content := "# Title\n\nFirst line\nsecond line\n\n- one\n- two ^used\n"
+
+	t.Run("Marker goes at the end of the paragraph", func(t *testing.T) {
+		updated, id, err := obsidian.AddBlockID(content, 3, "intro")
+		assert.NoError(t, err)
+		assert.Equal(t, "intro", id)
+		assert.Equal(t, "# Title\n\nFirst line\nsecond line ^intro\n\n- one\n- two ^used\n", updated)
+	})
+
+	t.Run("List item", func(t *testing.T) {
+		updated, _, err := obsidian.AddBlockID(content, 6, "one")
+		assert.NoError(t, err)
+		assert.Equal(t, "# Title\n\nFirst line\nsecond line\n\n- one ^one\n- two ^used\n", updated)
+	})
+
+	t.Run("Existing ID is returned", func(t *testing.T) {
+		updated, id, err := obsidian.AddBlockID(content, 7, "")
+		assert.NoError(t, err)
+		assert.Equal(t, "used", id)
+		assert.Equal(t, content, updated)
+	})
+
+	t.Run("Generated ID", func(t *testing.T) {
+		updated, id, err := obsidian.AddBlockID(content, 4, "")
+		assert.NoError(t, err)
+		assert.Regexp(t, regexp.MustCompile(`^[a-z0-9]{6}$`), id)
+		assert.Contains(t, updated, "second line ^"+id+"\n")
+	})
+
+	t.Run("Windows line endings", func(t *testing.T) {
+		updated, _, err := obsidian.AddBlockID("a\r\nb\r\n", 1, "x")
+		assert.NoError(t, err)
+		assert.Equal(t, "a\r\nb ^x\r\n", updated)
+	})
+
+	t.Run("Rejected lines and IDs", func(t *testing.T) {
+		for _, test := range []struct {
+			line int
+			id   string
+		}{
+			{1, "x"},      // heading
+			{2, "x"},      // blank line
+			{99, "x"},     // out of range
+			{3, "used"},   // taken
+			{3, "bad id"}, // invalid characters
+		} {
+			_, _, err := obsidian.AddBlockID(content, test.line, test.id)
+			assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err), "line %d, id %q", test.line, test.id)
+		}
+	})
+}
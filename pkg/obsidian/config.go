@@ -8,10 +8,20 @@ import (
 	"strings"
 )
 
+// Values of the newLinkFormat setting in .obsidian/app.json.
+const (
+	LinkFormatShortest = "shortest"
+	LinkFormatRelative = "relative"
+	LinkFormatAbsolute = "absolute"
+)
+
 // ObsidianAppConfig represents relevant fields from .obsidian/app.json.
 type ObsidianAppConfig struct {
-	NewFileLocation   string `json:"newFileLocation"`
-	NewFileFolderPath string `json:"newFileFolderPath"`
+	NewFileLocation      string `json:"newFileLocation"`
+	NewFileFolderPath    string `json:"newFileFolderPath"`
+	NewLinkFormat        string `json:"newLinkFormat"`
+	UseMarkdownLinks     bool   `json:"useMarkdownLinks"`
+	AttachmentFolderPath string `json:"attachmentFolderPath"`
 }
 
 // DailyNotesConfig represents relevant fields from .obsidian/daily-notes.json.
@@ -21,20 +31,34 @@ type DailyNotesConfig struct {
 	Template string `json:"template"`
 }
 
-// DefaultNoteFolder reads the configured default folder for new notes from
-// .obsidian/app.json. Returns "" if not configured or unreadable (caller
-// should use vault root).
-func DefaultNoteFolder(vaultPath string) string {
+// TemplatesConfig represents relevant fields from .obsidian/templates.json.
+type TemplatesConfig struct {
+	Folder     string `json:"folder"`
+	DateFormat string `json:"dateFormat"`
+	TimeFormat string `json:"timeFormat"`
+}
+
+// ReadObsidianAppConfig reads .obsidian/app.json from the vault. Returns
+// zero-value config if unreadable.
+func ReadObsidianAppConfig(vaultPath string) ObsidianAppConfig {
 	data, err := os.ReadFile(filepath.Join(vaultPath, ".obsidian", "app.json"))
 	if err != nil {
-		return ""
+		return ObsidianAppConfig{}
 	}
 
 	var config ObsidianAppConfig
 	if err := json.Unmarshal(data, &config); err != nil {
-		return ""
+		return ObsidianAppConfig{}
 	}
 
+	return config
+}
+
+// DefaultNoteFolder reads the configured default folder for new notes from
+// .obsidian/app.json. Returns "" if not configured or unreadable (caller
+// should use vault root).
+func DefaultNoteFolder(vaultPath string) string {
+	config := ReadObsidianAppConfig(vaultPath)
 	if config.NewFileLocation == "folder" && config.NewFileFolderPath != "" {
 		return config.NewFileFolderPath
 	}
@@ -58,6 +82,22 @@ func ReadDailyNotesConfig(vaultPath string) DailyNotesConfig {
 	return config
 }
 
+// ReadTemplatesConfig reads the core templates plugin config from the vault.
+// Returns zero-value config if unreadable.
+func ReadTemplatesConfig(vaultPath string) TemplatesConfig {
+	data, err := os.ReadFile(filepath.Join(vaultPath, ".obsidian", "templates.json"))
+	if err != nil {
+		return TemplatesConfig{}
+	}
+
+	var config TemplatesConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return TemplatesConfig{}
+	}
+
+	return config
+}
+
 // ApplyDefaultFolder prepends the configured default note folder to noteName
 // when noteName has no explicit path (no "/"). If the note name already
 // contains a "/", it is treated as an explicit path and returned unchanged.
@@ -78,6 +118,9 @@ func ApplyDefaultFolder(noteName, vaultPath string) string {
 //
 // Note: the Moment.js "dd" token (2-letter weekday like "Mo", "Tu") has no Go
 // equivalent and is not supported.
+//
+// Deprecated: many Moment.js tokens, such as ordinals, weeks and bracketed
+// text, have no Go layout equivalent. Use FormatMoment and ParseMoment.
 func MomentToGoFormat(momentFmt string) string {
 	// Order matters: longer tokens must be replaced before shorter ones.
 	replacements := []struct {
@@ -111,6 +111,28 @@ func TestApplyDefaultFolder(t *testing.T) {
 	})
 }
 
+func TestReadObsidianAppConfig(t *testing.T) {
+	t.Run("Reads link settings", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		obsDir := filepath.Join(tmpDir, ".obsidian")
+		os.MkdirAll(obsDir, 0755)
+		os.WriteFile(filepath.Join(obsDir, "app.json"), []byte(`{
+			"newLinkFormat": "relative",
+			"useMarkdownLinks": true
+		}`), 0644)
+
+		config := obsidian.ReadObsidianAppConfig(tmpDir)
+		assert.Equal(t, obsidian.LinkFormatRelative, config.NewLinkFormat)
+		assert.True(t, config.UseMarkdownLinks)
+	})
+
+	t.Run("Returns zero config when file is absent", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		config := obsidian.ReadObsidianAppConfig(tmpDir)
+		assert.Equal(t, obsidian.ObsidianAppConfig{}, config)
+	})
+}
+
 func TestReadDailyNotesConfig(t *testing.T) {
 	t.Run("Reads full config", func(t *testing.T) {
 		tmpDir := t.TempDir()
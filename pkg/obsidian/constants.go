@@ -3,6 +3,8 @@ package obsidian
 const (
 	ExecuteUriError                    = "Failed to execute Obsidian URI"
 	NoteDoesNotExistError              = "Cannot find note in vault"
+	FolderDoesNotExistError            = "Cannot find folder in vault"
+	SourceDoesNotExistError            = "Source does not exist in vault"
 	VaultAccessError                   = "Failed to access vault directory"
 	VaultReadError                     = "Failed to read notes in vault"
 	VaultWriteError                    = "Failed to write to update notes in vault"
@@ -14,4 +16,44 @@ const (
 	ObsidianConfigReadError            = "Failed to read Obsidian config file. Please ensure vault has been set up in Obsidian."
 	ObsidianConfigParseError           = "Failed to parse Obsidian config file. Please ensure vault has been set up in Obsidian."
 	ObsidianConfigVaultNotFoundError   = "Vault not found in Obsidian config file. Please ensure vault has been set up in Obsidian."
+	IndexReadError                     = "Failed to read vault index. Run 'index rebuild' to recreate it."
+	IndexWriteError                    = "Failed to write vault index. Please ensure you have correct permissions."
+	NotATaskError                      = "Line is not a task. Tasks are list items with a checkbox, e.g. '- [ ] do something'."
+	TemplateNotFoundError              = "Cannot find template in vault"
+	SectionNotFoundError               = "Cannot find section in note"
+	BlockNotFoundError                 = "Cannot find block in note"
+	NotABlockError                     = "Line is not a block. Block IDs can be added to paragraphs and list items, outside code blocks and frontmatter."
+	InvalidTagError                    = "Invalid tag. Tags may only contain letters, numbers, '_', '-' and '/', and cannot be purely numeric."
+)
+
+// Error codes reported alongside error messages in machine-readable output.
+// Unlike the messages above they never change once released.
+const (
+	ErrCodeUnknown                = "unknown"
+	ErrCodeInvalidArgument        = "invalid_argument"
+	ErrCodeNoteNotFound           = "note_not_found"
+	ErrCodePathTraversal          = "path_traversal"
+	ErrCodeVaultNotFound          = "vault_not_found"
+	ErrCodeVaultAccess            = "vault_access_failed"
+	ErrCodeVaultRead              = "vault_read_failed"
+	ErrCodeVaultWrite             = "vault_write_failed"
+	ErrCodeConfigDirNotFound      = "config_dir_not_found"
+	ErrCodeCLIConfigNotFound      = "cli_config_not_found"
+	ErrCodeCLIConfigInvalid       = "cli_config_invalid"
+	ErrCodeCLIConfigWrite         = "cli_config_write_failed"
+	ErrCodeObsidianConfigNotFound = "obsidian_config_not_found"
+	ErrCodeObsidianConfigInvalid  = "obsidian_config_invalid"
+	ErrCodeExecuteUri             = "uri_execute_failed"
+	ErrCodeEditor                 = "editor_failed"
+	ErrCodeIndexRead              = "index_read_failed"
+	ErrCodeIndexWrite             = "index_write_failed"
+	ErrCodeInvalidFrontmatter     = "invalid_frontmatter"
+	ErrCodeNoFrontmatter          = "no_frontmatter"
+	ErrCodeInvalidQuery           = "invalid_query"
+	ErrCodeKeyNotFound            = "key_not_found"
+	ErrCodeKeyExists              = "key_exists"
+	ErrCodeInvalidSchema          = "invalid_schema"
+	ErrCodeTemplateNotFound       = "template_not_found"
+	ErrCodeSectionNotFound        = "section_not_found"
+	ErrCodeBlockNotFound          = "block_not_found"
 )
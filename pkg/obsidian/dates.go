@@ -0,0 +1,81 @@
+package obsidian
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	relativeDaysAgo = regexp.MustCompile(`^(\d+) (day|week)s? ago$`)
+	relativeDaysIn  = regexp.MustCompile(`^in (\d+) (day|week)s?$`)
+	relativeWeekday = regexp.MustCompile(`^(?:(last|next|this) )?(sunday|monday|tuesday|wednesday|thursday|friday|saturday)$`)
+)
+
+// ResolveDate returns the day described by phrase, relative to now. It
+// accepts a date in YYYY-MM-DD form, "today", "yesterday", "tomorrow",
+// "N days ago", "in N days" (or weeks), "last week", "next week", and a
+// weekday optionally preceded by "last", "next" or "this": "friday" and
+// "last friday" are the most recent Friday before today, "next friday" the
+// first one after today and "this friday" the one in the current week
+// (starting on Sunday). The result is midnight of that day in now's zone.
+func ResolveDate(phrase string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	text := strings.ToLower(strings.Join(strings.Fields(phrase), " "))
+
+	switch text {
+	case "today":
+		return today, nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "last week":
+		return today.AddDate(0, 0, -7), nil
+	case "next week":
+		return today.AddDate(0, 0, 7), nil
+	}
+
+	if date, err := time.ParseInLocation("2006-01-02", text, now.Location()); err == nil {
+		return date, nil
+	}
+
+	if match := relativeDaysAgo.FindStringSubmatch(text); match != nil {
+		return today.AddDate(0, 0, -relativeDays(match[1], match[2])), nil
+	}
+	if match := relativeDaysIn.FindStringSubmatch(text); match != nil {
+		return today.AddDate(0, 0, relativeDays(match[1], match[2])), nil
+	}
+
+	if match := relativeWeekday.FindStringSubmatch(text); match != nil {
+		weekday := time.Weekday(weekdayValue(match[2]))
+		switch match[1] {
+		case "next":
+			days := (int(weekday) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), nil
+		case "this":
+			return today.AddDate(0, 0, int(weekday)-int(today.Weekday())), nil
+		default:
+			days := (int(today.Weekday()) - int(weekday) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, -days), nil
+		}
+	}
+
+	return time.Time{}, NewError(ErrCodeInvalidArgument, fmt.Sprintf("invalid date %q: use YYYY-MM-DD, today, yesterday, tomorrow, N days ago or a weekday such as \"last friday\"", phrase))
+}
+
+func relativeDays(count, unit string) int {
+	n, _ := strconv.Atoi(count)
+	if unit == "week" {
+		return 7 * n
+	}
+	return n
+}
@@ -0,0 +1,51 @@
+package obsidian_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveDate(t *testing.T) {
+	// A Wednesday.
+	now := time.Date(2026, time.October, 14, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		phrase   string
+		expected string
+	}{
+		{"today", "2026-10-14"},
+		{"Yesterday", "2026-10-13"},
+		{"tomorrow", "2026-10-15"},
+		{"2026-10-01", "2026-10-01"},
+		{"3 days ago", "2026-10-11"},
+		{"1 day ago", "2026-10-13"},
+		{"in 2 weeks", "2026-10-28"},
+		{"last week", "2026-10-07"},
+		{"next week", "2026-10-21"},
+		{"friday", "2026-10-09"},
+		{"last friday", "2026-10-09"},
+		{"last  Wednesday", "2026-10-07"},
+		{"next friday", "2026-10-16"},
+		{"next wednesday", "2026-10-21"},
+		{"this monday", "2026-10-12"},
+		{"this saturday", "2026-10-17"},
+	}
+	for _, test := range tests {
+		t.Run(test.phrase, func(t *testing.T) {
+			date, err := obsidian.ResolveDate(test.phrase, now)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, date.Format("2006-01-02"))
+			assert.Equal(t, 0, date.Hour())
+		})
+	}
+
+	t.Run("Invalid phrases", func(t *testing.T) {
+		for _, phrase := range []string{"someday", "2026-13-01", "last fortnight"} {
+			_, err := obsidian.ResolveDate(phrase, now)
+			assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err), phrase)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package obsidian_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnifiedDiff(t *testing.T) {
+	t.Run("Identical content has no diff", func(t *testing.T) {
+		assert.Equal(t, "", obsidian.UnifiedDiff("a.md", "same\n", "same\n"))
+	})
+
+	t.Run("Single change with context", func(t *testing.T) {
+		before := "---\nstate: done\ntitle: A\n---\nBody\n"
+		after := "---\nstatus: done\ntitle: A\n---\nBody\n"
+
+		assert.Equal(t, "--- a/Projects/a.md\n+++ b/Projects/a.md\n@@ -1,5 +1,5 @@\n ---\n-state: done\n+status: done\n title: A\n ---\n Body\n",
+			obsidian.UnifiedDiff("Projects/a.md", before, after))
+	})
+
+	t.Run("Distant changes get separate hunks", func(t *testing.T) {
+		lines := make([]string, 20)
+		for i := range lines {
+			lines[i] = string(rune('a' + i))
+		}
+		before := strings.Join(lines, "\n")
+		lines[1], lines[17] = "B", "R"
+		after := strings.Join(lines, "\n")
+
+		assert.Equal(t, "--- a/x.md\n+++ b/x.md\n"+
+			"@@ -1,5 +1,5 @@\n a\n-b\n+B\n c\n d\n e\n"+
+			"@@ -15,6 +15,6 @@\n o\n p\n q\n-r\n+R\n s\n t\n",
+			obsidian.UnifiedDiff("x.md", before, after))
+	})
+
+	t.Run("Added lines at the start", func(t *testing.T) {
+		assert.Equal(t, "--- a/x.md\n+++ b/x.md\n@@ -1 +1,4 @@\n+---\n+tags: [a]\n+---\n Body\n",
+			obsidian.UnifiedDiff("x.md", "Body", "---\ntags: [a]\n---\nBody"))
+	})
+
+	t.Run("Removed lines only", func(t *testing.T) {
+		assert.Equal(t, "--- a/x.md\n+++ b/x.md\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+			obsidian.UnifiedDiff("x.md", "a\nb\n", ""))
+	})
+}
@@ -0,0 +1,63 @@
+package obsidian
+
+import (
+	"errors"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/config"
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+)
+
+// Error is a failure with a stable, machine-readable code alongside the
+// human-readable message. Codes are part of the documented output schema;
+// messages may change.
+type Error struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Err is the underlying cause, if any.
+	Err error `json:"-"`
+}
+
+// NewError returns an error with the given code and message.
+func NewError(code, message string) error {
+	return &Error{Code: code, Message: message}
+}
+
+// WrapError returns an error with the given code and message that unwraps to
+// err.
+func WrapError(code, message string, err error) error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code identifying err. Errors from other packages
+// with a known message are mapped to a code too; anything else is
+// ErrCodeUnknown.
+func ErrorCode(err error) string {
+	var coded *Error
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+
+	switch err.Error() {
+	case config.UserConfigDirectoryNotFoundErrorMessage:
+		return ErrCodeConfigDirNotFound
+	case frontmatter.InvalidFrontmatterError:
+		return ErrCodeInvalidFrontmatter
+	case frontmatter.NoFrontmatterError:
+		return ErrCodeNoFrontmatter
+	case frontmatter.KeyNotFoundError:
+		return ErrCodeKeyNotFound
+	case frontmatter.KeyExistsError:
+		return ErrCodeKeyExists
+	case frontmatter.InvalidKeyPathError, frontmatter.KeyPathTypeError, frontmatter.IndexOutOfRangeError, frontmatter.NotAListError:
+		return ErrCodeInvalidArgument
+	}
+	return ErrCodeUnknown
+}
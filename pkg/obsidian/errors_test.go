@@ -0,0 +1,45 @@
+package obsidian_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/config"
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Coded error", obsidian.NewError(obsidian.ErrCodeNoteNotFound, obsidian.NoteDoesNotExistError), obsidian.ErrCodeNoteNotFound},
+		{"Wrapped coded error", fmt.Errorf("context: %w", obsidian.ErrPathTraversal), obsidian.ErrCodePathTraversal},
+		{"Config directory error", errors.New(config.UserConfigDirectoryNotFoundErrorMessage), obsidian.ErrCodeConfigDirNotFound},
+		{"Frontmatter error", errors.New(frontmatter.NoFrontmatterError), obsidian.ErrCodeNoFrontmatter},
+		{"Frontmatter key error", errors.New(frontmatter.KeyExistsError), obsidian.ErrCodeKeyExists},
+		{"Other error", errors.New("boom"), obsidian.ErrCodeUnknown},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, obsidian.ErrorCode(test.err))
+		})
+	}
+
+	t.Run("Errors keep their messages and causes", func(t *testing.T) {
+		err := obsidian.WrapError(obsidian.ErrCodeVaultWrite, "write failed", os.ErrPermission)
+		assert.EqualError(t, err, "write failed")
+		assert.ErrorIs(t, err, os.ErrPermission)
+	})
+
+	t.Run("Note errors carry codes", func(t *testing.T) {
+		note := obsidian.Note{}
+		_, err := note.GetContents(t.TempDir(), "missing")
+		assert.Equal(t, obsidian.ErrCodeNoteNotFound, obsidian.ErrorCode(err))
+	})
+}
@@ -0,0 +1,237 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+	"github.com/Yakitrak/notesmd-cli/pkg/query"
+)
+
+// Frontmatter operations applied by EditFrontmatter.
+const (
+	FrontmatterSet    = "set"
+	FrontmatterDelete = "delete"
+	FrontmatterRename = "rename"
+	FrontmatterAppend = "append"
+	FrontmatterRemove = "remove"
+)
+
+// NoteSelector chooses the notes a bulk operation applies to. Every criterion
+// that is set must hold.
+type NoteSelector struct {
+	// Glob matches vault-relative paths; "*" stays within a folder and "**"
+	// spans folders, e.g. "Projects/**/*.md".
+	Glob string
+	// Folder keeps notes at or below a vault-relative folder.
+	Folder string
+	// Tag keeps notes with the tag, or one of its nested tags, in their
+	// frontmatter or body.
+	Tag string
+	// Where is a condition on frontmatter in query WHERE syntax, e.g.
+	// `state = "done"`.
+	Where string
+}
+
+// IsEmpty reports whether no criterion is set.
+func (s NoteSelector) IsEmpty() bool {
+	return s.Glob == "" && s.Folder == "" && s.Tag == "" && s.Where == ""
+}
+
+// FrontmatterEdit describes a change to one frontmatter key.
+type FrontmatterEdit struct {
+	Operation string
+	// Key is a key path such as "status", "project.owner" or "aliases[0]".
+	Key string
+	// Value is the new value for set, or the items to add or remove for
+	// append and remove.
+	Value string
+	// NewKey is the key's new name for rename.
+	NewKey string
+}
+
+// FrontmatterChange is a note changed (or, in a dry run, that would be
+// changed) by EditFrontmatter, with a unified diff of the change.
+type FrontmatterChange struct {
+	Path string `json:"path"`
+	Diff string `json:"diff"`
+}
+
+// SelectNotes returns the sorted paths of the notes matching selector.
+func SelectNotes(vaultPath string, selector NoteSelector, now time.Time) ([]string, error) {
+	pages, err := selectPages(vaultPath, selector, now)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(pages))
+	for i, page := range pages {
+		paths[i] = page.Path
+	}
+	return paths, nil
+}
+
+func selectPages(vaultPath string, selector NoteSelector, now time.Time) ([]query.Page, error) {
+	matcher, err := compileSelector(selector)
+	if err != nil {
+		return nil, err
+	}
+
+	pages, err := queryPages(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := pages[:0]
+	for i := range pages {
+		if matcher.matches(&pages[i], now) {
+			selected = append(selected, pages[i])
+		}
+	}
+	return selected, nil
+}
+
+// noteMatcher is a NoteSelector prepared for matching.
+type noteMatcher struct {
+	glob   *regexp.Regexp
+	folder string
+	tag    string
+	where  *query.Condition
+}
+
+func compileSelector(selector NoteSelector) (*noteMatcher, error) {
+	m := &noteMatcher{folder: strings.Trim(normalizePathSeparators(selector.Folder), "/")}
+	if selector.Glob != "" {
+		m.glob = globRegexp(normalizePathSeparators(selector.Glob))
+	}
+	if selector.Tag != "" {
+		tag, err := NormalizeTag(selector.Tag)
+		if err != nil {
+			return nil, err
+		}
+		m.tag = tag
+	}
+	if selector.Where != "" {
+		where, err := query.ParseCondition(selector.Where)
+		if err != nil {
+			return nil, WrapError(ErrCodeInvalidQuery, err.Error(), err)
+		}
+		m.where = where
+	}
+	return m, nil
+}
+
+func (m *noteMatcher) matches(page *query.Page, now time.Time) bool {
+	if m.glob != nil && !m.glob.MatchString(page.Path) {
+		return false
+	}
+	if m.folder != "" && !strings.HasPrefix(page.Path, m.folder+"/") {
+		return false
+	}
+	if m.tag != "" && !hasTag(page.Tags, m.tag) {
+		return false
+	}
+	return m.where == nil || m.where.Matches(page, now)
+}
+
+// globRegexp converts a path glob to an anchored regular expression.
+func globRegexp(glob string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(?:.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if _, ok := renameTagValue(t, tag, tag); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// EditFrontmatter applies edit to every note matching selector and returns
+// the notes that changed. Notes the edit does not affect, such as notes
+// without the key being deleted, renamed or removed from, are skipped. With dryRun set
+// nothing is written; otherwise all notes are written together or, if any
+// note fails, none are.
+func EditFrontmatter(vaultPath string, selector NoteSelector, edit FrontmatterEdit, dryRun bool, now time.Time) ([]FrontmatterChange, error) {
+	pages, err := selectPages(vaultPath, selector, now)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := []FrontmatterChange{}
+	updates := make(map[string]string)
+	for _, page := range pages {
+		data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(page.Path)))
+		if err != nil {
+			return nil, NewError(ErrCodeVaultRead, VaultReadError)
+		}
+		content := string(data)
+		updated, err := applyFrontmatterEdit(content, edit)
+		if err != nil {
+			return nil, WrapError(ErrorCode(err), fmt.Sprintf("%s: %s", page.Path, err.Error()), err)
+		}
+		if updated == content {
+			continue
+		}
+		updates[page.Path] = updated
+		changes = append(changes, FrontmatterChange{Path: page.Path, Diff: UnifiedDiff(page.Path, content, updated)})
+	}
+
+	if !dryRun {
+		if err := writeNotesAtomically(vaultPath, updates); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func applyFrontmatterEdit(content string, edit FrontmatterEdit) (string, error) {
+	switch edit.Operation {
+	case FrontmatterSet:
+		return frontmatter.SetKey(content, edit.Key, edit.Value)
+	case FrontmatterAppend:
+		return frontmatter.AppendValue(content, edit.Key, edit.Value)
+	case FrontmatterDelete, FrontmatterRename, FrontmatterRemove:
+	default:
+		return "", NewError(ErrCodeInvalidArgument, fmt.Sprintf("unknown frontmatter operation %q", edit.Operation))
+	}
+
+	hasKey, err := frontmatter.HasKey(content, edit.Key)
+	if err != nil || !hasKey {
+		return content, err
+	}
+	switch edit.Operation {
+	case FrontmatterDelete:
+		return frontmatter.DeleteKey(content, edit.Key)
+	case FrontmatterRename:
+		return frontmatter.RenameKey(content, edit.Key, edit.NewKey)
+	}
+	return frontmatter.RemoveValue(content, edit.Key, edit.Value)
+}
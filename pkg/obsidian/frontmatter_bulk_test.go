@@ -0,0 +1,140 @@
+package obsidian_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func bulkVault(t *testing.T) string {
+	t.Helper()
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"Projects/alpha.md":         "---\nstate: active\ntags: [project]\n---\nAlpha",
+		"Projects/beta.md":          "---\nstate: done\n---\nBeta #project",
+		"Projects/Archive/gamma.md": "---\nstate: done\n---\nGamma",
+		"inbox.md":                  "Inbox",
+	})
+	return vaultPath
+}
+
+func TestSelectNotes(t *testing.T) {
+	vaultPath := bulkVault(t)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		selector obsidian.NoteSelector
+		expected []string
+	}{
+		{"Glob within a folder", obsidian.NoteSelector{Glob: "Projects/*.md"}, []string{"Projects/alpha.md", "Projects/beta.md"}},
+		{"Glob across folders", obsidian.NoteSelector{Glob: "**/gamma.md"}, []string{"Projects/Archive/gamma.md"}},
+		{"Folder", obsidian.NoteSelector{Folder: "Projects/Archive/"}, []string{"Projects/Archive/gamma.md"}},
+		{"Tag", obsidian.NoteSelector{Tag: "#project"}, []string{"Projects/alpha.md", "Projects/beta.md"}},
+		{"Where", obsidian.NoteSelector{Where: `state = "done"`}, []string{"Projects/Archive/gamma.md", "Projects/beta.md"}},
+		{"Combined", obsidian.NoteSelector{Folder: "Projects", Tag: "project", Where: `state = "done"`}, []string{"Projects/beta.md"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			paths, err := obsidian.SelectNotes(vaultPath, test.selector, now)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, paths)
+		})
+	}
+
+	t.Run("Invalid where condition", func(t *testing.T) {
+		_, err := obsidian.SelectNotes(vaultPath, obsidian.NoteSelector{Where: `state =`}, now)
+		assert.Equal(t, obsidian.ErrCodeInvalidQuery, obsidian.ErrorCode(err))
+	})
+}
+
+func TestEditFrontmatter(t *testing.T) {
+	now := time.Now()
+
+	t.Run("Renames a key in the selected notes", func(t *testing.T) {
+		vaultPath := bulkVault(t)
+		edit := obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterRename, Key: "state", NewKey: "status"}
+
+		changes, err := obsidian.EditFrontmatter(vaultPath, obsidian.NoteSelector{Glob: "**"}, edit, false, now)
+
+		assert.NoError(t, err)
+		assert.Len(t, changes, 3)
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "Projects", "beta.md"))
+		assert.Contains(t, string(content), "status: done")
+		assert.NotContains(t, string(content), "state:")
+		content, _ = os.ReadFile(filepath.Join(vaultPath, "inbox.md"))
+		assert.Equal(t, "Inbox", string(content))
+	})
+
+	t.Run("Dry run returns diffs without writing", func(t *testing.T) {
+		vaultPath := bulkVault(t)
+		edit := obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterSet, Key: "state", Value: "archived"}
+
+		changes, err := obsidian.EditFrontmatter(vaultPath, obsidian.NoteSelector{Folder: "Projects/Archive"}, edit, true, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.FrontmatterChange{{
+			Path: "Projects/Archive/gamma.md",
+			Diff: "--- a/Projects/Archive/gamma.md\n+++ b/Projects/Archive/gamma.md\n@@ -1,4 +1,4 @@\n ---\n-state: done\n+state: archived\n ---\n Gamma\n",
+		}}, changes)
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "Projects", "Archive", "gamma.md"))
+		assert.Contains(t, string(content), "state: done")
+	})
+
+	t.Run("Appends to lists and skips notes already containing the value", func(t *testing.T) {
+		vaultPath := bulkVault(t)
+		edit := obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterAppend, Key: "tags", Value: "project"}
+
+		changes, err := obsidian.EditFrontmatter(vaultPath, obsidian.NoteSelector{Folder: "Projects"}, edit, false, now)
+
+		assert.NoError(t, err)
+		assert.Len(t, changes, 2)
+		assert.Equal(t, "Projects/Archive/gamma.md", changes[0].Path)
+		assert.Equal(t, "Projects/beta.md", changes[1].Path)
+	})
+
+	t.Run("Delete skips notes without the key", func(t *testing.T) {
+		vaultPath := bulkVault(t)
+		edit := obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterDelete, Key: "tags"}
+
+		changes, err := obsidian.EditFrontmatter(vaultPath, obsidian.NoteSelector{Glob: "**/*.md"}, edit, false, now)
+
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		assert.Equal(t, "Projects/alpha.md", changes[0].Path)
+	})
+
+	t.Run("Removes list items from nested keys", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a.md": "---\nproject:\n  members: [Ann, Bob]\n---\n",
+			"b.md": "---\nproject: x\n---\n",
+			"c.md": "No frontmatter",
+		})
+		edit := obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterRemove, Key: "project.members", Value: "Bob"}
+
+		changes, err := obsidian.EditFrontmatter(vaultPath, obsidian.NoteSelector{Glob: "*.md"}, edit, false, now)
+
+		assert.NoError(t, err)
+		assert.Len(t, changes, 1)
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "a.md"))
+		assert.Equal(t, "---\nproject:\n  members: [Ann]\n---\n", string(content))
+	})
+
+	t.Run("A conflict in one note leaves every note unchanged", func(t *testing.T) {
+		vaultPath := bulkVault(t)
+		writeVaultFiles(t, vaultPath, map[string]string{"Projects/delta.md": "---\nstate: x\nstatus: y\n---\n"})
+		edit := obsidian.FrontmatterEdit{Operation: obsidian.FrontmatterRename, Key: "state", NewKey: "status"}
+
+		_, err := obsidian.EditFrontmatter(vaultPath, obsidian.NoteSelector{Folder: "Projects"}, edit, false, now)
+
+		assert.EqualError(t, err, "Projects/delta.md: frontmatter key already exists")
+		assert.Equal(t, obsidian.ErrCodeKeyExists, obsidian.ErrorCode(err))
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "Projects", "alpha.md"))
+		assert.Contains(t, string(content), "state: active")
+	})
+}
@@ -0,0 +1,419 @@
+package obsidian
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+)
+
+const (
+	// IndexVersion is bumped whenever the on-disk index layout changes so stale
+	// indexes are rebuilt instead of misread.
+	IndexVersion  = 2
+	IndexFileName = "notesmd-cli-index.json"
+)
+
+// IndexEntry holds the metadata recorded for a single note.
+type IndexEntry struct {
+	Path        string                 `json:"path"`
+	ModTime     int64                  `json:"mtime"`
+	Size        int64                  `json:"size"`
+	Headings    []Heading              `json:"headings,omitempty"`
+	Links       []string               `json:"links,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Frontmatter map[string]interface{} `json:"frontmatter,omitempty"`
+}
+
+// VaultIndex is a persistent cache of note metadata stored in the vault's
+// .obsidian directory. Entries are keyed by vault-relative, slash-separated
+// paths and refreshed incrementally by comparing modification times and sizes.
+type VaultIndex struct {
+	Version   int                    `json:"version"`
+	UpdatedAt time.Time              `json:"updated_at"`
+	Notes     map[string]*IndexEntry `json:"notes"`
+}
+
+// IndexStatus summarises an index and how far it has drifted from the vault.
+type IndexStatus struct {
+	Path      string    `json:"path"`
+	Exists    bool      `json:"exists"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Notes     int       `json:"notes"`
+	Added     int       `json:"added"`
+	Updated   int       `json:"updated"`
+	Removed   int       `json:"removed"`
+}
+
+// IndexFilePath returns where the index for the given vault is stored.
+func IndexFilePath(vaultPath string) string {
+	return filepath.Join(vaultPath, ".obsidian", IndexFileName)
+}
+
+func NewVaultIndex() *VaultIndex {
+	return &VaultIndex{Version: IndexVersion, Notes: make(map[string]*IndexEntry)}
+}
+
+// LoadIndex reads the index for a vault from disk.
+func LoadIndex(vaultPath string) (*VaultIndex, error) {
+	data, err := os.ReadFile(IndexFilePath(vaultPath))
+	if err != nil {
+		return nil, NewError(ErrCodeIndexRead, IndexReadError)
+	}
+
+	idx := NewVaultIndex()
+	if err := json.Unmarshal(data, idx); err != nil || idx.Version != IndexVersion {
+		return nil, NewError(ErrCodeIndexRead, IndexReadError)
+	}
+	if idx.Notes == nil {
+		idx.Notes = make(map[string]*IndexEntry)
+	}
+	return idx, nil
+}
+
+// Save writes the index to disk, replacing any previous version atomically.
+func (idx *VaultIndex) Save(vaultPath string) error {
+	indexPath := IndexFilePath(vaultPath)
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return NewError(ErrCodeIndexWrite, IndexWriteError)
+	}
+
+	data, err := json.Marshal(idx)
+	if err != nil {
+		return NewError(ErrCodeIndexWrite, IndexWriteError)
+	}
+
+	tmpPath := indexPath + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return NewError(ErrCodeIndexWrite, IndexWriteError)
+	}
+	if err := os.Rename(tmpPath, indexPath); err != nil {
+		os.Remove(tmpPath)
+		return NewError(ErrCodeIndexWrite, IndexWriteError)
+	}
+	return nil
+}
+
+// Update brings the index in line with the vault on disk. Only notes whose
+// modification time or size changed are re-read.
+func (idx *VaultIndex) Update(vaultPath string) (IndexStatus, error) {
+	files, err := scanVaultNotes(vaultPath)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	status := IndexStatus{Path: IndexFilePath(vaultPath), Exists: true}
+	for relPath := range idx.Notes {
+		if _, ok := files[relPath]; !ok {
+			delete(idx.Notes, relPath)
+			status.Removed++
+		}
+	}
+
+	for relPath, info := range files {
+		existing, ok := idx.Notes[relPath]
+		if ok && existing.ModTime == info.ModTime().UnixNano() && existing.Size == info.Size() {
+			continue
+		}
+
+		entry, err := buildIndexEntry(vaultPath, relPath, info)
+		if err != nil {
+			return IndexStatus{}, err
+		}
+		idx.Notes[relPath] = entry
+		if ok {
+			status.Updated++
+		} else {
+			status.Added++
+		}
+	}
+
+	if status.Added+status.Updated+status.Removed > 0 || idx.UpdatedAt.IsZero() {
+		idx.UpdatedAt = time.Now()
+	}
+	status.UpdatedAt = idx.UpdatedAt
+	status.Notes = len(idx.Notes)
+	return status, nil
+}
+
+// Lookup resolves a note name to its vault-relative path with the same rule
+// findNotePath uses without an index: an exact relative path wins, otherwise
+// the note with a matching file name that a vault walk reaches first.
+func (idx *VaultIndex) Lookup(noteName string) (string, bool) {
+	paths := make([]string, 0, len(idx.Notes))
+	for relPath := range idx.Notes {
+		paths = append(paths, relPath)
+	}
+	return resolveNoteName(noteName, paths)
+}
+
+// resolveNoteName picks the note noteName refers to among paths, the
+// slash-separated relative paths of the vault's notes: the note at exactly
+// that path, or else the first note in walk order with that file name.
+func resolveNoteName(noteName string, paths []string) (string, bool) {
+	note := normalizePathSeparators(AddMdSuffix(noteName))
+	found := ""
+	for _, relPath := range paths {
+		if relPath == note {
+			return relPath, true
+		}
+		if path.Base(relPath) == note && (found == "" || walkOrderLess(relPath, found)) {
+			found = relPath
+		}
+	}
+	return found, found != ""
+}
+
+// walkOrderLess reports whether filepath.WalkDir reaches the slash-separated
+// path a before b. A walk visits each folder's entries by name, so paths
+// compare folder by folder rather than as whole strings: "a/x.md" comes
+// before "a b/x.md" and "a.md".
+func walkOrderLess(a, b string) bool {
+	as, bs := strings.Split(a, "/"), strings.Split(b, "/")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		if as[i] != bs[i] {
+			return as[i] < bs[i]
+		}
+	}
+	return len(as) < len(bs)
+}
+
+// NotePaths returns the vault-relative paths of all indexed notes, sorted.
+func (idx *VaultIndex) NotePaths() []string {
+	paths := make([]string, 0, len(idx.Notes))
+	for relPath := range idx.Notes {
+		paths = append(paths, relPath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// LinkingNotes returns the sorted paths of notes with at least one link whose
+// target refers to noteName, either by full path or by file name, including
+// relative paths. Matching is
+// case-insensitive so the result is a superset of what pattern-based link
+// searches will find.
+func (idx *VaultIndex) LinkingNotes(noteName string) []string {
+	return idx.linkingNotes([]string{noteName})
+}
+
+// linkingNotes is LinkingNotes for several notes at once: it returns the
+// sorted paths of notes with a link that may refer to any of noteNames.
+func (idx *VaultIndex) linkingNotes(noteNames []string) []string {
+	baseNames := make(map[string]bool, len(noteNames))
+	for _, noteName := range noteNames {
+		baseNames[path.Base(strings.ToLower(RemoveMdSuffix(normalizePathSeparators(noteName))))] = true
+	}
+
+	var linking []string
+	for _, relPath := range idx.NotePaths() {
+		for _, link := range idx.Notes[relPath].Links {
+			target := strings.ToLower(RemoveMdSuffix(normalizePathSeparators(link)))
+			if baseNames[path.Base(target)] {
+				linking = append(linking, relPath)
+				break
+			}
+		}
+	}
+	return linking
+}
+
+// UpdateIndex brings the vault's index up to date, re-reading only the notes
+// that changed, and saves it. Without a usable index it builds one.
+func UpdateIndex(vaultPath string) (IndexStatus, error) {
+	idx, err := LoadIndex(vaultPath)
+	if err != nil {
+		idx = NewVaultIndex()
+	}
+	status, err := idx.Update(vaultPath)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+	if err := idx.Save(vaultPath); err != nil {
+		return IndexStatus{}, err
+	}
+	return status, nil
+}
+
+// RebuildIndex discards any existing index for the vault and indexes every
+// note from scratch.
+func RebuildIndex(vaultPath string) (IndexStatus, error) {
+	idx := NewVaultIndex()
+	status, err := idx.Update(vaultPath)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+	if err := idx.Save(vaultPath); err != nil {
+		return IndexStatus{}, err
+	}
+	return status, nil
+}
+
+// ReadIndexStatus reports on the index without modifying it. Added, Updated
+// and Removed count the notes that changed since the index was last written.
+func ReadIndexStatus(vaultPath string) (IndexStatus, error) {
+	status := IndexStatus{Path: IndexFilePath(vaultPath)}
+	if _, err := os.Stat(status.Path); err != nil {
+		if _, err := os.Stat(vaultPath); err != nil {
+			return IndexStatus{}, NewError(ErrCodeVaultAccess, VaultAccessError)
+		}
+		return status, nil
+	}
+
+	idx, err := LoadIndex(vaultPath)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	files, err := scanVaultNotes(vaultPath)
+	if err != nil {
+		return IndexStatus{}, err
+	}
+
+	status.Exists = true
+	status.UpdatedAt = idx.UpdatedAt
+	status.Notes = len(idx.Notes)
+	for relPath, info := range files {
+		entry, ok := idx.Notes[relPath]
+		switch {
+		case !ok:
+			status.Added++
+		case entry.ModTime != info.ModTime().UnixNano() || entry.Size != info.Size():
+			status.Updated++
+		}
+	}
+	for relPath := range idx.Notes {
+		if _, ok := files[relPath]; !ok {
+			status.Removed++
+		}
+	}
+	return status, nil
+}
+
+// freshIndex loads the vault's index and brings it up to date in memory,
+// re-reading only the notes that changed since it was saved. The index file
+// is left as it is; `index update` or `index rebuild` save it. It returns nil
+// when the vault has no usable index, in which case callers walk the vault.
+func freshIndex(vaultPath string) *VaultIndex {
+	idx, err := LoadIndex(vaultPath)
+	if err != nil {
+		return nil
+	}
+	if _, err := idx.Update(vaultPath); err != nil {
+		return nil
+	}
+	return idx
+}
+
+// skipHidden reports whether a walk of the vault should skip the entry at p,
+// a hidden file or directory such as .obsidian or .trash, and returns
+// filepath.SkipDir for a hidden directory. The index and the walks used
+// without one share it, so both see the same notes.
+func skipHidden(vaultPath, p string, d fs.DirEntry) (bool, error) {
+	if p == vaultPath || !strings.HasPrefix(d.Name(), ".") {
+		return false, nil
+	}
+	if d.IsDir() {
+		return true, filepath.SkipDir
+	}
+	return true, nil
+}
+
+// scanVaultNotes returns the file info of every Markdown note in the vault,
+// keyed by slash-separated relative path, skipping hidden files and
+// directories.
+func scanVaultNotes(vaultPath string) (map[string]fs.FileInfo, error) {
+	files := make(map[string]fs.FileInfo)
+	err := filepath.WalkDir(vaultPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return NewError(ErrCodeVaultAccess, VaultAccessError)
+		}
+		if skip, err := skipHidden(vaultPath, p, d); skip {
+			return err
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(vaultPath, p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return NewError(ErrCodeVaultRead, VaultReadError)
+		}
+		files[normalizePathSeparators(relPath)] = info
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func buildIndexEntry(vaultPath, relPath string, info fs.FileInfo) (*IndexEntry, error) {
+	entry := &IndexEntry{
+		Path:    relPath,
+		ModTime: info.ModTime().UnixNano(),
+		Size:    info.Size(),
+	}
+	if info.Size() > maxFileSizeBytes {
+		return entry, nil
+	}
+
+	data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(relPath)))
+	if err != nil {
+		return nil, NewError(ErrCodeVaultRead, VaultReadError)
+	}
+	content := string(data)
+
+	entry.Headings = ExtractHeadings(content)
+	entry.Links = ExtractLinks(content)
+	if frontmatter.HasFrontmatter(content) {
+		if fm, _, err := frontmatter.Parse(content); err == nil && len(fm) > 0 {
+			entry.Frontmatter = JSONSafeMap(fm)
+			entry.Tags = FrontmatterTags(fm)
+		}
+	}
+	entry.Tags = append(entry.Tags, ExtractInlineTags(content)...)
+	return entry, nil
+}
+
+// JSONSafeMap converts YAML-decoded values into a form encoding/json accepts,
+// stringifying non-string map keys.
+func JSONSafeMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = jsonSafeValue(v)
+	}
+	return out
+}
+
+func jsonSafeValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return JSONSafeMap(val)
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[fmt.Sprint(k)] = jsonSafeValue(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = jsonSafeValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,323 @@
+package obsidian_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func writeVaultFiles(t *testing.T, vaultPath string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(vaultPath, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRebuildIndex(t *testing.T) {
+	t.Run("Indexes notes with metadata", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"note.md":           "---\ntags: [fm]\nstatus: draft\n---\n# Title\nLinks to [[other]] #inline",
+			"folder/other.md":   "No links",
+			"image.png":         "binary",
+			".trash/deleted.md": "ignored",
+		})
+
+		status, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, status.Notes)
+		assert.Equal(t, 2, status.Added)
+
+		idx, err := obsidian.LoadIndex(vaultPath)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"folder/other.md", "note.md"}, idx.NotePaths())
+
+		entry := idx.Notes["note.md"]
+		assert.Equal(t, []obsidian.Heading{{Level: 1, Text: "Title", Line: 5}}, entry.Headings)
+		assert.Equal(t, []string{"other"}, entry.Links)
+		assert.Equal(t, []string{"fm", "inline"}, entry.Tags)
+		assert.Equal(t, "draft", entry.Frontmatter["status"])
+	})
+
+	t.Run("Error on missing vault", func(t *testing.T) {
+		_, err := obsidian.RebuildIndex(filepath.Join(t.TempDir(), "missing"))
+		assert.Equal(t, obsidian.VaultAccessError, err.Error())
+	})
+}
+
+func TestVaultIndexUpdate(t *testing.T) {
+	t.Run("Only re-reads changed notes", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"keep.md":   "unchanged",
+			"change.md": "before",
+			"remove.md": "gone soon",
+		})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+
+		future := time.Now().Add(time.Hour)
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"change.md": "after [[keep]]",
+			"new.md":    "brand new",
+		})
+		assert.NoError(t, os.Chtimes(filepath.Join(vaultPath, "change.md"), future, future))
+		assert.NoError(t, os.Remove(filepath.Join(vaultPath, "remove.md")))
+
+		idx, err := obsidian.LoadIndex(vaultPath)
+		assert.NoError(t, err)
+		status, err := idx.Update(vaultPath)
+		assert.NoError(t, err)
+
+		assert.Equal(t, 1, status.Added)
+		assert.Equal(t, 1, status.Updated)
+		assert.Equal(t, 1, status.Removed)
+		assert.Equal(t, 3, status.Notes)
+		assert.Equal(t, []string{"keep"}, idx.Notes["change.md"].Links)
+	})
+}
+
+func TestVaultIndexLookup(t *testing.T) {
+	idx := obsidian.NewVaultIndex()
+	idx.Notes["a/note.md"] = &obsidian.IndexEntry{Path: "a/note.md"}
+	idx.Notes["b/note.md"] = &obsidian.IndexEntry{Path: "b/note.md"}
+	idx.Notes["top.md"] = &obsidian.IndexEntry{Path: "top.md"}
+	idx.Notes["a b/dup.md"] = &obsidian.IndexEntry{Path: "a b/dup.md"}
+	idx.Notes["a/dup.md"] = &obsidian.IndexEntry{Path: "a/dup.md"}
+
+	tests := []struct {
+		testName string
+		noteName string
+		expected string
+		found    bool
+	}{
+		{"Exact path", "b/note", "b/note.md", true},
+		{"Basename falls back to first match", "note.md", "a/note.md", true},
+		{"Matches are taken in walk order", "dup", "a/dup.md", true},
+		{"Top-level note", "top", "top.md", true},
+		{"Missing note", "missing", "", false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			relPath, found := idx.Lookup(test.noteName)
+			assert.Equal(t, test.found, found)
+			assert.Equal(t, test.expected, relPath)
+		})
+	}
+}
+
+func TestVaultIndexLinkingNotes(t *testing.T) {
+	idx := obsidian.NewVaultIndex()
+	idx.Notes["wiki.md"] = &obsidian.IndexEntry{Links: []string{"Target"}}
+	idx.Notes["path.md"] = &obsidian.IndexEntry{Links: []string{"folder/target"}}
+	idx.Notes["md.md"] = &obsidian.IndexEntry{Links: []string{"./folder/target.md"}}
+	idx.Notes["other.md"] = &obsidian.IndexEntry{Links: []string{"folder/targets"}}
+	idx.Notes["none.md"] = &obsidian.IndexEntry{}
+
+	assert.Equal(t, []string{"md.md", "path.md", "wiki.md"}, idx.LinkingNotes("folder/target.md"))
+}
+
+func TestReadIndexStatus(t *testing.T) {
+	t.Run("Reports missing index", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		status, err := obsidian.ReadIndexStatus(vaultPath)
+		assert.NoError(t, err)
+		assert.False(t, status.Exists)
+		assert.Equal(t, obsidian.IndexFilePath(vaultPath), status.Path)
+	})
+
+	t.Run("Reports pending changes without updating", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"one.md": "1"})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		writeVaultFiles(t, vaultPath, map[string]string{"two.md": "2"})
+
+		status, err := obsidian.ReadIndexStatus(vaultPath)
+		assert.NoError(t, err)
+		assert.True(t, status.Exists)
+		assert.Equal(t, 1, status.Notes)
+		assert.Equal(t, 1, status.Added)
+
+		status, err = obsidian.ReadIndexStatus(vaultPath)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, status.Added, "status should not modify the index")
+	})
+
+	t.Run("Error on corrupt index", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{".obsidian/" + obsidian.IndexFileName: "not json"})
+		_, err := obsidian.ReadIndexStatus(vaultPath)
+		assert.Equal(t, obsidian.IndexReadError, err.Error())
+	})
+
+	t.Run("Error on outdated index version", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		data, _ := json.Marshal(map[string]interface{}{"version": 0, "notes": map[string]interface{}{}})
+		writeVaultFiles(t, vaultPath, map[string]string{".obsidian/" + obsidian.IndexFileName: string(data)})
+		_, err := obsidian.LoadIndex(vaultPath)
+		assert.Equal(t, obsidian.IndexReadError, err.Error())
+	})
+}
+
+func TestNoteWithIndex(t *testing.T) {
+	t.Run("GetContents resolves notes through the index", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"deep/dir/note.md": "indexed content"})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+
+		note := obsidian.Note{}
+		contents, err := note.GetContents(vaultPath, "note")
+		assert.NoError(t, err)
+		assert.Equal(t, "indexed content", contents)
+	})
+
+	t.Run("GetContents finds notes added since the index was saved", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		writeVaultFiles(t, vaultPath, map[string]string{"later.md": "added later"})
+
+		note := obsidian.Note{}
+		contents, err := note.GetContents(vaultPath, "later")
+		assert.NoError(t, err)
+		assert.Equal(t, "added later", contents)
+	})
+
+	t.Run("Ambiguous names resolve the same with and without an index", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a b/dup.md": "a b",
+			"a/dup.md":   "a",
+			"a.md":       "",
+		})
+		note := obsidian.Note{}
+
+		walked, err := note.GetContents(vaultPath, "dup")
+		assert.NoError(t, err)
+		_, err = obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		indexed, err := note.GetContents(vaultPath, "dup")
+		assert.NoError(t, err)
+		assert.Equal(t, "a", walked)
+		assert.Equal(t, walked, indexed)
+
+		writeVaultFiles(t, vaultPath, map[string]string{"dup.md": "root"})
+		contents, err := note.GetContents(vaultPath, "dup")
+		assert.NoError(t, err)
+		assert.Equal(t, "root", contents)
+	})
+
+	t.Run("Commands refresh the index without saving it", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": ""})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		saved, err := os.ReadFile(obsidian.IndexFilePath(vaultPath))
+		assert.NoError(t, err)
+		writeVaultFiles(t, vaultPath, map[string]string{"b.md": ""})
+
+		note := obsidian.Note{}
+		notes, err := note.GetNotesList(vaultPath)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md", "b.md"}, notes)
+		current, err := os.ReadFile(obsidian.IndexFilePath(vaultPath))
+		assert.NoError(t, err)
+		assert.Equal(t, string(saved), string(current))
+
+		status, err := obsidian.UpdateIndex(vaultPath)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, status.Added)
+		status, err = obsidian.ReadIndexStatus(vaultPath)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, status.Notes)
+		assert.Zero(t, status.Added+status.Updated+status.Removed)
+	})
+
+	t.Run("GetNotesList picks up new notes", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": ""})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		writeVaultFiles(t, vaultPath, map[string]string{"sub/b.md": ""})
+
+		note := obsidian.Note{}
+		notes, err := note.GetNotesList(vaultPath)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md", filepath.Join("sub", "b.md")}, notes)
+	})
+
+	t.Run("Hidden notes are skipped with and without an index", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a.md":              "",
+			".trash/deleted.md": "",
+			".draft.md":         "",
+		})
+		note := obsidian.Note{}
+
+		walked, err := note.GetNotesList(vaultPath)
+		assert.NoError(t, err)
+		_, err = note.GetContents(vaultPath, "deleted")
+		assert.Equal(t, obsidian.ErrCodeNoteNotFound, obsidian.ErrorCode(err))
+
+		_, err = obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		indexed, err := note.GetNotesList(vaultPath)
+		assert.NoError(t, err)
+		_, err = note.GetContents(vaultPath, "deleted")
+		assert.Equal(t, obsidian.ErrCodeNoteNotFound, obsidian.ErrorCode(err))
+
+		assert.Equal(t, []string{"a.md"}, walked)
+		assert.Equal(t, walked, indexed)
+	})
+
+	t.Run("UpdateLinks and FindBacklinks use indexed links", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"target.md": "target",
+			"linker.md": "See [[target]] here",
+			"other.md":  "No link to target",
+		})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+
+		note := obsidian.Note{}
+		backlinks, err := note.FindBacklinks(vaultPath, "target")
+		assert.NoError(t, err)
+		assert.Len(t, backlinks, 1)
+		assert.Equal(t, "linker.md", backlinks[0].FilePath)
+
+		err = note.UpdateLinks(vaultPath, "target", "renamed")
+		assert.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "linker.md"))
+		assert.Equal(t, "See [[renamed]] here", string(content))
+		content, _ = os.ReadFile(filepath.Join(vaultPath, "other.md"))
+		assert.Equal(t, "No link to target", string(content))
+	})
+
+	t.Run("SearchNotesWithSnippets searches indexed notes", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": "needle here", "b.md": "nothing"})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+
+		note := obsidian.Note{}
+		matches, err := note.SearchNotesWithSnippets(vaultPath, "needle")
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{{FilePath: "a.md", LineNumber: 1, MatchLine: "needle here", MatchStart: 0, MatchEnd: 6}}, matches)
+	})
+}
@@ -0,0 +1,529 @@
+package obsidian
+
+import (
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Kinds of links found by ParseLinks.
+const (
+	LinkWiki      = "wikilink"  // [[note]]
+	LinkEmbed     = "embed"     // ![[note]]
+	LinkMarkdown  = "markdown"  // [text](note.md) or ![alt](image.png)
+	LinkReference = "reference" // [label]: note.md
+)
+
+var (
+	wikiLinkRegex      = regexp.MustCompile(`!?\[\[([^\[\]\n]*)\]\]`)
+	markdownLinkRegex  = regexp.MustCompile(`\]\([ \t]*(?:<([^<>\n]*)>|((?:[^\s()<>]|\([^\s()<>]*\))+))(?:[ \t]+(?:"[^"\n]*"|'[^'\n]*'|\([^()\n]*\)))?[ \t]*\)`)
+	referenceLinkRegex = regexp.MustCompile(`^ {0,3}\[([^\]\n]+)\]:[ \t]*(?:<([^<>\n]*)>|(\S+))`)
+)
+
+// Link is a link from a note to a note or file.
+type Link struct {
+	Kind string
+	// Target is the linked path without any "#heading" or "#^block"
+	// fragment, alias or title. Markdown link targets are URL-decoded.
+	Target string
+	// Fragment is the "#heading" or "#^block" part of the link, if any.
+	Fragment string
+	// Start and End are the byte range of the target as written in the
+	// note, so that replacing content[Start:End] retargets the link.
+	Start int
+	End   int
+	// Angled is set for Markdown links written as [text](<path>).
+	Angled bool
+}
+
+// Raw returns the target as written in content.
+func (l Link) Raw(content string) string {
+	return content[l.Start:l.End]
+}
+
+// ParseLinks returns the wikilinks, embeds, Markdown links and reference
+// link definitions of a note, in order of appearance. Links in frontmatter,
+// code blocks and inline code, links to external URLs and links within the
+// note itself (such as "[[#Heading]]") are left out.
+func ParseLinks(content string) []Link {
+	var links []Link
+	for _, line := range splitMarkdownLines(content) {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		text := stripInlineCode(line.Text)
+		var lineLinks []Link
+
+		for _, m := range wikiLinkRegex.FindAllStringSubmatchIndex(text, -1) {
+			kind := LinkWiki
+			if text[m[0]] == '!' {
+				kind = LinkEmbed
+			}
+			inner := text[m[2]:m[3]]
+			end := len(inner)
+			if i := strings.IndexByte(inner, '|'); i != -1 {
+				end = i
+			}
+			target, fragment := inner[:end], ""
+			if i := strings.IndexByte(target, '#'); i != -1 {
+				target, fragment = target[:i], target[i:]
+			}
+			if strings.TrimSpace(target) == "" {
+				continue
+			}
+			lineLinks = append(lineLinks, Link{
+				Kind:     kind,
+				Target:   target,
+				Fragment: fragment,
+				Start:    line.Offset + m[2],
+				End:      line.Offset + m[2] + len(target),
+			})
+		}
+
+		for _, m := range markdownLinkRegex.FindAllStringSubmatchIndex(text, -1) {
+			if link, ok := markdownLink(LinkMarkdown, text, m[2:6], line.Offset); ok {
+				lineLinks = append(lineLinks, link)
+			}
+		}
+
+		if m := referenceLinkRegex.FindStringSubmatchIndex(text); m != nil && !strings.HasPrefix(text[m[2]:m[3]], "^") {
+			if link, ok := markdownLink(LinkReference, text, m[4:8], line.Offset); ok {
+				lineLinks = append(lineLinks, link)
+			}
+		}
+
+		sort.SliceStable(lineLinks, func(i, j int) bool { return lineLinks[i].Start < lineLinks[j].Start })
+		links = append(links, lineLinks...)
+	}
+	return links
+}
+
+// markdownLink builds the link whose destination is at one of two submatch
+// ranges of text: loc[0:2] when written in angle brackets, loc[2:4]
+// otherwise.
+func markdownLink(kind, text string, loc []int, offset int) (Link, bool) {
+	link := Link{Kind: kind}
+	start, end := loc[2], loc[3]
+	if loc[0] >= 0 {
+		start, end = loc[0], loc[1]
+		link.Angled = true
+	}
+
+	raw := text[start:end]
+	if strings.Contains(raw, "://") || strings.HasPrefix(raw, "mailto:") {
+		return Link{}, false
+	}
+	if i := strings.IndexByte(raw, '#'); i != -1 {
+		raw, link.Fragment = raw[:i], raw[i:]
+	}
+	if raw == "" {
+		return Link{}, false
+	}
+
+	link.Target = raw
+	if decoded, err := url.PathUnescape(raw); err == nil {
+		link.Target = decoded
+	}
+	link.Start = offset + start
+	link.End = offset + start + len(raw)
+	return link, true
+}
+
+// vaultFiles is the set of files in a vault, used to resolve links the way
+// Obsidian does. Paths are slash-separated and relative to the vault.
+type vaultFiles struct {
+	paths  map[string]string   // lowercase path -> path
+	byName map[string][]string // lowercase file name -> paths
+}
+
+func newVaultFiles(paths []string) *vaultFiles {
+	files := &vaultFiles{paths: make(map[string]string), byName: make(map[string][]string)}
+	for _, p := range paths {
+		files.add(p)
+	}
+	return files
+}
+
+func (f *vaultFiles) add(p string) {
+	key := strings.ToLower(p)
+	if _, ok := f.paths[key]; ok {
+		return
+	}
+	f.paths[key] = p
+	name := path.Base(key)
+	f.byName[name] = append(f.byName[name], p)
+}
+
+func (f *vaultFiles) remove(p string) {
+	key := strings.ToLower(p)
+	if _, ok := f.paths[key]; !ok {
+		return
+	}
+	delete(f.paths, key)
+	name := path.Base(key)
+	others := f.byName[name][:0]
+	for _, q := range f.byName[name] {
+		if strings.ToLower(q) != key {
+			others = append(others, q)
+		}
+	}
+	f.byName[name] = others
+}
+
+// lookup returns the file at p, trying p as a note name without ".md" too.
+func (f *vaultFiles) lookup(p string) (string, bool) {
+	if found, ok := f.paths[strings.ToLower(p)]; ok {
+		return found, true
+	}
+	found, ok := f.paths[strings.ToLower(p)+".md"]
+	return found, ok
+}
+
+// resolve returns the file a link in the note at source points to. Markdown
+// links are read relative to the linking note first, or to the vault when
+// they start with "/". Otherwise, like wikilinks, the target is a path from
+// the vault root, a path relative to the linking note, or the end of a path,
+// such as a file name, in which case a file next to the linking note wins
+// over the file with the shortest path.
+func (f *vaultFiles) resolve(link Link, source string) (string, bool) {
+	target := link.Target
+	dir := path.Dir(source)
+
+	if link.Kind == LinkMarkdown || link.Kind == LinkReference {
+		if strings.HasPrefix(target, "/") {
+			return f.lookup(path.Clean(strings.TrimPrefix(target, "/")))
+		}
+		if found, ok := f.lookup(path.Join(dir, target)); ok {
+			return found, true
+		}
+	}
+
+	target = strings.TrimPrefix(target, "/")
+	if found, ok := f.lookup(path.Clean(target)); ok {
+		return found, true
+	}
+	if found, ok := f.lookup(path.Join(dir, target)); ok {
+		return found, true
+	}
+
+	suffix := strings.ToLower(path.Clean(target))
+	var candidates []string
+	for _, name := range []string{path.Base(suffix), path.Base(suffix) + ".md"} {
+		for _, p := range f.byName[name] {
+			key := strings.ToLower(p)
+			if key == suffix || key == suffix+".md" || strings.HasSuffix(key, "/"+suffix) || strings.HasSuffix(key, "/"+suffix+".md") {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return "", false
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		iLocal, jLocal := path.Dir(candidates[i]) == dir, path.Dir(candidates[j]) == dir
+		if iLocal != jLocal {
+			return iLocal
+		}
+		if len(candidates[i]) != len(candidates[j]) {
+			return len(candidates[i]) < len(candidates[j])
+		}
+		return candidates[i] < candidates[j]
+	})
+	return candidates[0], true
+}
+
+// retarget returns the text to write in place of link's target in the note
+// at source so that it points at dest. With a newLinkFormat setting the path
+// is written in that format; otherwise the style of the original is kept:
+// relative to the note when relative is set, from the vault root, or a file
+// name as long as that is unambiguous. Either way the ".md" extension is
+// kept only when the original had it, and Markdown links stay URL-encoded or
+// in angle brackets. The useMarkdownLinks setting is deliberately not
+// applied: as in Obsidian, a rewritten link stays a wikilink or a Markdown
+// link, and only links written from scratch, see NoteLink, follow it.
+func (f *vaultFiles) retarget(link Link, raw, source, dest, format string, relative bool) string {
+	var target string
+	switch {
+	case format != "":
+		target = f.linkPath(source, dest, format)
+	case strings.HasPrefix(link.Target, "/"):
+		target = "/" + dest
+	case relative:
+		target = relativeLinkPath(path.Dir(source), dest)
+		if strings.HasPrefix(link.Target, "./") && !strings.HasPrefix(target, "../") {
+			target = "./" + target
+		}
+	case !strings.Contains(link.Target, "/"):
+		target = dest
+		name := path.Base(dest)
+		if found, ok := f.resolve(Link{Kind: LinkWiki, Target: name}, source); ok && found == dest {
+			target = name
+		}
+	default:
+		target = dest
+	}
+
+	if strings.HasSuffix(dest, ".md") && !strings.HasSuffix(strings.ToLower(link.Target), ".md") {
+		target = RemoveMdSuffix(target)
+	}
+	isMarkdown := link.Kind == LinkMarkdown || link.Kind == LinkReference
+	if isMarkdown && !link.Angled && (strings.Contains(raw, "%") || strings.ContainsAny(target, " %<>")) {
+		target = escapeLinkPath(target)
+	}
+	return target
+}
+
+// linkPath returns the path to write in a link from the note at source to
+// the file at dest for the newLinkFormat setting format: the path from the
+// vault root for LinkFormatAbsolute, the path from the note's folder for
+// LinkFormatRelative, and otherwise the shortest end of the path that no
+// other file's path ends with.
+func (f *vaultFiles) linkPath(source, dest, format string) string {
+	switch format {
+	case LinkFormatAbsolute:
+		return dest
+	case LinkFormatRelative:
+		return relativeLinkPath(path.Dir(source), dest)
+	}
+
+	parts := strings.Split(dest, "/")
+	for i := len(parts) - 1; i > 0; i-- {
+		suffix := strings.ToLower(strings.Join(parts[i:], "/"))
+		unique := true
+		for _, p := range f.byName[strings.ToLower(parts[len(parts)-1])] {
+			key := strings.ToLower(p)
+			if p != dest && (key == suffix || strings.HasSuffix(key, "/"+suffix)) {
+				unique = false
+				break
+			}
+		}
+		if unique {
+			return strings.Join(parts[i:], "/")
+		}
+	}
+	return dest
+}
+
+// escapeLinkPath URL-encodes a path for a Markdown link, as Obsidian does
+// for spaces and other special characters.
+func escapeLinkPath(p string) string {
+	return (&url.URL{Path: p}).EscapedPath()
+}
+
+// NoteLink returns a link from the note at source to target, a note or file
+// name or path resolved like a wikilink, with an optional "#heading" or
+// "#^block" fragment. It is written the way the vault is set up to write new
+// links: as a Markdown link when useMarkdownLinks is on and a wikilink
+// otherwise, with the path in the newLinkFormat format (shortest when not
+// set). Source is a vault-relative path; "" stands for the vault root.
+func NoteLink(vaultPath, source, target, fragment string) (string, error) {
+	paths, err := listVaultFiles(vaultPath)
+	if err != nil {
+		return "", err
+	}
+	files := newVaultFiles(paths)
+	dest, ok := files.resolve(Link{Kind: LinkWiki, Target: normalizePathSeparators(target)}, source)
+	if !ok {
+		return "", NewError(ErrCodeNoteNotFound, NoteDoesNotExistError)
+	}
+
+	config := ReadObsidianAppConfig(vaultPath)
+	linkPath := files.linkPath(source, dest, config.NewLinkFormat)
+	if config.UseMarkdownLinks {
+		text := RemoveMdSuffix(path.Base(dest))
+		return "[" + text + "](" + escapeLinkPath(linkPath) + strings.ReplaceAll(fragment, " ", "%20") + ")", nil
+	}
+	if strings.HasSuffix(dest, ".md") {
+		linkPath = RemoveMdSuffix(linkPath)
+	}
+	return "[[" + linkPath + fragment + "]]", nil
+}
+
+// relativeLinkPath returns the slash-separated path to target from dir.
+func relativeLinkPath(dir, target string) string {
+	rel, err := filepath.Rel(filepath.FromSlash(dir), filepath.FromSlash(target))
+	if err != nil {
+		return target
+	}
+	return filepath.ToSlash(rel)
+}
+
+// listVaultFiles returns the slash-separated paths of all files in the
+// vault, skipping hidden files and directories.
+func listVaultFiles(vaultPath string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(vaultPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return NewError(ErrCodeVaultAccess, VaultAccessError)
+		}
+		if skip, err := skipHidden(vaultPath, p, d); skip {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(vaultPath, p)
+		if err != nil {
+			return NewError(ErrCodeVaultAccess, VaultAccessError)
+		}
+		paths = append(paths, filepath.ToSlash(relPath))
+		return nil
+	})
+	return paths, err
+}
+
+// linkMove holds what is needed to update links after files were moved.
+type linkMove struct {
+	// before and after are the vault's files before and after the move.
+	before *vaultFiles
+	after  *vaultFiles
+	// moved maps old vault-relative paths to new ones.
+	moved map[string]string
+	// format is the vault's newLinkFormat setting, if any.
+	format string
+}
+
+// updateMovedLinks rewrites the links in the vault's notes so they keep
+// pointing at the same files after the moves in moved, which maps old
+// vault-relative paths to new ones; the files must already be at their new
+// paths. Links are resolved as they were before the move. Links in a moved
+// note that are relative to it are rewritten too. When linking is not nil,
+// only the moved notes and the notes it reports as possibly linking to them
+// are read.
+func updateMovedLinks(vaultPath string, moved map[string]string, linking func(note string) bool) error {
+	paths, err := listVaultFiles(vaultPath)
+	if err != nil {
+		return err
+	}
+
+	move := linkMove{
+		before: newVaultFiles(paths),
+		after:  newVaultFiles(paths),
+		moved:  moved,
+		format: ReadObsidianAppConfig(vaultPath).NewLinkFormat,
+	}
+	// All removals come before the additions so that chained moves, where
+	// one file takes the path another left, leave both sets complete.
+	previous := make(map[string]string, len(moved))
+	for oldPath, newPath := range moved {
+		move.before.remove(newPath)
+		move.after.remove(oldPath)
+		previous[newPath] = oldPath
+	}
+	for oldPath, newPath := range moved {
+		move.before.add(oldPath)
+		move.after.add(newPath)
+	}
+
+	for _, note := range paths {
+		if !strings.HasSuffix(note, ".md") {
+			continue
+		}
+		source, noteMoved := previous[note]
+		if !noteMoved {
+			if linking != nil && !linking(note) {
+				continue
+			}
+			source = note
+		}
+		if err := rewriteNoteLinks(vaultPath, note, func(content string) string {
+			return move.retargetLinks(content, source, note)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// linkingFilter returns the filter updateMovedLinks uses to skip notes that
+// cannot link to the files in moved, or nil when the vault has no index.
+func linkingFilter(vaultPath string, moved map[string]string) func(note string) bool {
+	idx := freshIndex(vaultPath)
+	if idx == nil {
+		return nil
+	}
+	oldPaths := make([]string, 0, len(moved))
+	for oldPath := range moved {
+		oldPaths = append(oldPaths, oldPath)
+	}
+	linking := make(map[string]bool)
+	for _, relPath := range idx.linkingNotes(oldPaths) {
+		linking[relPath] = true
+	}
+	return func(note string) bool { return linking[note] }
+}
+
+// retargetLinks returns content, a note that was at source and is now at
+// note, with each link updated for the moved files.
+func (m *linkMove) retargetLinks(content, source, note string) string {
+	var sb strings.Builder
+	last := 0
+	for _, link := range ParseLinks(content) {
+		dest, ok := m.before.resolve(link, source)
+		if !ok {
+			continue
+		}
+		newDest, destMoved := m.moved[dest]
+		if !destMoved {
+			if source == note {
+				continue
+			}
+			// The note itself moved: only links that no longer reach the
+			// same file change.
+			if found, ok := m.after.resolve(link, note); ok && found == dest {
+				continue
+			}
+			newDest = dest
+		}
+
+		relative := strings.HasPrefix(link.Target, "./") || strings.HasPrefix(link.Target, "../")
+		if (link.Kind == LinkMarkdown || link.Kind == LinkReference) && !strings.HasPrefix(link.Target, "/") {
+			if found, ok := m.before.lookup(path.Join(path.Dir(source), link.Target)); ok && found == dest {
+				relative = true
+			}
+		}
+
+		raw := link.Raw(content)
+		target := m.after.retarget(link, raw, note, newDest, m.format, relative)
+		if target == raw {
+			continue
+		}
+		sb.WriteString(content[last:link.Start])
+		sb.WriteString(target)
+		last = link.End
+	}
+	if last == 0 {
+		return content
+	}
+	sb.WriteString(content[last:])
+	return sb.String()
+}
+
+// rewriteNoteLinks replaces the content of the note at the vault-relative
+// path note with update(content), leaving the file untouched (and its
+// timestamp preserved) when nothing changes.
+func rewriteNoteLinks(vaultPath, note string, update func(string) string) error {
+	p := filepath.Join(vaultPath, filepath.FromSlash(note))
+	info, err := os.Stat(p)
+	if err != nil {
+		return NewError(ErrCodeVaultAccess, VaultAccessError)
+	}
+	original, err := os.ReadFile(p)
+	if err != nil {
+		return NewError(ErrCodeVaultRead, VaultReadError)
+	}
+
+	updated := update(string(original))
+	if updated == string(original) {
+		return nil
+	}
+	if err := os.WriteFile(p, []byte(updated), info.Mode()); err != nil {
+		return NewError(ErrCodeVaultWrite, VaultWriteError)
+	}
+	return nil
+}
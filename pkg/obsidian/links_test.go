@@ -0,0 +1,241 @@
+package obsidian_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLinks(t *testing.T) {
+	t.Run("Finds every kind of link", func(t *testing.T) {
+		content := "[[Note A#Part|alias]] ![[image.png|100x200]] [md](../other/My%20Note.md#^abc) [angled](<with space.md> \"title\")\n[ref]: notes/ref.md\n"
+		links := obsidian.ParseLinks(content)
+
+		var kinds, targets, fragments, raws []string
+		for _, link := range links {
+			kinds = append(kinds, link.Kind)
+			targets = append(targets, link.Target)
+			fragments = append(fragments, link.Fragment)
+			raws = append(raws, link.Raw(content))
+		}
+		assert.Equal(t, []string{obsidian.LinkWiki, obsidian.LinkEmbed, obsidian.LinkMarkdown, obsidian.LinkMarkdown, obsidian.LinkReference}, kinds)
+		assert.Equal(t, []string{"Note A", "image.png", "../other/My Note.md", "with space.md", "notes/ref.md"}, targets)
+		assert.Equal(t, []string{"#Part", "", "#^abc", "", ""}, fragments)
+		assert.Equal(t, []string{"Note A", "image.png", "../other/My%20Note.md", "with space.md", "notes/ref.md"}, raws)
+		assert.True(t, links[3].Angled)
+	})
+
+	t.Run("Skips code, frontmatter, external and same-note links", func(t *testing.T) {
+		content := "---\nup: \"[[Parent]]\"\n---\n```\n[[InCode]]\n```\n`[[Inline]]` [site](https://example.com) [mail](mailto:a@b.c) [[#Heading]] [top](#top) [^1]: footnote\n"
+		assert.Empty(t, obsidian.ParseLinks(content))
+	})
+}
+
+func TestUpdateLinks_ResolvesLinks(t *testing.T) {
+	t.Run("Rewrites only links to the moved note, in their own style", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"projects/alpha.md": "",
+			"archive/plan.md":   "other note with the same name",
+			"projects/notes/log.md": "[[plan]] [up](../plan.md) [enc](../My%20Plan.md) [angled](<../My Plan.md>)\n" +
+				"![[projects/My Plan|300]]\n[ref]: ../My%20Plan.md#^id\n" +
+				"```\n[[My Plan]]\n```\n",
+		})
+		writeVaultFiles(t, vaultPath, map[string]string{"done/My Plan.md": "moved"})
+
+		note := obsidian.Note{}
+		err := note.UpdateLinks(vaultPath, "projects/My Plan.md", "done/My Plan.md")
+		assert.NoError(t, err)
+
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "projects", "notes", "log.md"))
+		assert.Equal(t, "[[plan]] [up](../plan.md) [enc](../../done/My%20Plan.md) [angled](<../../done/My Plan.md>)\n"+
+			"![[done/My Plan|300]]\n[ref]: ../../done/My%20Plan.md#^id\n"+
+			"```\n[[My Plan]]\n```\n", string(content))
+	})
+
+	t.Run("File names that become ambiguous get a path", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a/idea.md":   "",
+			"b/draft.md":  "moved",
+			"linker.md":   "[[draft]] and [[Draft|alias]]",
+			"b/unique.md": "",
+		})
+
+		note := obsidian.Note{}
+		assert.NoError(t, os.Rename(filepath.Join(vaultPath, "b", "draft.md"), filepath.Join(vaultPath, "b", "idea.md")))
+		err := note.UpdateLinks(vaultPath, "b/draft", "b/idea")
+		assert.NoError(t, err)
+
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "linker.md"))
+		assert.Equal(t, "[[b/idea]] and [[b/idea|alias]]", string(content))
+	})
+
+	t.Run("Relative links in the moved note follow it", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a/sibling.md":   "",
+			"b/c/moved.md":   "[s](../../a/sibling.md) [[sibling]] [self](moved.md) [[./gone]]",
+			"a/unrelated.md": "",
+		})
+
+		note := obsidian.Note{}
+		assert.NoError(t, os.Rename(filepath.Join(vaultPath, "b", "c", "moved.md"), filepath.Join(vaultPath, "moved.md")))
+		err := note.UpdateLinks(vaultPath, "b/c/moved", "moved")
+		assert.NoError(t, err)
+
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "moved.md"))
+		assert.Equal(t, "[s](a/sibling.md) [[sibling]] [self](moved.md) [[./gone]]", string(content))
+	})
+}
+
+func TestUpdateLinks_LinkFormatSetting(t *testing.T) {
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{obsidian.LinkFormatShortest, "[[b/idea]] [md](b/idea.md)"},
+		{obsidian.LinkFormatRelative, "[[../b/idea]] [md](../b/idea.md)"},
+		{obsidian.LinkFormatAbsolute, "[[b/idea]] [md](b/idea.md)"},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			vaultPath := t.TempDir()
+			writeVaultFiles(t, vaultPath, map[string]string{
+				".obsidian/app.json": `{"newLinkFormat": "` + test.format + `"}`,
+				"a/idea.md":          "",
+				"b/idea.md":          "moved",
+				"c/linker.md":        "[[draft]] [md](../draft.md)",
+			})
+
+			note := obsidian.Note{}
+			err := note.UpdateLinks(vaultPath, "draft", "b/idea")
+			assert.NoError(t, err)
+
+			content, _ := os.ReadFile(filepath.Join(vaultPath, "c", "linker.md"))
+			assert.Equal(t, test.expected, string(content))
+		})
+	}
+}
+
+func TestNoteLink(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"a/x/idea.md":    "",
+		"b/x/idea.md":    "",
+		"b/y/My Plan.md": "",
+		"img/pic.png":    "",
+	})
+
+	tests := []struct {
+		name     string
+		config   string
+		source   string
+		target   string
+		fragment string
+		expected string
+	}{
+		{"Shortest unique path by default", "", "", "b/x/idea", "", "[[b/x/idea]]"},
+		{"File name when unique", "", "", "My Plan", "#^abc", "[[My Plan#^abc]]"},
+		{"Attachment keeps its extension", "", "", "pic.png", "", "[[pic.png]]"},
+		{"Relative", `{"newLinkFormat": "relative"}`, "b/x/note.md", "My Plan", "", "[[../y/My Plan]]"},
+		{"Absolute", `{"newLinkFormat": "absolute"}`, "", "My Plan", "", "[[b/y/My Plan]]"},
+		{"Markdown", `{"useMarkdownLinks": true}`, "", "My Plan", "#Some heading", "[My Plan](My%20Plan.md#Some%20heading)"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			writeVaultFiles(t, vaultPath, map[string]string{".obsidian/app.json": test.config})
+			link, err := obsidian.NoteLink(vaultPath, test.source, test.target, test.fragment)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, link)
+		})
+	}
+
+	t.Run("Missing note", func(t *testing.T) {
+		_, err := obsidian.NoteLink(vaultPath, "", "nope", "")
+		assert.Equal(t, obsidian.ErrCodeNoteNotFound, obsidian.ErrorCode(err))
+	})
+}
+
+func TestMoveFolder(t *testing.T) {
+	t.Run("Moves notes and attachments and updates links to them", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"projects/alpha.md":         "[[beta]] [img](assets/chart.png) [out](../home.md)",
+			"projects/sub/beta.md":      "![[chart.png]]",
+			"projects/assets/chart.png": "png",
+			"home.md":                   "[[projects/alpha]] [b](projects/sub/beta.md) ![[projects/assets/chart.png]] [[other]]",
+			"other.md":                  "",
+		})
+
+		note := obsidian.Note{}
+		moves, err := note.MoveFolder(vaultPath, "projects", "archive/2024/projects")
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.FileMove{
+			{From: "projects/alpha.md", To: "archive/2024/projects/alpha.md"},
+			{From: "projects/assets/chart.png", To: "archive/2024/projects/assets/chart.png"},
+			{From: "projects/sub/beta.md", To: "archive/2024/projects/sub/beta.md"},
+		}, moves)
+
+		assert.NoDirExists(t, filepath.Join(vaultPath, "projects"))
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "home.md"))
+		assert.Equal(t, "[[archive/2024/projects/alpha]] [b](archive/2024/projects/sub/beta.md) ![[archive/2024/projects/assets/chart.png]] [[other]]", string(content))
+		content, _ = os.ReadFile(filepath.Join(vaultPath, "archive", "2024", "projects", "alpha.md"))
+		assert.Equal(t, "[[beta]] [img](assets/chart.png) [out](../../../home.md)", string(content))
+		content, _ = os.ReadFile(filepath.Join(vaultPath, "archive", "2024", "projects", "sub", "beta.md"))
+		assert.Equal(t, "![[chart.png]]", string(content))
+	})
+
+	t.Run("Uses the vault index to find linking notes", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"inbox/idea.md": "",
+			"linker.md":     "[[inbox/idea]]",
+			"unrelated.md":  "[[elsewhere]]",
+		})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+
+		note := obsidian.Note{}
+		_, err = note.MoveFolder(vaultPath, "inbox", "done")
+		assert.NoError(t, err)
+
+		content, _ := os.ReadFile(filepath.Join(vaultPath, "linker.md"))
+		assert.Equal(t, "[[done/idea]]", string(content))
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a/note.md": "",
+			"b/note.md": "",
+			"file.md":   "",
+		})
+		note := obsidian.Note{}
+
+		tests := []struct {
+			name string
+			from string
+			to   string
+			code string
+		}{
+			{"Missing folder", "missing", "c", obsidian.ErrCodeNoteNotFound},
+			{"Not a folder", "file.md", "c", obsidian.ErrCodeNoteNotFound},
+			{"Destination exists", "a", "b", obsidian.ErrCodeInvalidArgument},
+			{"Into itself", "a", "a/inner", obsidian.ErrCodeInvalidArgument},
+			{"Vault folder", ".", "c", obsidian.ErrCodeInvalidArgument},
+			{"Outside the vault", "a", "../c", obsidian.ErrCodePathTraversal},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				_, err := note.MoveFolder(vaultPath, test.from, test.to)
+				assert.Error(t, err)
+				assert.Equal(t, test.code, obsidian.ErrorCode(err))
+			})
+		}
+		assert.FileExists(t, filepath.Join(vaultPath, "a", "note.md"))
+	})
+}
@@ -0,0 +1,184 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+	"gopkg.in/yaml.v3"
+)
+
+// LintSchema maps sets of notes to the frontmatter schema they must follow.
+//
+// A schema file is YAML or JSON:
+//
+//	rules:
+//	  - folder: Projects
+//	    required: [status, due]
+//	    properties:
+//	      status: {type: string, enum: [active, paused, done]}
+//	      due: {type: date}
+//
+// Each rule selects notes like NoteSelector, with glob, folder, tag and
+// where, and applies to every note when it has none of them. A file without
+// rules is itself a single rule, so a plain JSON Schema document with
+// "required" and "properties" applies to the whole vault.
+type LintSchema struct {
+	Rules []LintRule `yaml:"rules"`
+}
+
+// LintRule applies a frontmatter schema to the notes matching its selector.
+type LintRule struct {
+	Glob               string `yaml:"glob"`
+	Folder             string `yaml:"folder"`
+	Tag                string `yaml:"tag"`
+	Where              string `yaml:"where"`
+	frontmatter.Schema `yaml:",inline"`
+}
+
+// LintViolation is a note whose frontmatter breaks the lint schema.
+type LintViolation struct {
+	Path    string `json:"path"`
+	Key     string `json:"key,omitempty"`
+	Message string `json:"message"`
+}
+
+// LintReport is the outcome of linting a vault.
+type LintReport struct {
+	Checked    int             `json:"checked"`
+	Violations []LintViolation `json:"violations"`
+}
+
+// LoadLintSchema reads and checks a lint schema file.
+func LoadLintSchema(path string) (*LintSchema, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, NewError(ErrCodeInvalidSchema, fmt.Sprintf("cannot read schema file %s", path))
+	}
+	return ParseLintSchema(data)
+}
+
+// ParseLintSchema parses and checks a lint schema in YAML or JSON.
+func ParseLintSchema(data []byte) (*LintSchema, error) {
+	schema := &LintSchema{}
+	if err := yaml.Unmarshal(data, schema); err != nil {
+		return nil, WrapError(ErrCodeInvalidSchema, fmt.Sprintf("invalid schema: %s", err.Error()), err)
+	}
+	if len(schema.Rules) == 0 {
+		var rule LintRule
+		if err := yaml.Unmarshal(data, &rule); err != nil {
+			return nil, WrapError(ErrCodeInvalidSchema, fmt.Sprintf("invalid schema: %s", err.Error()), err)
+		}
+		schema.Rules = []LintRule{rule}
+	}
+
+	for i := range schema.Rules {
+		rule := &schema.Rules[i]
+		if err := rule.Schema.Compile(); err != nil {
+			return nil, WrapError(ErrCodeInvalidSchema, fmt.Sprintf("invalid schema: rule %d: %s", i+1, err.Error()), err)
+		}
+		if _, err := compileSelector(rule.selector()); err != nil {
+			return nil, WrapError(ErrCodeInvalidSchema, fmt.Sprintf("invalid schema: rule %d: %s", i+1, err.Error()), err)
+		}
+	}
+	return schema, nil
+}
+
+func (r *LintRule) selector() NoteSelector {
+	return NoteSelector{Glob: r.Glob, Folder: r.Folder, Tag: r.Tag, Where: r.Where}
+}
+
+// LintFrontmatter checks the frontmatter of every note against schema. When
+// paths is not empty only those vault-relative notes are checked. Notes whose
+// frontmatter is not valid YAML are reported as violations.
+func LintFrontmatter(vaultPath string, schema *LintSchema, paths []string, now time.Time) (LintReport, error) {
+	report := LintReport{Violations: []LintViolation{}}
+
+	matchers := make([]*noteMatcher, len(schema.Rules))
+	for i := range schema.Rules {
+		matcher, err := compileSelector(schema.Rules[i].selector())
+		if err != nil {
+			return report, err
+		}
+		matchers[i] = matcher
+	}
+
+	var only map[string]bool
+	if len(paths) > 0 {
+		only = make(map[string]bool, len(paths))
+		for _, path := range paths {
+			only[normalizePathSeparators(path)] = true
+		}
+	}
+
+	pages, err := queryPages(vaultPath)
+	if err != nil {
+		return report, err
+	}
+	for i := range pages {
+		page := &pages[i]
+		if only != nil && !only[page.Path] {
+			continue
+		}
+
+		var rules []*LintRule
+		for j, matcher := range matchers {
+			if matcher.matches(page, now) {
+				rules = append(rules, &schema.Rules[j])
+			}
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		report.Checked++
+
+		fm, err := readFrontmatter(vaultPath, page.Path)
+		if err != nil {
+			if ErrorCode(err) != ErrCodeInvalidFrontmatter {
+				return report, err
+			}
+			report.Violations = append(report.Violations, LintViolation{Path: page.Path, Message: err.Error()})
+			continue
+		}
+
+		var violations []frontmatter.Violation
+		seen := make(map[frontmatter.Violation]bool)
+		for _, rule := range rules {
+			for _, v := range rule.Validate(fm) {
+				if !seen[v] {
+					seen[v] = true
+					violations = append(violations, v)
+				}
+			}
+		}
+		sort.SliceStable(violations, func(a, b int) bool { return violations[a].Key < violations[b].Key })
+		for _, v := range violations {
+			report.Violations = append(report.Violations, LintViolation{Path: page.Path, Key: v.Key, Message: v.Message})
+		}
+	}
+	return report, nil
+}
+
+// readFrontmatter returns the frontmatter of a note, or an empty map when it
+// has none.
+func readFrontmatter(vaultPath, relPath string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(relPath)))
+	if err != nil {
+		return nil, NewError(ErrCodeVaultRead, VaultReadError)
+	}
+	content := string(data)
+	if !frontmatter.HasFrontmatter(content) {
+		return map[string]interface{}{}, nil
+	}
+	fm, _, err := frontmatter.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	if fm == nil {
+		fm = map[string]interface{}{}
+	}
+	return fm, nil
+}
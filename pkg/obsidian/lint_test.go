@@ -0,0 +1,99 @@
+package obsidian_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+const lintSchemaSource = `
+rules:
+  - folder: Projects
+    required: [status]
+    properties:
+      status: {type: string, enum: [active, done]}
+  - tag: book
+    required: [rating]
+    properties:
+      rating: {type: integer, minimum: 1, maximum: 5}
+`
+
+func TestLintFrontmatter(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"Projects/ok.md":       "---\nstatus: done\n---\n",
+		"Projects/bad.md":      "---\nstatus: open\n---\n",
+		"Projects/none.md":     "No frontmatter",
+		"Projects/broken.md":   "---\nstatus: [open\n---\n",
+		"Reading/dune.md":      "---\ntags: [book]\nrating: 9\n---\n",
+		"Reading/unrelated.md": "---\nrating: 9\n---\n",
+	})
+	schema, err := obsidian.ParseLintSchema([]byte(lintSchemaSource))
+	assert.NoError(t, err)
+
+	t.Run("Reports violations for matching notes", func(t *testing.T) {
+		report, err := obsidian.LintFrontmatter(vaultPath, schema, nil, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 5, report.Checked)
+		assert.Equal(t, []obsidian.LintViolation{
+			{Path: "Projects/bad.md", Key: "status", Message: "must be one of active, done"},
+			{Path: "Projects/broken.md", Message: "frontmatter contains invalid YAML"},
+			{Path: "Projects/none.md", Key: "status", Message: "missing required key"},
+			{Path: "Reading/dune.md", Key: "rating", Message: "must be at most 5"},
+		}, report.Violations)
+	})
+
+	t.Run("Checks only the given notes", func(t *testing.T) {
+		report, err := obsidian.LintFrontmatter(vaultPath, schema, []string{"Projects/ok.md", "Reading/unrelated.md"}, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, 1, report.Checked)
+		assert.Empty(t, report.Violations)
+	})
+}
+
+func TestParseLintSchema(t *testing.T) {
+	t.Run("A JSON Schema document applies to every note", func(t *testing.T) {
+		schema, err := obsidian.ParseLintSchema([]byte(`{"required": ["title"], "properties": {"title": {"type": "string"}}}`))
+		assert.NoError(t, err)
+
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": "Body", "b.md": "---\ntitle: B\n---\n"})
+		report, err := obsidian.LintFrontmatter(vaultPath, schema, nil, time.Now())
+
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.LintViolation{{Path: "a.md", Key: "title", Message: "missing required key"}}, report.Violations)
+	})
+
+	t.Run("Invalid schemas return an error", func(t *testing.T) {
+		for source, message := range map[string]string{
+			`rules: [{properties: {a: {type: bogus}}}]`: `invalid schema: rule 1: property "a": unknown type "bogus"`,
+			`rules: [{where: "status ="}]`:              `invalid schema: rule 1: expected a value at end of query`,
+		} {
+			_, err := obsidian.ParseLintSchema([]byte(source))
+			assert.EqualError(t, err, message, source)
+			assert.Equal(t, obsidian.ErrCodeInvalidSchema, obsidian.ErrorCode(err), source)
+		}
+
+		_, err := obsidian.ParseLintSchema([]byte(`rules: {}`))
+		assert.Equal(t, obsidian.ErrCodeInvalidSchema, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Missing schema file", func(t *testing.T) {
+		_, err := obsidian.LoadLintSchema(filepath.Join(t.TempDir(), "missing.yaml"))
+		assert.Equal(t, obsidian.ErrCodeInvalidSchema, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Schema file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "schema.yaml")
+		assert.NoError(t, os.WriteFile(path, []byte(lintSchemaSource), 0644))
+		schema, err := obsidian.LoadLintSchema(path)
+		assert.NoError(t, err)
+		assert.Len(t, schema.Rules, 2)
+	})
+}
@@ -1,7 +1,6 @@
 package obsidian
 
 import (
-	"errors"
 	"os"
 	"sort"
 	"strings"
@@ -19,15 +18,15 @@ func ListEntries(vaultPath, relativePath string) ([]string, error) {
 
 	info, err := os.Stat(targetPath)
 	if err != nil {
-		return nil, errors.New(VaultAccessError)
+		return nil, NewError(ErrCodeVaultAccess, VaultAccessError)
 	}
 	if !info.IsDir() {
-		return nil, errors.New(VaultAccessError)
+		return nil, NewError(ErrCodeVaultAccess, VaultAccessError)
 	}
 
 	entries, err := os.ReadDir(targetPath)
 	if err != nil {
-		return nil, errors.New(VaultReadError)
+		return nil, NewError(ErrCodeVaultRead, VaultReadError)
 	}
 
 	dirs := make([]string, 0, len(entries))
@@ -0,0 +1,180 @@
+package obsidian
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Heading is an ATX heading ("## Title") found in a note.
+type Heading struct {
+	Level int    `json:"level"`
+	Text  string `json:"text"`
+	Line  int    `json:"line"`
+}
+
+// markdownLine is a single line of note content along with its position and
+// whether it belongs to frontmatter or a fenced code block.
+type markdownLine struct {
+	Text          string
+	Number        int // 1-based line number
+	Offset        int // byte offset of the first character of the line
+	InCode        bool
+	InFrontmatter bool
+}
+
+var (
+	headingRegex    = regexp.MustCompile(`^(#{1,6})[ \t]+(.*?)[ \t]*#*[ \t]*$`)
+	inlineTagRegex  = regexp.MustCompile(`(^|\s)#([\p{L}\p{N}_/\-]+)`)
+	inlineCodeRegex = regexp.MustCompile("`+[^`]*`+")
+)
+
+// splitMarkdownLines splits content into lines, tracking byte offsets and
+// whether each line sits inside the leading frontmatter block or a fenced
+// (``` or ~~~) code block. Fence delimiter lines are marked as code.
+func splitMarkdownLines(content string) []markdownLine {
+	rawLines := strings.Split(content, "\n")
+	lines := make([]markdownLine, 0, len(rawLines))
+
+	offset := 0
+	inFrontmatter := false
+	var fence string
+	for i, raw := range rawLines {
+		line := markdownLine{Text: raw, Number: i + 1, Offset: offset}
+		offset += len(raw) + 1
+
+		trimmed := strings.TrimSpace(raw)
+		switch {
+		case i == 0 && trimmed == "---":
+			inFrontmatter = true
+			line.InFrontmatter = true
+		case inFrontmatter:
+			line.InFrontmatter = true
+			if trimmed == "---" || trimmed == "..." {
+				inFrontmatter = false
+			}
+		case fence != "":
+			line.InCode = true
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				fence = ""
+			}
+		default:
+			if f := fenceDelimiter(trimmed); f != "" {
+				fence = f
+				line.InCode = true
+			}
+		}
+
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// fenceDelimiter returns the opening fence (e.g. "```" or "~~~~") if line
+// starts a fenced code block, or "" otherwise.
+func fenceDelimiter(line string) string {
+	for _, c := range []byte{'`', '~'} {
+		n := 0
+		for n < len(line) && line[n] == c {
+			n++
+		}
+		if n >= 3 {
+			return line[:n]
+		}
+	}
+	return ""
+}
+
+// stripInlineCode blanks out inline code spans so their contents are not
+// mistaken for tags or links, while keeping byte offsets intact.
+func stripInlineCode(line string) string {
+	return inlineCodeRegex.ReplaceAllStringFunc(line, func(s string) string {
+		return strings.Repeat(" ", len(s))
+	})
+}
+
+// ExtractHeadings returns the ATX headings of a note, ignoring frontmatter and
+// fenced code blocks.
+func ExtractHeadings(content string) []Heading {
+	var headings []Heading
+	for _, line := range splitMarkdownLines(content) {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		m := headingRegex.FindStringSubmatch(line.Text)
+		if m == nil {
+			continue
+		}
+		headings = append(headings, Heading{Level: len(m[1]), Text: m[2], Line: line.Number})
+	}
+	return headings
+}
+
+// ExtractInlineTags returns the inline #tags found in the body of a note, in
+// order of appearance and without the leading "#". Tags inside frontmatter,
+// code blocks and inline code are ignored, as are purely numeric tags
+// (e.g. "#123"), matching Obsidian's behaviour.
+func ExtractInlineTags(content string) []string {
+	var tags []string
+	for _, line := range splitMarkdownLines(content) {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		for _, m := range inlineTagRegex.FindAllStringSubmatch(stripInlineCode(line.Text), -1) {
+			tag := strings.Trim(m[2], "/")
+			if tag == "" || isNumeric(tag) {
+				continue
+			}
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// ExtractLinks returns the targets of all wikilinks, embeds and Markdown
+// links in content, as found by ParseLinks.
+func ExtractLinks(content string) []string {
+	var links []string
+	for _, link := range ParseLinks(content) {
+		links = append(links, link.Target)
+	}
+	return links
+}
+
+func isNumeric(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FrontmatterTags returns the tags declared in a note's frontmatter under the
+// "tags" (or legacy "tag") key. Both YAML lists and comma/space separated
+// strings are accepted, and a leading "#" is removed.
+func FrontmatterTags(fm map[string]interface{}) []string {
+	var tags []string
+	for _, key := range []string{"tags", "tag"} {
+		switch v := fm[key].(type) {
+		case string:
+			for _, t := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == ' ' }) {
+				tags = appendTag(tags, t)
+			}
+		case []interface{}:
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					tags = appendTag(tags, s)
+				}
+			}
+		}
+	}
+	return tags
+}
+
+func appendTag(tags []string, tag string) []string {
+	tag = strings.Trim(strings.TrimSpace(tag), "#/")
+	if tag == "" {
+		return tags
+	}
+	return append(tags, tag)
+}
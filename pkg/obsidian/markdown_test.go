@@ -0,0 +1,88 @@
+package obsidian_test
+
+import (
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractHeadings(t *testing.T) {
+	t.Run("Extracts ATX headings with levels and lines", func(t *testing.T) {
+		content := "# Title\n\nSome text\n## Section One ##\n### Sub"
+		headings := obsidian.ExtractHeadings(content)
+		assert.Equal(t, []obsidian.Heading{
+			{Level: 1, Text: "Title", Line: 1},
+			{Level: 2, Text: "Section One", Line: 4},
+			{Level: 3, Text: "Sub", Line: 5},
+		}, headings)
+	})
+
+	t.Run("Ignores frontmatter and code blocks", func(t *testing.T) {
+		content := "---\ntitle: x\n---\n```bash\n# comment\n```\n# Real"
+		headings := obsidian.ExtractHeadings(content)
+		assert.Equal(t, []obsidian.Heading{{Level: 1, Text: "Real", Line: 7}}, headings)
+	})
+
+	t.Run("Requires a space after the hashes", func(t *testing.T) {
+		assert.Empty(t, obsidian.ExtractHeadings("#tag\n####### seven"))
+	})
+}
+
+func TestExtractInlineTags(t *testing.T) {
+	tests := []struct {
+		testName string
+		content  string
+		expected []string
+	}{
+		{"Simple tags", "A #todo and #idea", []string{"todo", "idea"}},
+		{"Nested tags", "Filed under #project/alpha/notes", []string{"project/alpha/notes"}},
+		{"Tag at start of line", "#start of line", []string{"start"}},
+		{"Ignores numeric tags", "Issue #123 and #v2", []string{"v2"}},
+		{"Ignores headings", "# Heading\n## Another", nil},
+		{"Ignores link anchors and urls", "[[note#heading]] and http://x.com/#frag", nil},
+		{"Ignores inline code", "Run `echo #nope` then #yes", []string{"yes"}},
+		{"Ignores code blocks", "```\n#nope\n```\n#yes", []string{"yes"}},
+		{"Ignores frontmatter", "---\ncolor: #fff\n---\n#yes", []string{"yes"}},
+		{"Supports unicode", "#café time", []string{"café"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			assert.Equal(t, test.expected, obsidian.ExtractInlineTags(test.content))
+		})
+	}
+}
+
+func TestExtractLinks(t *testing.T) {
+	t.Run("Extracts wikilinks, embeds and markdown links", func(t *testing.T) {
+		content := "[[Note A]] [[folder/Note B|alias]] [[Note C#Heading]] ![[image.png]] [md](other.md) [ext](https://example.com) [anchor](#top) [sec](path/to.md#part)"
+		links := obsidian.ExtractLinks(content)
+		assert.Equal(t, []string{"Note A", "folder/Note B", "Note C", "image.png", "other.md", "path/to.md"}, links)
+	})
+
+	t.Run("Returns nil for content without links", func(t *testing.T) {
+		assert.Nil(t, obsidian.ExtractLinks("plain text"))
+	})
+}
+
+func TestFrontmatterTags(t *testing.T) {
+	t.Run("Reads list of tags", func(t *testing.T) {
+		fm := map[string]interface{}{"tags": []interface{}{"#a", "b/c", 3}}
+		assert.Equal(t, []string{"a", "b/c"}, obsidian.FrontmatterTags(fm))
+	})
+
+	t.Run("Reads comma or space separated string", func(t *testing.T) {
+		fm := map[string]interface{}{"tags": "a, b c"}
+		assert.Equal(t, []string{"a", "b", "c"}, obsidian.FrontmatterTags(fm))
+	})
+
+	t.Run("Reads legacy tag key", func(t *testing.T) {
+		fm := map[string]interface{}{"tag": "single"}
+		assert.Equal(t, []string{"single"}, obsidian.FrontmatterTags(fm))
+	})
+
+	t.Run("Returns nil without tags", func(t *testing.T) {
+		assert.Nil(t, obsidian.FrontmatterTags(map[string]interface{}{"title": "x"}))
+	})
+}
@@ -0,0 +1,538 @@
+package obsidian
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Date fields a Moment.js token can be parsed into.
+const (
+	fieldNone = iota
+	fieldYear
+	fieldQuarter
+	fieldMonth
+	fieldDay
+	fieldDayOfYear
+	fieldWeekday
+	fieldISOWeekday
+	fieldLocaleYear
+	fieldLocaleWeek
+	fieldISOYear
+	fieldISOWeek
+	fieldHour
+	fieldHour12
+	fieldMinute
+	fieldSecond
+	fieldNanosecond
+	fieldPM
+	fieldZone
+	fieldUnix
+	fieldUnixMilli
+	fieldCount
+)
+
+// momentToken formats and parses one Moment.js token.
+type momentToken struct {
+	token  string
+	format func(t time.Time) string
+	// pattern matches the token's text when parsing, and value converts
+	// the match into field.
+	pattern string
+	field   int
+	value   func(s string) int
+}
+
+// localizedFormats are Moment's localized formats in the en locale. They
+// expand to other tokens.
+var localizedFormats = map[string]string{
+	"LTS":  "h:mm:ss A",
+	"LT":   "h:mm A",
+	"L":    "MM/DD/YYYY",
+	"LL":   "MMMM D, YYYY",
+	"LLL":  "MMMM D, YYYY h:mm A",
+	"LLLL": "dddd, MMMM D, YYYY h:mm A",
+	"l":    "M/D/YYYY",
+	"ll":   "MMM D, YYYY",
+	"lll":  "MMM D, YYYY h:mm A",
+	"llll": "ddd, MMM D, YYYY h:mm A",
+}
+
+var (
+	monthNames      = namePattern(12, func(i int) string { return time.Month(i + 1).String() })
+	shortMonthNames = namePattern(12, func(i int) string { return time.Month(i + 1).String()[:3] })
+	weekdayNames    = namePattern(7, func(i int) string { return time.Weekday(i).String() })
+	shortDayNames   = namePattern(7, func(i int) string { return time.Weekday(i).String()[:3] })
+	minDayNames     = namePattern(7, func(i int) string { return time.Weekday(i).String()[:2] })
+)
+
+const (
+	ordinalPattern = `\d{1,2}(?:st|nd|rd|th)`
+	zonePattern    = `Z|[+-]\d{2}:?\d{2}`
+)
+
+// momentTokens lists the supported Moment.js tokens, longest first so that
+// tokens match before their prefixes.
+var momentTokens = sortedMomentTokens([]momentToken{
+	// Year, quarter and month.
+	{"YYYYYY", func(t time.Time) string { return signedYear(t.Year(), 6) }, `[+-]\d{6}`, fieldYear, atoi},
+	{"YYYYY", func(t time.Time) string { return fmt.Sprintf("%05d", t.Year()) }, `\d{5}`, fieldYear, atoi},
+	{"YYYY", func(t time.Time) string { return fourDigitYear(t.Year()) }, `\d{4}`, fieldYear, atoi},
+	{"YY", func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()%100) }, `\d{2}`, fieldYear, twoDigitYear},
+	{"Y", func(t time.Time) string { return fourDigitYearOrMore(t.Year()) }, `[+-]?\d+`, fieldYear, atoi},
+	{"Q", func(t time.Time) string { return fmt.Sprint(quarter(t)) }, `[1-4]`, fieldQuarter, atoi},
+	{"Qo", func(t time.Time) string { return ordinal(quarter(t)) }, `[1-4](?:st|nd|rd|th)`, fieldQuarter, atoi},
+	{"MMMM", func(t time.Time) string { return t.Month().String() }, monthNames, fieldMonth, monthValue},
+	{"MMM", func(t time.Time) string { return t.Month().String()[:3] }, shortMonthNames, fieldMonth, monthValue},
+	{"MM", func(t time.Time) string { return fmt.Sprintf("%02d", int(t.Month())) }, `\d{2}`, fieldMonth, atoi},
+	{"Mo", func(t time.Time) string { return ordinal(int(t.Month())) }, ordinalPattern, fieldMonth, atoi},
+	{"M", func(t time.Time) string { return fmt.Sprint(int(t.Month())) }, `\d{1,2}`, fieldMonth, atoi},
+
+	// Day of month and of year.
+	{"DD", func(t time.Time) string { return fmt.Sprintf("%02d", t.Day()) }, `\d{2}`, fieldDay, atoi},
+	{"Do", func(t time.Time) string { return ordinal(t.Day()) }, ordinalPattern, fieldDay, atoi},
+	{"D", func(t time.Time) string { return fmt.Sprint(t.Day()) }, `\d{1,2}`, fieldDay, atoi},
+	{"DDDD", func(t time.Time) string { return fmt.Sprintf("%03d", t.YearDay()) }, `\d{3}`, fieldDayOfYear, atoi},
+	{"DDDo", func(t time.Time) string { return ordinal(t.YearDay()) }, `\d{1,3}(?:st|nd|rd|th)`, fieldDayOfYear, atoi},
+	{"DDD", func(t time.Time) string { return fmt.Sprint(t.YearDay()) }, `\d{1,3}`, fieldDayOfYear, atoi},
+
+	// Day of week.
+	{"dddd", func(t time.Time) string { return t.Weekday().String() }, weekdayNames, fieldWeekday, weekdayValue},
+	{"ddd", func(t time.Time) string { return t.Weekday().String()[:3] }, shortDayNames, fieldWeekday, weekdayValue},
+	{"dd", func(t time.Time) string { return t.Weekday().String()[:2] }, minDayNames, fieldWeekday, weekdayValue},
+	{"do", func(t time.Time) string { return ordinal(int(t.Weekday())) }, `[0-6](?:st|nd|rd|th)`, fieldWeekday, atoi},
+	{"d", func(t time.Time) string { return fmt.Sprint(int(t.Weekday())) }, `[0-6]`, fieldWeekday, atoi},
+	{"e", func(t time.Time) string { return fmt.Sprint(int(t.Weekday())) }, `[0-6]`, fieldWeekday, atoi},
+	{"E", func(t time.Time) string { return fmt.Sprint(isoWeekday(t)) }, `[1-7]`, fieldISOWeekday, atoi},
+
+	// Weeks and week years.
+	{"gggg", func(t time.Time) string { year, _ := localeWeek(t); return fmt.Sprintf("%04d", year) }, `\d{4}`, fieldLocaleYear, atoi},
+	{"gg", func(t time.Time) string { year, _ := localeWeek(t); return fmt.Sprintf("%02d", year%100) }, `\d{2}`, fieldLocaleYear, twoDigitYear},
+	{"ww", func(t time.Time) string { _, week := localeWeek(t); return fmt.Sprintf("%02d", week) }, `\d{2}`, fieldLocaleWeek, atoi},
+	{"wo", func(t time.Time) string { _, week := localeWeek(t); return ordinal(week) }, ordinalPattern, fieldLocaleWeek, atoi},
+	{"w", func(t time.Time) string { _, week := localeWeek(t); return fmt.Sprint(week) }, `\d{1,2}`, fieldLocaleWeek, atoi},
+	{"GGGG", func(t time.Time) string { year, _ := t.ISOWeek(); return fmt.Sprintf("%04d", year) }, `\d{4}`, fieldISOYear, atoi},
+	{"GG", func(t time.Time) string { year, _ := t.ISOWeek(); return fmt.Sprintf("%02d", year%100) }, `\d{2}`, fieldISOYear, twoDigitYear},
+	{"WW", func(t time.Time) string { _, week := t.ISOWeek(); return fmt.Sprintf("%02d", week) }, `\d{2}`, fieldISOWeek, atoi},
+	{"Wo", func(t time.Time) string { _, week := t.ISOWeek(); return ordinal(week) }, ordinalPattern, fieldISOWeek, atoi},
+	{"W", func(t time.Time) string { _, week := t.ISOWeek(); return fmt.Sprint(week) }, `\d{1,2}`, fieldISOWeek, atoi},
+
+	// Time of day.
+	{"HH", func(t time.Time) string { return fmt.Sprintf("%02d", t.Hour()) }, `\d{2}`, fieldHour, atoi},
+	{"H", func(t time.Time) string { return fmt.Sprint(t.Hour()) }, `\d{1,2}`, fieldHour, atoi},
+	{"hh", func(t time.Time) string { return fmt.Sprintf("%02d", hour12(t)) }, `\d{2}`, fieldHour12, atoi},
+	{"h", func(t time.Time) string { return fmt.Sprint(hour12(t)) }, `\d{1,2}`, fieldHour12, atoi},
+	{"kk", func(t time.Time) string { return fmt.Sprintf("%02d", hour24(t)) }, `\d{2}`, fieldHour, hour24Value},
+	{"k", func(t time.Time) string { return fmt.Sprint(hour24(t)) }, `\d{1,2}`, fieldHour, hour24Value},
+	{"mm", func(t time.Time) string { return fmt.Sprintf("%02d", t.Minute()) }, `\d{2}`, fieldMinute, atoi},
+	{"m", func(t time.Time) string { return fmt.Sprint(t.Minute()) }, `\d{1,2}`, fieldMinute, atoi},
+	{"ss", func(t time.Time) string { return fmt.Sprintf("%02d", t.Second()) }, `\d{2}`, fieldSecond, atoi},
+	{"s", func(t time.Time) string { return fmt.Sprint(t.Second()) }, `\d{1,2}`, fieldSecond, atoi},
+	{"A", func(t time.Time) string { return t.Format("PM") }, `(?i:AM|PM)`, fieldPM, pmValue},
+	{"a", func(t time.Time) string { return t.Format("pm") }, `(?i:AM|PM)`, fieldPM, pmValue},
+
+	// Time zone and timestamps.
+	{"ZZ", func(t time.Time) string { return t.Format("-0700") }, zonePattern, fieldZone, zoneValue},
+	{"Z", func(t time.Time) string { return t.Format("-07:00") }, zonePattern, fieldZone, zoneValue},
+	{"zz", func(t time.Time) string { return t.Format("MST") }, `[A-Za-z]+`, fieldNone, nil},
+	{"z", func(t time.Time) string { return t.Format("MST") }, `[A-Za-z]+`, fieldNone, nil},
+	{"X", func(t time.Time) string { return fmt.Sprint(t.Unix()) }, `-?\d+`, fieldUnix, atoi},
+	{"x", func(t time.Time) string { return fmt.Sprint(t.UnixNano() / int64(time.Millisecond)) }, `-?\d+`, fieldUnixMilli, atoi},
+}, fractionTokens())
+
+// fractionTokens returns the tokens S to SSSSSSSSS, for tenths of a second
+// down to nanoseconds.
+func fractionTokens() []momentToken {
+	tokens := make([]momentToken, 9)
+	for i := range tokens {
+		digits := i + 1
+		scale := int(math.Pow10(9 - digits))
+		tokens[i] = momentToken{
+			token:   strings.Repeat("S", digits),
+			format:  func(t time.Time) string { return fmt.Sprintf("%09d", t.Nanosecond())[:digits] },
+			pattern: fmt.Sprintf(`\d{%d}`, digits),
+			field:   fieldNanosecond,
+			value:   func(s string) int { return atoi(s) * scale },
+		}
+	}
+	return tokens
+}
+
+func sortedMomentTokens(lists ...[]momentToken) []momentToken {
+	var tokens []momentToken
+	for _, list := range lists {
+		tokens = append(tokens, list...)
+	}
+	sort.SliceStable(tokens, func(i, j int) bool { return len(tokens[i].token) > len(tokens[j].token) })
+	return tokens
+}
+
+// FormatMoment formats t with a Moment.js format string, as Obsidian does
+// for note names. It supports every Moment.js token of the en locale,
+// including ordinals (Do), days of the year (DDD), locale and ISO weeks
+// (ww, WW, gggg, GGGG), timestamps (X, x) and localized formats (L, LL,
+// LT, ...). Text in square brackets, and a character after a backslash, is
+// copied literally, so "gggg-[W]ww" gives "2024-W09".
+func FormatMoment(t time.Time, format string) string {
+	var b strings.Builder
+	scanMoment(format, func(literal string) {
+		b.WriteString(literal)
+	}, func(token momentToken) {
+		b.WriteString(token.format(t))
+	})
+	return b.String()
+}
+
+// ValidateMomentFormat checks that format is a Moment.js format dates can be
+// parsed back from: valid UTF-8, with at least one token that stands for
+// part of a date or time.
+func ValidateMomentFormat(format string) error {
+	if !utf8.ValidString(format) {
+		return NewError(ErrCodeInvalidArgument, fmt.Sprintf("invalid date format %q: not valid UTF-8", format))
+	}
+	dated := false
+	scanMoment(format, func(string) {}, func(token momentToken) {
+		if token.field != fieldNone {
+			dated = true
+		}
+	})
+	if !dated {
+		return NewError(ErrCodeInvalidArgument, fmt.Sprintf("invalid date format %q: no date tokens", format))
+	}
+	return nil
+}
+
+// ParseMoment parses value, such as a note name, written in a Moment.js
+// format, accepting the same tokens as FormatMoment. Missing fields default
+// as in Moment: the current year, January, the 1st and midnight. A week
+// gives its first day unless a weekday is given too, and a weekday name
+// that does not match the date makes the value invalid. The result is in
+// the local time zone unless the value has a zone offset. A value that does
+// not match, or a format ValidateMomentFormat rejects, is an invalid_argument
+// error.
+func ParseMoment(value, format string) (time.Time, error) {
+	if err := ValidateMomentFormat(format); err != nil {
+		return time.Time{}, err
+	}
+	var pattern strings.Builder
+	var fields []momentToken
+	pattern.WriteString("^")
+	scanMoment(format, func(literal string) {
+		pattern.WriteString(regexp.QuoteMeta(literal))
+	}, func(token momentToken) {
+		pattern.WriteString("(" + token.pattern + ")")
+		fields = append(fields, token)
+	})
+	pattern.WriteString("$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return time.Time{}, NewError(ErrCodeInvalidArgument, fmt.Sprintf("invalid date format %q", format))
+	}
+	invalid := NewError(ErrCodeInvalidArgument, fmt.Sprintf("%q does not match date format %q", value, format))
+	match := re.FindStringSubmatch(value)
+	if match == nil {
+		return time.Time{}, invalid
+	}
+
+	var set [fieldCount]bool
+	var values [fieldCount]int
+	for i, token := range fields {
+		if token.field != fieldNone {
+			values[token.field] = token.value(match[i+1])
+			set[token.field] = true
+		}
+	}
+
+	location := time.Local
+	if set[fieldZone] {
+		location = time.FixedZone("", values[fieldZone])
+	}
+	if set[fieldUnixMilli] {
+		return time.Unix(0, int64(values[fieldUnixMilli])*int64(time.Millisecond)).In(location), nil
+	}
+	if set[fieldUnix] {
+		return time.Unix(int64(values[fieldUnix]), 0).In(location), nil
+	}
+
+	year := time.Now().Year()
+	if set[fieldYear] {
+		year = values[fieldYear]
+	}
+	month, day := 1, 1
+	if set[fieldQuarter] {
+		month = (values[fieldQuarter]-1)*3 + 1
+	}
+	if set[fieldMonth] {
+		month = values[fieldMonth]
+	}
+	if set[fieldDay] {
+		day = values[fieldDay]
+	}
+	hour := values[fieldHour]
+	if set[fieldHour12] {
+		hour = values[fieldHour12] % 12
+		if values[fieldPM] == 1 {
+			hour += 12
+		}
+	}
+	if month < 1 || month > 12 || hour > 23 || values[fieldMinute] > 59 || values[fieldSecond] > 59 {
+		return time.Time{}, invalid
+	}
+
+	var date time.Time
+	switch {
+	case set[fieldISOWeek]:
+		if values[fieldISOWeek] < 1 || values[fieldISOWeek] > 53 {
+			return time.Time{}, invalid
+		}
+		if set[fieldISOYear] {
+			year = values[fieldISOYear]
+		}
+		jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, location)
+		monday := jan4.AddDate(0, 0, 1-isoWeekday(jan4))
+		date = monday.AddDate(0, 0, 7*(values[fieldISOWeek]-1))
+		if set[fieldISOWeekday] {
+			date = date.AddDate(0, 0, values[fieldISOWeekday]-1)
+		} else if set[fieldWeekday] {
+			date = date.AddDate(0, 0, (values[fieldWeekday]+6)%7)
+		}
+	case set[fieldLocaleWeek]:
+		if values[fieldLocaleWeek] < 1 || values[fieldLocaleWeek] > 53 {
+			return time.Time{}, invalid
+		}
+		if set[fieldLocaleYear] {
+			year = values[fieldLocaleYear]
+		}
+		jan1 := time.Date(year, time.January, 1, 0, 0, 0, 0, location)
+		sunday := jan1.AddDate(0, 0, -int(jan1.Weekday()))
+		date = sunday.AddDate(0, 0, 7*(values[fieldLocaleWeek]-1))
+		if set[fieldWeekday] {
+			date = date.AddDate(0, 0, values[fieldWeekday])
+		} else if set[fieldISOWeekday] {
+			date = date.AddDate(0, 0, values[fieldISOWeekday]%7)
+		}
+	case set[fieldDayOfYear] && !set[fieldMonth] && !set[fieldDay]:
+		date = time.Date(year, time.January, values[fieldDayOfYear], 0, 0, 0, 0, location)
+		if values[fieldDayOfYear] < 1 || date.Year() != year {
+			return time.Time{}, invalid
+		}
+	default:
+		date = time.Date(year, time.Month(month), day, 0, 0, 0, 0, location)
+		if date.Month() != time.Month(month) || date.Day() != day {
+			return time.Time{}, invalid
+		}
+		if set[fieldWeekday] && int(date.Weekday()) != values[fieldWeekday] {
+			return time.Time{}, invalid
+		}
+		if set[fieldISOWeekday] && isoWeekday(date) != values[fieldISOWeekday] {
+			return time.Time{}, invalid
+		}
+	}
+
+	return time.Date(date.Year(), date.Month(), date.Day(), hour, values[fieldMinute], values[fieldSecond], values[fieldNanosecond], location), nil
+}
+
+// scanMoment splits a Moment.js format into literal text and tokens,
+// expanding localized formats.
+func scanMoment(format string, literal func(string), token func(momentToken)) {
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end > 0 {
+				literal(format[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+		if format[i] == '\\' && i+1 < len(format) {
+			literal(format[i+1 : i+2])
+			i += 2
+			continue
+		}
+
+		if expansion, n := localizedFormat(format[i:]); n > 0 {
+			scanMoment(expansion, literal, token)
+			i += n
+			continue
+		}
+
+		matched := false
+		for _, t := range momentTokens {
+			if strings.HasPrefix(format[i:], t.token) {
+				token(t)
+				i += len(t.token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			literal(format[i : i+1])
+			i++
+		}
+	}
+}
+
+// localizedFormat returns the expansion of the localized format at the
+// start of s and its length, or 0 when there is none.
+func localizedFormat(s string) (string, int) {
+	for _, name := range []string{"LLLL", "llll", "LTS", "LLL", "lll", "LL", "ll", "LT", "L", "l"} {
+		if strings.HasPrefix(s, name) {
+			return localizedFormats[name], len(name)
+		}
+	}
+	return "", 0
+}
+
+// localeWeek returns the week-numbering year and week of t in Moment's
+// default (en) locale: weeks start on Sunday and week 1 is the week
+// containing January 1st.
+func localeWeek(t time.Time) (year, week int) {
+	saturday := time.Date(t.Year(), t.Month(), t.Day()+6-int(t.Weekday()), 0, 0, 0, 0, time.UTC)
+	return saturday.Year(), (saturday.YearDay()-1)/7 + 1
+}
+
+func quarter(t time.Time) int {
+	return (int(t.Month())-1)/3 + 1
+}
+
+// isoWeekday returns the ISO day of the week, from 1 for Monday to 7 for
+// Sunday.
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+func hour12(t time.Time) int {
+	hour := t.Hour() % 12
+	if hour == 0 {
+		return 12
+	}
+	return hour
+}
+
+// hour24 returns the hour from 1 to 24, as Moment's k token does.
+func hour24(t time.Time) int {
+	if t.Hour() == 0 {
+		return 24
+	}
+	return t.Hour()
+}
+
+// ordinal returns n with its English ordinal suffix, e.g. "1st" or "12th".
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+func fourDigitYear(year int) string {
+	if year > 9999 {
+		return fmt.Sprintf("+%d", year)
+	}
+	return fmt.Sprintf("%04d", year)
+}
+
+func fourDigitYearOrMore(year int) string {
+	if year > 9999 {
+		return fmt.Sprintf("+%d", year)
+	}
+	return fmt.Sprint(year)
+}
+
+func signedYear(year, digits int) string {
+	sign := "+"
+	if year < 0 {
+		sign = "-"
+		year = -year
+	}
+	return fmt.Sprintf("%s%0*d", sign, digits, year)
+}
+
+// namePattern returns a case-insensitive pattern matching any of n names.
+func namePattern(n int, name func(i int) string) string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = name(i)
+	}
+	return "(?i:" + strings.Join(names, "|") + ")"
+}
+
+// atoi reads the leading integer of s, ignoring a "+" sign and any suffix
+// such as an ordinal's.
+func atoi(s string) int {
+	end := 0
+	for end < len(s) && (s[end] >= '0' && s[end] <= '9' || end == 0 && (s[end] == '-' || s[end] == '+')) {
+		end++
+	}
+	n, _ := strconv.Atoi(strings.TrimPrefix(s[:end], "+"))
+	return n
+}
+
+// twoDigitYear reads a two-digit year as Moment does: 69-99 are 1900s and
+// 00-68 are 2000s.
+func twoDigitYear(s string) int {
+	year := atoi(s)
+	if year > 68 {
+		return 1900 + year
+	}
+	return 2000 + year
+}
+
+func hour24Value(s string) int {
+	return atoi(s) % 24
+}
+
+func monthValue(s string) int {
+	for month := time.January; month <= time.December; month++ {
+		if strings.HasPrefix(strings.ToLower(month.String()), strings.ToLower(s)) {
+			return int(month)
+		}
+	}
+	return 0
+}
+
+func weekdayValue(s string) int {
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), strings.ToLower(s)) {
+			return int(day)
+		}
+	}
+	return 0
+}
+
+func pmValue(s string) int {
+	if strings.EqualFold(s, "pm") {
+		return 1
+	}
+	return 0
+}
+
+// zoneValue returns the offset in seconds of a zone such as "+05:30",
+// "-0700" or "Z".
+func zoneValue(s string) int {
+	if s == "Z" {
+		return 0
+	}
+	digits := strings.Replace(s[1:], ":", "", 1)
+	offset := (atoi(digits[:2])*60 + atoi(digits[2:])) * 60
+	if s[0] == '-' {
+		return -offset
+	}
+	return offset
+}
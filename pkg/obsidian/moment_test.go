@@ -0,0 +1,195 @@
+package obsidian_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMoment(t *testing.T) {
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 14, 5, 9, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name     string
+		date     time.Time
+		format   string
+		expected string
+	}{
+		{"Default daily format", date(2024, time.March, 5), "YYYY-MM-DD", "2024-03-05"},
+		{"Names", date(2024, time.March, 5), "dddd, MMMM D, YY", "Tuesday, March 5, 24"},
+		{"Short names", date(2024, time.March, 5), "ddd MMM DD", "Tue Mar 05"},
+		{"Time", date(2024, time.March, 5), "HH:mm:ss h:m:s A a", "14:05:09 2:5:9 PM pm"},
+		{"Brackets are literal", date(2024, time.March, 5), "[Week of] YYYY", "Week of 2024"},
+		{"Unclosed bracket", date(2024, time.March, 5), "[YYYY", "[2024"},
+		{"Quarter", date(2024, time.August, 1), "YYYY-[Q]Q", "2024-Q3"},
+		{"Locale week", date(2024, time.March, 5), "gggg-[W]ww", "2024-W10"},
+		{"Locale week of January 1st", date(2024, time.January, 1), "gggg-[W]w", "2024-W1"},
+		{"Locale week belongs to the next year", date(2023, time.December, 31), "gggg-[W]ww", "2024-W01"},
+		{"Locale week before January 1st's Sunday", date(2022, time.December, 31), "gg-ww", "22-53"},
+		{"ISO week", date(2024, time.March, 5), "GGGG-[W]WW", "2024-W10"},
+		{"ISO week belongs to the previous year", date(2021, time.January, 1), "GGGG-[W]W", "2020-W53"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, obsidian.FormatMoment(test.date, test.format))
+		})
+	}
+}
+
+// TestFormatMomentDocs mirrors the format examples of Moment.js's own en
+// locale tests, for Sunday, February 14th 2010, 15:25:50.125.
+func TestFormatMomentDocs(t *testing.T) {
+	date := time.Date(2010, time.February, 14, 15, 25, 50, 125000000, time.UTC)
+
+	tests := []struct {
+		format   string
+		expected string
+	}{
+		{"dddd, MMMM Do YYYY, h:mm:ss a", "Sunday, February 14th 2010, 3:25:50 pm"},
+		{"ddd, hA", "Sun, 3PM"},
+		{"M Mo MM MMMM MMM", "2 2nd 02 February Feb"},
+		{"YYYY YY", "2010 10"},
+		{"D Do DD", "14 14th 14"},
+		{"d do dddd ddd dd", "0 0th Sunday Sun Su"},
+		{"DDD DDDo DDDD", "45 45th 045"},
+		{"w wo ww", "8 8th 08"},
+		{"h hh", "3 03"},
+		{"H HH", "15 15"},
+		{"m mm", "25 25"},
+		{"s ss", "50 50"},
+		{"a A", "pm PM"},
+		{"[the] DDDo [day of the year]", "the 45th day of the year"},
+		{"LTS", "3:25:50 PM"},
+		{"L", "02/14/2010"},
+		{"LL", "February 14, 2010"},
+		{"LLL", "February 14, 2010 3:25 PM"},
+		{"LLLL", "Sunday, February 14, 2010 3:25 PM"},
+		{"l", "2/14/2010"},
+		{"ll", "Feb 14, 2010"},
+		{"lll", "Feb 14, 2010 3:25 PM"},
+		{"llll", "Sun, Feb 14, 2010 3:25 PM"},
+		{"Q Qo", "1 1st"},
+		{"e E", "0 7"},
+		{"W Wo WW GGGG GG", "6 6th 06 2010 10"},
+		{"gggg gg", "2010 10"},
+		{"k kk", "15 15"},
+		{"S SS SSS SSSSSS", "1 12 125 125000"},
+		{"Z ZZ", "+00:00 +0000"},
+		{"X x", "1266161150 1266161150125"},
+		{"Y YYYYY YYYYYY", "2010 02010 +002010"},
+		{"\\Y\\e\\a\\r YYYY", "Year 2010"},
+	}
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			assert.Equal(t, test.expected, obsidian.FormatMoment(date, test.format))
+		})
+	}
+
+	t.Run("Ordinals", func(t *testing.T) {
+		expected := []string{"1st", "2nd", "3rd", "4th", "10th", "11th", "12th", "13th", "21st", "22nd", "23rd", "31st"}
+		for i, day := range []int{1, 2, 3, 4, 10, 11, 12, 13, 21, 22, 23, 31} {
+			assert.Equal(t, expected[i], obsidian.FormatMoment(time.Date(2010, time.January, day, 0, 0, 0, 0, time.UTC), "Do"))
+		}
+	})
+
+	t.Run("Midnight in the k token", func(t *testing.T) {
+		assert.Equal(t, "24 12 AM", obsidian.FormatMoment(time.Date(2010, time.January, 1, 0, 0, 0, 0, time.UTC), "k h A"))
+	})
+}
+
+func TestParseMoment(t *testing.T) {
+	day := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+	}
+
+	tests := []struct {
+		name     string
+		value    string
+		format   string
+		expected time.Time
+	}{
+		{"Default daily format", "2024-03-05", "YYYY-MM-DD", day(2024, time.March, 5)},
+		{"Names are case-insensitive", "tuesday, march 5, 24", "dddd, MMMM D, YY", day(2024, time.March, 5)},
+		{"Short names", "Tue Mar 05 2024", "ddd MMM DD YYYY", day(2024, time.March, 5)},
+		{"Folders and brackets", "2024/03/Week of 05", "YYYY/MM/[Week of] DD", day(2024, time.March, 5)},
+		{"Two-digit years", "69-01-01", "YY-MM-DD", day(1969, time.January, 1)},
+		{"Quarter", "2024-Q3", "YYYY-[Q]Q", day(2024, time.July, 1)},
+		{"Locale week starts on Sunday", "2024-W01", "gggg-[W]ww", day(2023, time.December, 31)},
+		{"ISO week starts on Monday", "2021-W01", "GGGG-[W]WW", day(2021, time.January, 4)},
+		{"Time", "2024-03-05 2:07 pm", "YYYY-MM-DD h:mm a", time.Date(2024, time.March, 5, 14, 7, 0, 0, time.Local)},
+		{"Ordinals", "March 5th, 2024", "MMMM Do, YYYY", day(2024, time.March, 5)},
+		{"Day of year", "2024-065", "YYYY-DDDD", day(2024, time.March, 5)},
+		{"Minimal weekday names", "Tu 2024-03-05", "dd YYYY-MM-DD", day(2024, time.March, 5)},
+		{"ISO week and weekday", "2024-W10-2", "GGGG-[W]WW-E", day(2024, time.March, 5)},
+		{"Locale week and weekday", "2024 10 Tue", "gggg ww ddd", day(2024, time.March, 5)},
+		{"Localized formats", "Tuesday, March 5, 2024 2:07 PM", "LLLL", time.Date(2024, time.March, 5, 14, 7, 0, 0, time.Local)},
+		{"Escapes", "Week 10 of 2024", "\\W\\e\\e\\k W [of] YYYY", day(2024, time.March, 4)},
+		{"Fractions", "2024-03-05 14:07:09.250", "YYYY-MM-DD HH:mm:ss.SSS", time.Date(2024, time.March, 5, 14, 7, 9, 250000000, time.Local)},
+		{"Midnight in the k token", "2024-03-05 24:00", "YYYY-MM-DD kk:mm", day(2024, time.March, 5)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			parsed, err := obsidian.ParseMoment(test.value, test.format)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, parsed)
+		})
+	}
+
+	t.Run("Zones and timestamps", func(t *testing.T) {
+		parsed, err := obsidian.ParseMoment("2024-03-05T14:07:00+05:30", "YYYY-MM-DD[T]HH:mm:ssZ")
+		assert.NoError(t, err)
+		assert.True(t, parsed.Equal(time.Date(2024, time.March, 5, 8, 37, 0, 0, time.UTC)))
+
+		parsed, err = obsidian.ParseMoment("1266161150", "X")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1266161150), parsed.Unix())
+
+		parsed, err = obsidian.ParseMoment("1266161150125", "x")
+		assert.NoError(t, err)
+		assert.Equal(t, 125000000, parsed.Nanosecond())
+	})
+
+	t.Run("Round trips the daily note formats of Moment's docs", func(t *testing.T) {
+		date := time.Date(2010, time.February, 14, 0, 0, 0, 0, time.Local)
+		for _, format := range []string{"YYYY-MM-DD", "dddd, MMMM Do YYYY", "[Day] DDDD [of] YYYY", "ll", "YYYY/MM/DD-dd"} {
+			parsed, err := obsidian.ParseMoment(obsidian.FormatMoment(date, format), format)
+			assert.NoError(t, err, format)
+			assert.Equal(t, date, parsed, format)
+		}
+	})
+
+	t.Run("Rejects names that do not match", func(t *testing.T) {
+		for value, format := range map[string]string{
+			"2024-02-30":      "YYYY-MM-DD",
+			"2024-13-01":      "YYYY-MM-DD",
+			"2024-03-05 copy": "YYYY-MM-DD",
+			"notes":           "YYYY-MM-DD",
+			"2023-366":        "YYYY-DDDD",
+			"Mon 2024-03-05":  "ddd YYYY-MM-DD",
+		} {
+			_, err := obsidian.ParseMoment(value, format)
+			assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err), value)
+		}
+		_, err := obsidian.ParseMoment("2024-W54", "GGGG-[W]WW")
+		assert.EqualError(t, err, `"2024-W54" does not match date format "GGGG-[W]WW"`)
+	})
+
+	t.Run("Rejects formats with invalid UTF-8", func(t *testing.T) {
+		_, err := obsidian.ParseMoment("2024-03-05", "YYYY-MM-DD\xff")
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
+
+func TestValidateMomentFormat(t *testing.T) {
+	for _, format := range []string{"YYYY-MM-DD", "gggg-[W]ww", "LL", "X", "dddd"} {
+		assert.NoError(t, obsidian.ValidateMomentFormat(format), format)
+	}
+	for _, format := range []string{"", "[Daily]", "\\Y\\M", "YYYY\xff", "zz"} {
+		err := obsidian.ValidateMomentFormat(format)
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err), format)
+	}
+}
@@ -0,0 +1,267 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// MoveFiles moves many notes, attachments and folders at once. The From and
+// To of each move are relative to the vault; notes may be given without
+// .md, and folders stand for every file beneath them. All moves are checked
+// before any file moves: sources must exist and appear once, destinations
+// must differ from each other and be free, unless another move of the batch
+// frees them, and the moves must not form a cycle. Files are then renamed in
+// an order that frees each destination before it is used, and the links to
+// every moved file are updated in a single pass over the vault. If a rename
+// fails, the files already moved are moved back. If updating the links fails
+// once every file has moved, the files stay moved and the error says so, as
+// some notes may already link to the new paths. It returns the files that
+// moved, sorted by their old path.
+func (m *Note) MoveFiles(vaultPath string, moves []FileMove) ([]FileMove, error) {
+	files, folders, err := expandMoves(vaultPath, moves)
+	if err != nil {
+		return nil, err
+	}
+	order, err := orderMoves(vaultPath, files)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := renameFiles(vaultPath, order); err != nil {
+		return nil, err
+	}
+	for _, folder := range folders {
+		removeEmptyFolders(filepath.Join(vaultPath, filepath.FromSlash(folder)))
+	}
+	fmt.Printf("Moved %d files\n", len(order))
+
+	moved := make(map[string]string, len(order))
+	for _, move := range order {
+		moved[move.From] = move.To
+	}
+	if len(moved) > 0 {
+		if err := updateMovedLinks(vaultPath, moved, linkingFilter(vaultPath, moved)); err != nil {
+			return nil, WrapError(ErrorCode(err), fmt.Sprintf("Moved %d files, but not all links to them could be updated: %s", len(order), err.Error()), err)
+		}
+	}
+
+	sort.Slice(order, func(i, j int) bool { return order[i].From < order[j].From })
+	return order, nil
+}
+
+// renameFiles renames the files of moves, relative to the vault, in order,
+// creating destination folders as needed. If a rename fails, the files
+// already renamed are moved back and the folders created are removed.
+func renameFiles(vaultPath string, moves []FileMove) error {
+	var created []string
+	for i, move := range moves {
+		from := filepath.Join(vaultPath, filepath.FromSlash(move.From))
+		to := filepath.Join(vaultPath, filepath.FromSlash(move.To))
+		if dir := missingFolder(filepath.Dir(to)); dir != "" {
+			created = append(created, dir)
+		}
+		err := os.MkdirAll(filepath.Dir(to), 0755)
+		if err == nil {
+			err = os.Rename(from, to)
+		}
+		if err != nil {
+			for j := i - 1; j >= 0; j-- {
+				os.Rename(filepath.Join(vaultPath, filepath.FromSlash(moves[j].To)), filepath.Join(vaultPath, filepath.FromSlash(moves[j].From)))
+			}
+			for j := len(created) - 1; j >= 0; j-- {
+				removeEmptyFolders(created[j])
+			}
+			return NewError(ErrCodeVaultWrite, fmt.Sprintf("Failed to move %q to %q, no files were moved", move.From, move.To))
+		}
+	}
+	return nil
+}
+
+// expandMoves validates moves and turns them into moves of single files
+// with vault-relative paths, skipping files that would not change path. It
+// also returns the folders that were moved.
+func expandMoves(vaultPath string, moves []FileMove) ([]FileMove, []string, error) {
+	absVault, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return nil, nil, NewError(ErrCodeVaultAccess, VaultAccessError)
+	}
+	var paths []string
+
+	var files []FileMove
+	var folders []string
+	for _, move := range moves {
+		fromPath, err := ValidatePath(vaultPath, move.From)
+		if err != nil {
+			return nil, nil, WrapError(ErrorCode(err), fmt.Sprintf("%s: %q", err.Error(), move.From), err)
+		}
+		toPath, err := ValidatePath(vaultPath, move.To)
+		if err != nil {
+			return nil, nil, WrapError(ErrorCode(err), fmt.Sprintf("%s: %q", err.Error(), move.To), err)
+		}
+		from, to := vaultRelative(absVault, fromPath), vaultRelative(absVault, toPath)
+		if from == "" || to == "" {
+			return nil, nil, NewError(ErrCodeInvalidArgument, "cannot move the vault folder itself")
+		}
+
+		if info, err := os.Stat(fromPath); err == nil && info.IsDir() {
+			if to == from || strings.HasPrefix(to, from+"/") {
+				return nil, nil, NewError(ErrCodeInvalidArgument, fmt.Sprintf("cannot move folder %q into itself", from))
+			}
+			if paths == nil {
+				if paths, err = listVaultFiles(vaultPath); err != nil {
+					return nil, nil, err
+				}
+			}
+			for _, p := range paths {
+				if strings.HasPrefix(p, from+"/") {
+					files = append(files, FileMove{From: p, To: to + strings.TrimPrefix(p, from)})
+				}
+			}
+			folders = append(folders, from)
+			continue
+		}
+
+		if !IsAttachment(fromPath) {
+			if _, err := os.Stat(AddMdSuffix(fromPath)); err != nil {
+				return nil, nil, NewError(ErrCodeNoteNotFound, fmt.Sprintf("%s: %q", SourceDoesNotExistError, move.From))
+			}
+			from, to = AddMdSuffix(from), AddMdSuffix(to)
+		}
+		if from != to {
+			files = append(files, FileMove{From: from, To: to})
+		}
+	}
+	return files, folders, nil
+}
+
+// orderMoves checks that files, moves of single files, have distinct
+// sources and destinations and that each destination is free or freed by
+// another move, and returns them ordered so that every destination is freed
+// before it is used.
+func orderMoves(vaultPath string, files []FileMove) ([]FileMove, error) {
+	sources := make(map[string]string, len(files))
+	destinations := make(map[string]string, len(files))
+	for _, move := range files {
+		if _, ok := sources[move.From]; ok {
+			return nil, NewError(ErrCodeInvalidArgument, fmt.Sprintf("%q is moved more than once", move.From))
+		}
+		if other, ok := destinations[move.To]; ok {
+			return nil, NewError(ErrCodeInvalidArgument, fmt.Sprintf("%q and %q are both moved to %q", other, move.From, move.To))
+		}
+		sources[move.From] = move.To
+		destinations[move.To] = move.From
+	}
+	for _, move := range files {
+		if _, freed := sources[move.To]; freed {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(vaultPath, filepath.FromSlash(move.To))); err == nil {
+			return nil, NewError(ErrCodeInvalidArgument, fmt.Sprintf("cannot move %q: %q already exists in vault", move.From, move.To))
+		}
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int, len(files))
+	order := make([]FileMove, 0, len(files))
+	var visit func(from string) error
+	visit = func(from string) error {
+		state[from] = visiting
+		to := sources[from]
+		if _, ok := sources[to]; ok {
+			switch state[to] {
+			case visiting:
+				return NewError(ErrCodeInvalidArgument, fmt.Sprintf("moves form a cycle through %q; move one of the files to a temporary path first", to))
+			case 0:
+				if err := visit(to); err != nil {
+					return err
+				}
+			}
+		}
+		state[from] = done
+		order = append(order, FileMove{From: from, To: to})
+		return nil
+	}
+	for _, move := range files {
+		if state[move.From] == 0 {
+			if err := visit(move.From); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return order, nil
+}
+
+// vaultRelative returns the slash-separated path of p, an absolute path
+// inside the vault at absVault, relative to the vault, or "" for the vault
+// itself.
+func vaultRelative(absVault, p string) string {
+	rel, err := filepath.Rel(absVault, p)
+	if err != nil || rel == "." {
+		return ""
+	}
+	return filepath.ToSlash(rel)
+}
+
+// missingFolder returns the outermost folder on the path to dir that does
+// not exist, or "" if dir exists.
+func missingFolder(dir string) string {
+	missing := ""
+	for {
+		if _, err := os.Lstat(dir); err == nil {
+			return missing
+		}
+		missing = dir
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return missing
+		}
+		dir = parent
+	}
+}
+
+// removeEmptyFolders removes dir and the folders beneath it that are left
+// empty. Hidden files such as .DS_Store are never moved with a folder, so a
+// folder holding nothing else counts as empty and they are removed with it;
+// a hidden folder keeps its parent.
+func removeEmptyFolders(dir string) {
+	var dirs []string
+	filepath.WalkDir(dir, func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if skip, err := skipHidden(dir, p, d); skip {
+			return err
+		}
+		if d.IsDir() {
+			dirs = append(dirs, p)
+		}
+		return nil
+	})
+	for i := len(dirs) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(dirs[i])
+		if err != nil {
+			continue
+		}
+		var hidden []string
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasPrefix(entry.Name(), ".") {
+				hidden = nil
+				break
+			}
+			hidden = append(hidden, filepath.Join(dirs[i], entry.Name()))
+		}
+		if len(hidden) < len(entries) {
+			continue
+		}
+		for _, p := range hidden {
+			os.Remove(p)
+		}
+		os.Remove(dirs[i])
+	}
+}
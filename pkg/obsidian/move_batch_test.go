@@ -0,0 +1,125 @@
+package obsidian_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNote_MoveFiles(t *testing.T) {
+	t.Run("Moves notes, attachments and folders and updates links once", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a.md":            "[[b]] [c](c.md) ![[pic.png]] [[old/x]]",
+			"b.md":            "[[a]]",
+			"c.md":            "",
+			"pic.png":         "png",
+			"old/x.md":        "[up](../a.md)",
+			"old/sub/y.md":    "",
+			"unrelated.md":    "[[elsewhere]]",
+			"linker/links.md": "[[a]] [[c]] ![](../pic.png) [[old/sub/y]]",
+		})
+		note := obsidian.Note{}
+
+		moves, err := note.MoveFiles(vaultPath, []obsidian.FileMove{
+			{From: "a", To: "b"},
+			{From: "b.md", To: "archive/b"},
+			{From: "c", To: "c"},
+			{From: "pic.png", To: "assets/pic.png"},
+			{From: "old", To: "new"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.FileMove{
+			{From: "a.md", To: "b.md"},
+			{From: "b.md", To: "archive/b.md"},
+			{From: "old/sub/y.md", To: "new/sub/y.md"},
+			{From: "old/x.md", To: "new/x.md"},
+			{From: "pic.png", To: "assets/pic.png"},
+		}, moves)
+
+		assert.NoDirExists(t, filepath.Join(vaultPath, "old"))
+		expected := map[string]string{
+			"b.md":            "[[archive/b]] [c](c.md) ![[pic.png]] [[new/x]]",
+			"archive/b.md":    "[[b]]",
+			"new/x.md":        "[up](../b.md)",
+			"linker/links.md": "[[b]] [[c]] ![](../assets/pic.png) [[new/sub/y]]",
+			"unrelated.md":    "[[elsewhere]]",
+		}
+		for p, want := range expected {
+			content, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(p)))
+			assert.NoError(t, err)
+			assert.Equal(t, want, string(content), p)
+		}
+	})
+
+	t.Run("Invalid batches move nothing", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			moves []obsidian.FileMove
+			code  string
+		}{
+			{"Missing source", []obsidian.FileMove{{From: "a", To: "x"}, {From: "missing", To: "y"}}, obsidian.ErrCodeNoteNotFound},
+			{"Missing attachment", []obsidian.FileMove{{From: "a", To: "x"}, {From: "img.png", To: "y.png"}}, obsidian.ErrCodeNoteNotFound},
+			{"Source moved twice", []obsidian.FileMove{{From: "a", To: "x"}, {From: "a.md", To: "y"}}, obsidian.ErrCodeInvalidArgument},
+			{"Same destination", []obsidian.FileMove{{From: "a", To: "x"}, {From: "b", To: "x"}}, obsidian.ErrCodeInvalidArgument},
+			{"Destination exists", []obsidian.FileMove{{From: "a", To: "taken"}}, obsidian.ErrCodeInvalidArgument},
+			{"Cycle", []obsidian.FileMove{{From: "a", To: "b"}, {From: "b", To: "a"}}, obsidian.ErrCodeInvalidArgument},
+			{"Outside the vault", []obsidian.FileMove{{From: "a", To: "../a"}}, obsidian.ErrCodePathTraversal},
+			{"Folder into itself", []obsidian.FileMove{{From: "dir", To: "dir/inner"}}, obsidian.ErrCodeInvalidArgument},
+			{"Rename fails", []obsidian.FileMove{{From: "a", To: "x"}, {From: "b", To: "taken.md/b"}}, obsidian.ErrCodeVaultWrite},
+			{"Rename fails after creating folders", []obsidian.FileMove{{From: "a", To: "new/sub/x"}, {From: "b", To: "taken.md/b"}}, obsidian.ErrCodeVaultWrite},
+		}
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				vaultPath := t.TempDir()
+				writeVaultFiles(t, vaultPath, map[string]string{
+					"a.md":       "[[b]]",
+					"b.md":       "[[a]]",
+					"taken.md":   "",
+					"dir/one.md": "",
+				})
+				note := obsidian.Note{}
+
+				_, err := note.MoveFiles(vaultPath, test.moves)
+				assert.Error(t, err)
+				assert.Equal(t, test.code, obsidian.ErrorCode(err))
+				for _, p := range []string{"a.md", "b.md", "taken.md", "dir/one.md"} {
+					assert.FileExists(t, filepath.Join(vaultPath, filepath.FromSlash(p)))
+				}
+				assert.NoFileExists(t, filepath.Join(vaultPath, "x.md"))
+				assert.NoDirExists(t, filepath.Join(vaultPath, "new"))
+				content, _ := os.ReadFile(filepath.Join(vaultPath, "a.md"))
+				assert.Equal(t, "[[b]]", string(content))
+			})
+		}
+	})
+
+	t.Run("Folders left with only hidden files are removed", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"old/.DS_Store":         "",
+			"old/x.md":              "",
+			"old/sub/.DS_Store":     "",
+			"old/sub/y.md":          "",
+			"old/kept/.hidden/z.md": "",
+			"old/kept/w.md":         "",
+		})
+		note := obsidian.Note{}
+
+		_, err := note.MoveFiles(vaultPath, []obsidian.FileMove{{From: "old", To: "new"}})
+		assert.NoError(t, err)
+		assert.NoDirExists(t, filepath.Join(vaultPath, "old", "sub"))
+		assert.FileExists(t, filepath.Join(vaultPath, "old", "kept", ".hidden", "z.md"))
+		assert.FileExists(t, filepath.Join(vaultPath, "old", ".DS_Store"))
+		assert.FileExists(t, filepath.Join(vaultPath, "new", "kept", "w.md"))
+	})
+
+	t.Run("Missing source is reported as given", func(t *testing.T) {
+		note := obsidian.Note{}
+		_, err := note.MoveFiles(t.TempDir(), []obsidian.FileMove{{From: "img.png", To: "assets/img.png"}})
+		assert.Equal(t, obsidian.SourceDoesNotExistError+`: "img.png"`, err.Error())
+	})
+}
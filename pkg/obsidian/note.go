@@ -2,7 +2,6 @@ package obsidian
 
 import (
 	"bytes"
-	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -15,31 +14,57 @@ import (
 type Note struct {
 }
 
+// NoteMatch is a search hit within a note. LineNumber is 1-based, or 0 when
+// the note matched by name or by criteria other than its content.
+// MatchStart and MatchEnd are the byte offsets of the match within the note
+// file and are both 0 for line-0 matches.
 type NoteMatch struct {
-	FilePath   string
-	LineNumber int
-	MatchLine  string
+	FilePath   string  `json:"path"`
+	LineNumber int     `json:"line"`
+	MatchLine  string  `json:"snippet"`
+	MatchStart int     `json:"start"`
+	MatchEnd   int     `json:"end"`
+	Score      float64 `json:"score,omitempty"`
+}
+
+// FileMove is a file moved from one vault-relative path to another.
+type FileMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
 }
 
 type NoteManager interface {
 	Move(string, string) error
+	MoveFolder(string, string, string) ([]FileMove, error)
+	MoveFiles(string, []FileMove) ([]FileMove, error)
+	PlanAttachmentMoves(string, string, string) ([]FileMove, error)
+	MoveAttachments(string, string, string) ([]FileMove, error)
+	UnusedAttachments(string, string) ([]string, error)
 	Delete(string) error
 	UpdateLinks(string, string, string) error
 	GetContents(string, string) (string, error)
 	SetContents(string, string, string) error
 	GetNotesList(string) ([]string, error)
 	SearchNotesWithSnippets(string, string) ([]NoteMatch, error)
+	SearchNotesRanked(string, string) ([]NoteMatch, error)
+	SearchNotesQuery(string, string) ([]NoteMatch, error)
+	SearchNotesRegex(string, string, RegexSearchOptions) ([]NoteMatch, error)
 	FindBacklinks(string, string) ([]NoteMatch, error)
 }
 
+// Move moves the note at originalPath to newPath, adding the .md extension
+// to both unless originalPath is an attachment.
 func (m *Note) Move(originalPath string, newPath string) error {
 	o := AddMdSuffix(originalPath)
 	n := AddMdSuffix(newPath)
+	if IsAttachment(originalPath) {
+		o, n = originalPath, newPath
+	}
 
 	err := os.Rename(o, n)
 
 	if err != nil {
-		return errors.New(NoteDoesNotExistError)
+		return NewError(ErrCodeNoteNotFound, NoteDoesNotExistError)
 	}
 
 	message := fmt.Sprintf(`Moved note 
@@ -49,140 +74,251 @@ to %s`, o, n)
 	fmt.Println(message)
 	return nil
 }
+
+// MoveFolder moves the folder at the vault-relative path from, with every
+// note and attachment beneath it, to the vault-relative path to, creating
+// missing parent folders. Links to the moved files are then updated across
+// the vault in a single pass. It returns the files that moved, sorted by
+// their old path.
+func (m *Note) MoveFolder(vaultPath string, from string, to string) ([]FileMove, error) {
+	fromPath, err := ValidatePath(vaultPath, from)
+	if err != nil {
+		return nil, err
+	}
+	toPath, err := ValidatePath(vaultPath, to)
+	if err != nil {
+		return nil, err
+	}
+	absVault, err := filepath.Abs(vaultPath)
+	if err != nil {
+		return nil, NewError(ErrCodeVaultAccess, VaultAccessError)
+	}
+	fromRel, err := filepath.Rel(absVault, fromPath)
+	if err != nil {
+		return nil, NewError(ErrCodeVaultAccess, VaultAccessError)
+	}
+	toRel, err := filepath.Rel(absVault, toPath)
+	if err != nil {
+		return nil, NewError(ErrCodeVaultAccess, VaultAccessError)
+	}
+	fromRel, toRel = filepath.ToSlash(fromRel), filepath.ToSlash(toRel)
+
+	if fromRel == "." || toRel == "." {
+		return nil, NewError(ErrCodeInvalidArgument, "cannot move the vault folder itself")
+	}
+	if info, err := os.Stat(fromPath); err != nil || !info.IsDir() {
+		return nil, NewError(ErrCodeNoteNotFound, FolderDoesNotExistError)
+	}
+	if toRel == fromRel || strings.HasPrefix(toRel, fromRel+"/") {
+		return nil, NewError(ErrCodeInvalidArgument, fmt.Sprintf("cannot move folder %q into itself", fromRel))
+	}
+	if _, err := os.Stat(toPath); err == nil {
+		return nil, NewError(ErrCodeInvalidArgument, fmt.Sprintf("%q already exists in vault", toRel))
+	}
+
+	paths, err := listVaultFiles(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	moves := []FileMove{}
+	moved := make(map[string]string)
+	for _, p := range paths {
+		if strings.HasPrefix(p, fromRel+"/") {
+			newPath := toRel + strings.TrimPrefix(p, fromRel)
+			moves = append(moves, FileMove{From: p, To: newPath})
+			moved[p] = newPath
+		}
+	}
+	sort.Slice(moves, func(i, j int) bool { return moves[i].From < moves[j].From })
+
+	if err := os.MkdirAll(filepath.Dir(toPath), 0755); err != nil {
+		return nil, NewError(ErrCodeVaultWrite, VaultWriteError)
+	}
+	if err := os.Rename(fromPath, toPath); err != nil {
+		return nil, NewError(ErrCodeVaultWrite, VaultWriteError)
+	}
+
+	message := fmt.Sprintf(`Moved folder
+from %s
+to %s`, fromPath, toPath)
+
+	fmt.Println(message)
+
+	if len(moved) == 0 {
+		return moves, nil
+	}
+	if err := updateMovedLinks(vaultPath, moved, linkingFilter(vaultPath, moved)); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+// Delete removes the note at path, or the attachment when path is one.
 func (m *Note) Delete(path string) error {
-	note := AddMdSuffix(path)
+	note := noteFilePath(path)
 	err := os.Remove(note)
 	if err != nil {
-		return errors.New(NoteDoesNotExistError)
+		return NewError(ErrCodeNoteNotFound, NoteDoesNotExistError)
 	}
 	fmt.Println("Deleted note: ", note)
 	return nil
 }
 
 func (m *Note) GetContents(vaultPath string, noteName string) (string, error) {
-	note := AddMdSuffix(noteName)
-
-	var notePath string
-	err := filepath.WalkDir(vaultPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err // Continue to the next path if there's an error
-		}
-		if d.IsDir() {
-			return nil // Skip directories
-		}
-
-		// Check for full path match first
-		relPath, err := filepath.Rel(vaultPath, path)
-		if err == nil && relPath == note {
-			notePath = path
-			return filepath.SkipDir
-		}
-
-		// Fall back to basename match for backward compatibility
-		if filepath.Base(path) == note {
-			notePath = path
-			return filepath.SkipDir
-		}
-		return nil
-	})
-
-	if err != nil || notePath == "" {
-		return "", errors.New(NoteDoesNotExistError)
+	notePath, err := findNotePath(vaultPath, noteName)
+	if err != nil {
+		return "", err
 	}
 
 	file, err := os.Open(notePath)
 	if err != nil {
-		return "", errors.New(VaultReadError)
+		return "", NewError(ErrCodeVaultRead, VaultReadError)
 	}
 	defer file.Close()
 
 	content, err := io.ReadAll(file)
 	if err != nil {
-		return "", errors.New(VaultReadError)
+		return "", NewError(ErrCodeVaultRead, VaultReadError)
 	}
 
 	return string(content), nil
 }
 
 func (m *Note) SetContents(vaultPath string, noteName string, content string) error {
-	note := AddMdSuffix(noteName)
-
-	var notePath string
-	err := filepath.WalkDir(vaultPath, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if d.IsDir() {
-			return nil
-		}
-
-		// Check for full path match first
-		relPath, err := filepath.Rel(vaultPath, path)
-		if err == nil && relPath == note {
-			notePath = path
-			return filepath.SkipDir
-		}
-
-		// Fall back to basename match for backward compatibility
-		if filepath.Base(path) == note {
-			notePath = path
-			return filepath.SkipDir
-		}
-		return nil
-	})
-
-	if err != nil || notePath == "" {
-		return errors.New(NoteDoesNotExistError)
+	notePath, err := findNotePath(vaultPath, noteName)
+	if err != nil {
+		return err
 	}
 
 	err = os.WriteFile(notePath, []byte(content), 0644)
 	if err != nil {
-		return errors.New(VaultWriteError)
+		return NewError(ErrCodeVaultWrite, VaultWriteError)
 	}
 
 	return nil
 }
 
-func (m *Note) UpdateLinks(vaultPath string, oldNoteName string, newNoteName string) error {
-	err := filepath.Walk(vaultPath, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return errors.New(VaultAccessError)
-		}
+// writeNotesAtomically replaces the contents of several notes, keyed by
+// vault-relative path, as a single unit. Every new version is first written to
+// a temporary file next to its note; only when all of them are on disk are
+// they renamed into place. If a rename fails, notes already replaced are
+// restored to their original contents.
+func writeNotesAtomically(vaultPath string, contents map[string]string) error {
+	type pendingWrite struct {
+		path     string
+		tmpPath  string
+		original []byte
+		mode     os.FileMode
+	}
 
-		if ShouldSkipDirectoryOrFile(info) {
-			return nil
-		}
+	relPaths := make([]string, 0, len(contents))
+	for relPath := range contents {
+		relPaths = append(relPaths, relPath)
+	}
+	sort.Strings(relPaths)
 
-		originalContent, err := os.ReadFile(path)
+	pending := make([]pendingWrite, 0, len(relPaths))
+	removeTemps := func() {
+		for _, w := range pending {
+			os.Remove(w.tmpPath)
+		}
+	}
+	for _, relPath := range relPaths {
+		notePath := filepath.Join(vaultPath, filepath.FromSlash(relPath))
+		info, err := os.Stat(notePath)
 		if err != nil {
-			return errors.New(VaultReadError)
+			removeTemps()
+			return NewError(ErrCodeVaultWrite, VaultWriteError)
 		}
+		original, err := os.ReadFile(notePath)
+		if err != nil {
+			removeTemps()
+			return NewError(ErrCodeVaultRead, VaultReadError)
+		}
+		w := pendingWrite{path: notePath, tmpPath: notePath + ".notesmd-tmp", original: original, mode: info.Mode().Perm()}
+		if err := os.WriteFile(w.tmpPath, []byte(contents[relPath]), w.mode); err != nil {
+			os.Remove(w.tmpPath)
+			removeTemps()
+			return NewError(ErrCodeVaultWrite, VaultWriteError)
+		}
+		pending = append(pending, w)
+	}
 
-		replacements := GenerateLinkReplacements(oldNoteName, newNoteName)
-		updatedContent := ReplaceContent(originalContent, replacements)
-
-		if bytes.Equal(originalContent, updatedContent) {
-			return nil
+	for i, w := range pending {
+		if err := os.Rename(w.tmpPath, w.path); err != nil {
+			for _, done := range pending[:i] {
+				_ = os.WriteFile(done.path, done.original, done.mode)
+			}
+			for _, rest := range pending[i:] {
+				os.Remove(rest.tmpPath)
+			}
+			return NewError(ErrCodeVaultWrite, VaultWriteError)
 		}
+	}
+	return nil
+}
 
-		err = os.WriteFile(path, updatedContent, info.Mode())
+// findNotePath resolves a note name or vault-relative path to an absolute
+// path: the note at exactly that path, or else the first note with that file
+// name in walk order. The vault index is used when there is one, brought up
+// to date like for list and search; otherwise the vault is walked.
+func findNotePath(vaultPath string, noteName string) (string, error) {
+	var relPath string
+	var ok bool
+	if idx := freshIndex(vaultPath); idx != nil {
+		relPath, ok = idx.Lookup(noteName)
+	} else {
+		files, err := scanVaultNotes(vaultPath)
 		if err != nil {
-			return errors.New(VaultWriteError)
+			return "", NewError(ErrCodeNoteNotFound, NoteDoesNotExistError)
 		}
-		return nil
-	})
-	if err != nil {
-		return err
+		paths := make([]string, 0, len(files))
+		for p := range files {
+			paths = append(paths, p)
+		}
+		relPath, ok = resolveNoteName(noteName, paths)
 	}
-	return nil
+	if !ok {
+		return "", NewError(ErrCodeNoteNotFound, NoteDoesNotExistError)
+	}
+	return filepath.Join(vaultPath, filepath.FromSlash(relPath)), nil
+}
+
+// UpdateLinks rewrites the links to a note that moved from oldNoteName to
+// newNoteName, both relative to the vault, so they point at its new path.
+// Each link is resolved from the note it is in, so only links that really
+// pointed at the moved note change, each keeping its style. Relative links
+// in the moved note itself are updated for its new folder. When newNoteName
+// is an attachment, the links to that attachment are updated instead.
+func (m *Note) UpdateLinks(vaultPath string, oldNoteName string, newNoteName string) error {
+	oldPath := AddMdSuffix(normalizePathSeparators(oldNoteName))
+	newPath := AddMdSuffix(normalizePathSeparators(newNoteName))
+	if IsAttachment(filepath.Join(vaultPath, newNoteName)) {
+		oldPath = normalizePathSeparators(oldNoteName)
+		newPath = normalizePathSeparators(newNoteName)
+	}
+	moved := map[string]string{oldPath: newPath}
+
+	return updateMovedLinks(vaultPath, moved, linkingFilter(vaultPath, moved))
 }
 
 func (m *Note) GetNotesList(vaultPath string) ([]string, error) {
+	if idx := freshIndex(vaultPath); idx != nil {
+		notes := idx.NotePaths()
+		for i, relPath := range notes {
+			notes[i] = filepath.FromSlash(relPath)
+		}
+		return notes, nil
+	}
+
 	var notes []string
 	err := filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if skip, err := skipHidden(vaultPath, path, d); skip {
+			return err
+		}
 		if !d.IsDir() && strings.HasSuffix(d.Name(), ".md") {
 			relPath, err := filepath.Rel(vaultPath, path)
 			if err != nil {
@@ -202,69 +338,33 @@ func (m *Note) SearchNotesWithSnippets(vaultPath string, query string) ([]NoteMa
 	var matches []NoteMatch
 	queryLower := strings.ToLower(query)
 
+	if idx := freshIndex(vaultPath); idx != nil {
+		for _, relPath := range idx.NotePaths() {
+			path := filepath.Join(vaultPath, filepath.FromSlash(relPath))
+			fileMatches := searchNoteSnippets(path, filepath.FromSlash(relPath), idx.Notes[relPath].Size, query, queryLower)
+			matches = append(matches, fileMatches...)
+		}
+		return matches, nil
+	}
+
 	err := filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
 		}
+		if skip, err := skipHidden(vaultPath, path, d); skip {
+			return err
+		}
 		if !d.IsDir() && strings.HasSuffix(d.Name(), ".md") {
 			relPath, err := filepath.Rel(vaultPath, path)
 			if err != nil {
 				return err
 			}
 
-			fileNameMatches := strings.Contains(strings.ToLower(relPath), queryLower)
-			var hasContentMatch bool
-
-			// Check file size to avoid reading very large files (>10MB)
-			if info, err := d.Info(); err == nil && info.Size() < 10*1024*1024 {
-				content, err := os.ReadFile(path)
-				if err == nil {
-					lines := strings.Split(string(content), "\n")
-					for lineNum, line := range lines {
-						if strings.Contains(strings.ToLower(line), queryLower) {
-							hasContentMatch = true
-							matchLine := strings.TrimSpace(line)
-							if len(matchLine) > 80 {
-								// Find the query position and center around it
-								queryPos := strings.Index(strings.ToLower(matchLine), queryLower)
-								if queryPos != -1 {
-									start := queryPos - 20
-									end := queryPos + len(query) + 20
-									if start < 0 {
-										start = 0
-									}
-									if end > len(matchLine) {
-										end = len(matchLine)
-									}
-									if start > 0 {
-										matchLine = "..." + matchLine[start:]
-									}
-									if end < len(strings.TrimSpace(line)) {
-										matchLine = matchLine[:end-start] + "..."
-									}
-								} else {
-									matchLine = matchLine[:80] + "..."
-								}
-							}
-
-							matches = append(matches, NoteMatch{
-								FilePath:   relPath,
-								LineNumber: lineNum + 1,
-								MatchLine:  matchLine,
-							})
-						}
-					}
-				}
-			}
-
-			// Only add filename match if there are no content matches
-			if fileNameMatches && !hasContentMatch {
-				matches = append(matches, NoteMatch{
-					FilePath:   relPath,
-					LineNumber: 0,
-					MatchLine:  fmt.Sprintf("(filename match: %s)", filepath.Base(relPath)),
-				})
+			size := int64(maxFileSizeBytes)
+			if info, err := d.Info(); err == nil {
+				size = info.Size()
 			}
+			matches = append(matches, searchNoteSnippets(path, relPath, size, query, queryLower)...)
 		}
 		return nil
 	})
@@ -275,6 +375,92 @@ func (m *Note) SearchNotesWithSnippets(vaultPath string, query string) ([]NoteMa
 	return matches, nil
 }
 
+// searchNoteSnippets returns the lines of a single note containing the query,
+// or a filename match if only the note's path contains it.
+func searchNoteSnippets(path, relPath string, size int64, query, queryLower string) []NoteMatch {
+	var matches []NoteMatch
+	fileNameMatches := strings.Contains(strings.ToLower(relPath), queryLower)
+	var hasContentMatch bool
+
+	// Check file size to avoid reading very large files (>10MB)
+	if size < maxFileSizeBytes {
+		content, err := os.ReadFile(path)
+		if err == nil {
+			lines := strings.Split(string(content), "\n")
+			offset := 0
+			for lineNum, line := range lines {
+				if pos := indexFold(line, queryLower); pos != -1 {
+					hasContentMatch = true
+					matches = append(matches, NoteMatch{
+						FilePath:   relPath,
+						LineNumber: lineNum + 1,
+						MatchLine:  snippetAround(line, queryLower, len(query)),
+						MatchStart: offset + pos,
+						MatchEnd:   offset + pos + len(query),
+					})
+				}
+				offset += len(line) + 1
+			}
+		}
+	}
+
+	// Only add filename match if there are no content matches
+	if fileNameMatches && !hasContentMatch {
+		matches = append(matches, NoteMatch{
+			FilePath:   relPath,
+			LineNumber: 0,
+			MatchLine:  fmt.Sprintf("(filename match: %s)", filepath.Base(relPath)),
+		})
+	}
+	return matches
+}
+
+// indexFold returns the byte offset in s of the first case-insensitive
+// occurrence of substrLower, or -1.
+func indexFold(s, substrLower string) int {
+	lower := strings.ToLower(s)
+	pos := strings.Index(lower, substrLower)
+	if pos == -1 || len(lower) == len(s) {
+		return pos
+	}
+	// Lowercasing changed byte lengths, so offsets in lower don't map onto s.
+	for i := range s {
+		if len(s)-i >= len(substrLower) && strings.EqualFold(s[i:i+len(substrLower)], substrLower) {
+			return i
+		}
+	}
+	return pos
+}
+
+// snippetAround trims a matching line for display, centring long lines on the
+// first occurrence of the (lowercased) query.
+func snippetAround(line, queryLower string, queryLen int) string {
+	matchLine := strings.TrimSpace(line)
+	if len(matchLine) > 80 {
+		// Find the query position and center around it
+		queryPos := strings.Index(strings.ToLower(matchLine), queryLower)
+		if queryPos != -1 {
+			start := queryPos - 20
+			end := queryPos + queryLen + 20
+			if start < 0 {
+				start = 0
+			}
+			if end > len(matchLine) {
+				end = len(matchLine)
+			}
+			if start > 0 {
+				matchLine = "..." + matchLine[start:]
+			}
+			if end < len(strings.TrimSpace(line)) {
+				matchLine = matchLine[:end-start] + "..."
+			}
+		} else {
+			matchLine = matchLine[:80] + "..."
+		}
+	}
+	return matchLine
+}
+
 const maxFileSizeBytes = 10 * 1024 * 1024 // 10MB
 
 // containsAnyPattern checks if content contains any of the patterns (case-insensitive).
@@ -291,9 +477,11 @@ func containsAnyPattern(contentLower []byte, patterns [][]byte) bool {
 func findMatchingLines(content []byte, patternsLower [][]byte) []NoteMatch {
 	var matches []NoteMatch
 	lineNum := 0
+	offset := 0
 
 	for len(content) > 0 {
 		lineNum++
+		lineStart := offset
 
 		// Find end of line
 		idx := bytes.IndexByte(content, '\n')
@@ -305,14 +493,18 @@ func findMatchingLines(content []byte, patternsLower [][]byte) []NoteMatch {
 			line = content[:idx]
 			content = content[idx+1:]
 		}
+		offset += len(line) + 1
 
 		// Check if line matches any pattern
 		lineLower := bytes.ToLower(line)
 		for _, pattern := range patternsLower {
 			if bytes.Contains(lineLower, pattern) {
+				pos := indexFold(string(line), string(pattern))
 				matches = append(matches, NoteMatch{
 					LineNumber: lineNum,
 					MatchLine:  string(bytes.TrimSpace(line)),
+					MatchStart: lineStart + pos,
+					MatchEnd:   lineStart + pos + len(pattern),
 				})
 				break
 			}
@@ -335,63 +527,88 @@ func (m *Note) FindBacklinks(vaultPath, noteName string) ([]NoteMatch, error) {
 	var matches []NoteMatch
 	fileModTimes := make(map[string]int64)
 
-	err := filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return err
+	collect := func(path, relPath string, info fs.FileInfo) {
+		// Skip the note itself (normalize for comparison)
+		if RemoveMdSuffix(normalizePathSeparators(relPath)) == noteName {
+			return
 		}
-		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
-			return nil
+		fileMatches := findBacklinkLines(path, relPath, info, patternsLower)
+		if len(fileMatches) > 0 {
+			fileModTimes[relPath] = info.ModTime().UnixNano()
+			matches = append(matches, fileMatches...)
 		}
+	}
 
-		relPath, err := filepath.Rel(vaultPath, path)
-		if err != nil {
-			return err
+	// With an index only notes that link to noteName need to be read.
+	if idx := freshIndex(vaultPath); idx != nil {
+		for _, relPath := range idx.LinkingNotes(noteName) {
+			path := filepath.Join(vaultPath, filepath.FromSlash(relPath))
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			collect(path, filepath.FromSlash(relPath), info)
 		}
+	} else {
+		err := filepath.WalkDir(vaultPath, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if skip, err := skipHidden(vaultPath, path, d); skip {
+				return err
+			}
+			if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+				return nil
+			}
 
-		// Skip the note itself (normalize for comparison)
-		if RemoveMdSuffix(normalizePathSeparators(relPath)) == noteName {
-			return nil
-		}
+			relPath, err := filepath.Rel(vaultPath, path)
+			if err != nil {
+				return err
+			}
 
-		info, err := d.Info()
-		if err != nil {
-			return nil
-		}
-		if info.Size() > maxFileSizeBytes {
-			fmt.Fprintf(os.Stderr, "Skipping file %s: size %d bytes exceeds limit %d bytes\n", relPath, info.Size(), maxFileSizeBytes)
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			collect(path, relPath, info)
 			return nil
-		}
+		})
 
-		content, err := os.ReadFile(path)
 		if err != nil {
-			return nil
+			return nil, err
 		}
+	}
 
-		// Quick check: skip file if it doesn't contain any pattern
-		contentLower := bytes.ToLower(content)
-		if !containsAnyPattern(contentLower, patternsLower) {
-			return nil
-		}
+	sort.Slice(matches, func(i, j int) bool {
+		return fileModTimes[matches[i].FilePath] > fileModTimes[matches[j].FilePath]
+	})
 
-		// Find matching lines
-		modTime := info.ModTime().UnixNano()
-		fileMatches := findMatchingLines(content, patternsLower)
-		for i := range fileMatches {
-			fileMatches[i].FilePath = relPath
-			fileModTimes[relPath] = modTime
-		}
-		matches = append(matches, fileMatches...)
+	return matches, nil
+}
 
+// findBacklinkLines returns the lines of a note that contain any of the
+// lowercased link patterns.
+func findBacklinkLines(path, relPath string, info fs.FileInfo, patternsLower [][]byte) []NoteMatch {
+	if info.Size() > maxFileSizeBytes {
+		fmt.Fprintf(os.Stderr, "Skipping file %s: size %d bytes exceeds limit %d bytes\n", relPath, info.Size(), maxFileSizeBytes)
 		return nil
-	})
+	}
 
+	content, err := os.ReadFile(path)
 	if err != nil {
-		return nil, err
+		return nil
 	}
 
-	sort.Slice(matches, func(i, j int) bool {
-		return fileModTimes[matches[i].FilePath] > fileModTimes[matches[j].FilePath]
-	})
+	// Quick check: skip file if it doesn't contain any pattern
+	contentLower := bytes.ToLower(content)
+	if !containsAnyPattern(contentLower, patternsLower) {
+		return nil
+	}
 
-	return matches, nil
+	// Find matching lines
+	fileMatches := findMatchingLines(content, patternsLower)
+	for i := range fileMatches {
+		fileMatches[i].FilePath = relPath
+	}
+	return fileMatches
 }
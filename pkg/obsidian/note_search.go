@@ -0,0 +1,373 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+	"github.com/Yakitrak/notesmd-cli/pkg/search"
+)
+
+// noteFile is a note's vault-relative path and contents.
+type noteFile struct {
+	RelPath string
+	Content string
+}
+
+// readNoteFiles reads every note in the vault, skipping files larger than
+// maxFileSizeBytes. Notes come from the index when there is one.
+func (m *Note) readNoteFiles(vaultPath string) ([]noteFile, error) {
+	notes, err := m.GetNotesList(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]noteFile, 0, len(notes))
+	for _, relPath := range notes {
+		fullPath := filepath.Join(vaultPath, relPath)
+		info, err := os.Stat(fullPath)
+		if err != nil || info.Size() > maxFileSizeBytes {
+			continue
+		}
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			continue
+		}
+		files = append(files, noteFile{RelPath: relPath, Content: string(content)})
+	}
+	return files, nil
+}
+
+// SearchNotesRanked runs a full-text query (see search.Query for the syntax)
+// over every note and returns the matching lines, grouped by note with the
+// most relevant notes first. Each match carries its note's relevance score.
+func (m *Note) SearchNotesRanked(vaultPath string, query string) ([]NoteMatch, error) {
+	parsed, err := search.ParseQuery(query)
+	if err != nil {
+		return nil, WrapError(ErrCodeInvalidQuery, err.Error(), err)
+	}
+
+	files, err := m.readNoteFiles(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]search.Document, len(files))
+	contents := make(map[string]string, len(files))
+	for i, f := range files {
+		docs[i] = search.Document{
+			ID:    f.RelPath,
+			Title: RemoveMdSuffix(path.Base(normalizePathSeparators(f.RelPath))),
+			Body:  f.Content,
+		}
+		contents[f.RelPath] = f.Content
+	}
+
+	var matches []NoteMatch
+	for _, result := range search.NewEngine(docs).Search(parsed) {
+		matches = append(matches, rankedNoteMatches(result, contents[result.ID])...)
+	}
+	return matches, nil
+}
+
+// rankedNoteMatches turns a search result into one NoteMatch per line with a
+// hit. Notes matched only by their name, or only through negated terms, get a
+// single line-0 entry.
+func rankedNoteMatches(result search.Result, content string) []NoteMatch {
+	var matches []NoteMatch
+	lineNum := 1
+	lineStart := 0
+	lastLine := 0
+	for _, hit := range result.Hits {
+		for {
+			next := strings.IndexByte(content[lineStart:], '\n')
+			if next == -1 || lineStart+next >= hit.Start {
+				break
+			}
+			lineStart += next + 1
+			lineNum++
+		}
+		if lineNum == lastLine {
+			continue
+		}
+		lastLine = lineNum
+
+		lineEnd := len(content)
+		if next := strings.IndexByte(content[lineStart:], '\n'); next != -1 {
+			lineEnd = lineStart + next
+		}
+		term := strings.ToLower(content[hit.Start:hit.End])
+		matches = append(matches, NoteMatch{
+			FilePath:   result.ID,
+			LineNumber: lineNum,
+			MatchLine:  snippetAround(content[lineStart:lineEnd], term, len(term)),
+			MatchStart: hit.Start,
+			MatchEnd:   hit.End,
+			Score:      result.Score,
+		})
+	}
+
+	if len(matches) > 0 {
+		return matches
+	}
+
+	matchLine := fmt.Sprintf("(filename match: %s)", filepath.Base(result.ID))
+	if len(result.TitleHits) == 0 {
+		matchLine = fmt.Sprintf("(matches query: %s)", filepath.Base(result.ID))
+	}
+	return []NoteMatch{{FilePath: result.ID, LineNumber: 0, MatchLine: matchLine, Score: result.Score}}
+}
+
+// SearchNotesQuery runs a query in Obsidian's search syntax (see
+// search.ObsidianQuery) over every note and returns the matching lines in
+// path order. Notes matched only through path:, file: or tag: operators get a
+// single line-0 entry.
+func (m *Note) SearchNotesQuery(vaultPath string, query string) ([]NoteMatch, error) {
+	parsed, err := search.ParseObsidianQuery(query)
+	if err != nil {
+		return nil, WrapError(ErrCodeInvalidQuery, err.Error(), err)
+	}
+
+	files, err := m.readNoteFiles(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []NoteMatch
+	for _, f := range files {
+		note := &search.QueryNote{
+			Path:     normalizePathSeparators(f.RelPath),
+			Content:  f.Content,
+			Tags:     noteTags(f.Content),
+			Sections: splitSections(f.Content),
+		}
+		if !parsed.Match(note) {
+			continue
+		}
+
+		lineMatches := parsed.MatchLines(note)
+		if len(lineMatches) == 0 {
+			matches = append(matches, NoteMatch{
+				FilePath:   f.RelPath,
+				LineNumber: 0,
+				MatchLine:  fmt.Sprintf("(matches query: %s)", filepath.Base(f.RelPath)),
+			})
+			continue
+		}
+		lines := strings.Split(f.Content, "\n")
+		offset, offsetLine := 0, 1
+		for _, lm := range lineMatches {
+			for ; offsetLine < lm.Line; offsetLine++ {
+				offset += len(lines[offsetLine-1]) + 1
+			}
+			line := lines[lm.Line-1]
+			matched := strings.ToLower(line[lm.Start:lm.End])
+			matches = append(matches, NoteMatch{
+				FilePath:   f.RelPath,
+				LineNumber: lm.Line,
+				MatchLine:  snippetAround(line, matched, len(matched)),
+				MatchStart: offset + lm.Start,
+				MatchEnd:   offset + lm.End,
+			})
+		}
+	}
+	return matches, nil
+}
+
+// noteTags returns a note's frontmatter and inline tags.
+func noteTags(content string) []string {
+	var tags []string
+	if frontmatter.HasFrontmatter(content) {
+		if fm, _, err := frontmatter.Parse(content); err == nil {
+			tags = FrontmatterTags(fm)
+		}
+	}
+	return append(tags, ExtractInlineTags(content)...)
+}
+
+// splitSections splits content at its headings. Each section starts with its
+// heading line; text before the first heading forms its own section.
+func splitSections(content string) []string {
+	lines := strings.Split(content, "\n")
+	var sections []string
+	start := 0
+	for _, h := range ExtractHeadings(content) {
+		if h.Line-1 > start {
+			sections = append(sections, strings.Join(lines[start:h.Line-1], "\n"))
+		}
+		start = h.Line - 1
+	}
+	return append(sections, strings.Join(lines[start:], "\n"))
+}
+
+// RegexSearchOptions controls how SearchNotesRegex interprets its pattern.
+type RegexSearchOptions struct {
+	// CaseSensitive disables the default case-insensitive matching.
+	CaseSensitive bool
+	// Multiline matches the pattern against whole notes instead of single
+	// lines, so matches may span lines. "." then also matches newlines while
+	// "^" and "$" still anchor at line boundaries.
+	Multiline bool
+}
+
+// Capture groups, or whole matches for patterns without groups, are wrapped
+// in these markers in regex search snippets.
+const (
+	HighlightStart = "«"
+	HighlightEnd   = "»"
+)
+
+// SearchNotesRegex finds matches of a Go (RE2) regular expression across the
+// vault, returning one entry per matching line (or per match in multi-line
+// mode) with the matched text highlighted. Notes whose path matches but whose
+// content does not get a single line-0 entry.
+func (m *Note) SearchNotesRegex(vaultPath string, pattern string, options RegexSearchOptions) ([]NoteMatch, error) {
+	flags := ""
+	if !options.CaseSensitive {
+		flags += "i"
+	}
+	if options.Multiline {
+		flags += "sm"
+	}
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, WrapError(ErrCodeInvalidQuery, fmt.Sprintf("invalid regular expression: %v", err), err)
+	}
+
+	files, err := m.readNoteFiles(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []NoteMatch
+	for _, f := range files {
+		var fileMatches []NoteMatch
+		if options.Multiline {
+			fileMatches = regexMatchesInContent(re, f.RelPath, f.Content)
+		} else {
+			offset := 0
+			for i, line := range strings.Split(f.Content, "\n") {
+				locs := nonEmptyMatches(re.FindAllStringSubmatchIndex(line, -1))
+				if len(locs) > 0 {
+					fileMatches = append(fileMatches, NoteMatch{
+						FilePath:   f.RelPath,
+						LineNumber: i + 1,
+						MatchLine:  regexSnippet(line, 0, len(line), locs),
+						MatchStart: offset + locs[0][0],
+						MatchEnd:   offset + locs[0][1],
+					})
+				}
+				offset += len(line) + 1
+			}
+		}
+
+		if len(fileMatches) == 0 && re.MatchString(f.RelPath) {
+			fileMatches = append(fileMatches, NoteMatch{
+				FilePath:   f.RelPath,
+				LineNumber: 0,
+				MatchLine:  fmt.Sprintf("(filename match: %s)", filepath.Base(f.RelPath)),
+			})
+		}
+		matches = append(matches, fileMatches...)
+	}
+	return matches, nil
+}
+
+// regexMatchesInContent returns one match per regex match in content, each
+// reported on the line where it starts.
+func regexMatchesInContent(re *regexp.Regexp, relPath, content string) []NoteMatch {
+	var matches []NoteMatch
+	for _, loc := range nonEmptyMatches(re.FindAllStringSubmatchIndex(content, -1)) {
+		lineStart := strings.LastIndexByte(content[:loc[0]], '\n') + 1
+		lineEnd := len(content)
+		if next := strings.IndexByte(content[loc[1]:], '\n'); next != -1 {
+			lineEnd = loc[1] + next
+		}
+		matches = append(matches, NoteMatch{
+			FilePath:   relPath,
+			LineNumber: strings.Count(content[:loc[0]], "\n") + 1,
+			MatchLine:  regexSnippet(content, lineStart, lineEnd, [][]int{loc}),
+			MatchStart: loc[0],
+			MatchEnd:   loc[1],
+		})
+	}
+	return matches
+}
+
+// nonEmptyMatches drops zero-length matches, which patterns such as "x*"
+// produce at every position.
+func nonEmptyMatches(locs [][]int) [][]int {
+	var kept [][]int
+	for _, loc := range locs {
+		if loc[1] > loc[0] {
+			kept = append(kept, loc)
+		}
+	}
+	return kept
+}
+
+// regexSnippet renders text[start:end] with the capture groups of each match
+// in locs highlighted, trimming long snippets around the first match. Line
+// breaks inside multi-line matches are shown as "⏎".
+func regexSnippet(text string, start, end int, locs [][]int) string {
+	markers := make(map[int]string)
+	for _, loc := range locs {
+		spans := loc[2:]
+		if len(spans) == 0 {
+			spans = loc[:2]
+		}
+		for i := 0; i+1 < len(spans); i += 2 {
+			if spans[i] < 0 || spans[i+1] <= spans[i] {
+				continue
+			}
+			markers[spans[i]] += HighlightStart
+			markers[spans[i+1]] = HighlightEnd + markers[spans[i+1]]
+		}
+	}
+
+	// Trim context before the first match and after the last one.
+	const context = 20
+	from, to := start, end
+	if end-start > 80 {
+		if locs[0][0]-context > from {
+			from = runeStart(text, locs[0][0]-context)
+		}
+		if last := locs[len(locs)-1][1]; last+context < to {
+			to = runeStart(text, last+context)
+		}
+	}
+
+	var b strings.Builder
+	if from > start {
+		b.WriteString("...")
+	}
+	for i := from; i < to; i++ {
+		b.WriteString(markers[i])
+		if text[i] == '\n' {
+			b.WriteString(" ⏎ ")
+			continue
+		}
+		b.WriteByte(text[i])
+	}
+	b.WriteString(markers[to])
+	if to < end {
+		b.WriteString("...")
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// runeStart moves i back to the start of the UTF-8 sequence containing it.
+func runeStart(text string, i int) int {
+	for i > 0 && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,186 @@
+package obsidian_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchNotesRanked(t *testing.T) {
+	t.Run("Orders notes by relevance with matching lines", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"once.md":  "intro\nThe budget is mentioned once among many other words here",
+			"often.md": "budget\nbudget review",
+			"none.md":  "nothing relevant",
+		})
+
+		note := obsidian.Note{}
+		matches, err := note.SearchNotesRanked(vaultPath, "budget")
+		assert.NoError(t, err)
+		assert.Len(t, matches, 3)
+
+		assert.Equal(t, "often.md", matches[0].FilePath)
+		assert.Equal(t, 1, matches[0].LineNumber)
+		assert.Equal(t, "often.md", matches[1].FilePath)
+		assert.Equal(t, 2, matches[1].LineNumber)
+		assert.Equal(t, "budget review", matches[1].MatchLine)
+		assert.Equal(t, "once.md", matches[2].FilePath)
+		assert.Equal(t, 2, matches[2].LineNumber)
+		assert.Greater(t, matches[0].Score, matches[2].Score)
+	})
+
+	t.Run("Filename-only matches use line 0", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"Roadmap.md": "content"})
+
+		note := obsidian.Note{}
+		matches, err := note.SearchNotesRanked(vaultPath, "roadmap")
+		assert.NoError(t, err)
+		assert.Equal(t, 1, len(matches))
+		assert.Equal(t, 0, matches[0].LineNumber)
+		assert.Equal(t, "(filename match: Roadmap.md)", matches[0].MatchLine)
+	})
+
+	t.Run("Supports boolean operators", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a.md": "apples and pears",
+			"b.md": "apples only",
+		})
+
+		note := obsidian.Note{}
+		matches, err := note.SearchNotesRanked(vaultPath, "apples -pears")
+		assert.NoError(t, err)
+		assert.Len(t, matches, 1)
+		assert.Equal(t, "b.md", matches[0].FilePath)
+	})
+
+	t.Run("Invalid query returns error", func(t *testing.T) {
+		note := obsidian.Note{}
+		_, err := note.SearchNotesRanked(t.TempDir(), `"open`)
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchNotesQuery(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"Projects/plan.md": "---\ntags: [work]\n---\n# Tasks\n- [ ] draft budget #todo\n\n# Notes\nbudget approved",
+		"Projects/old.md":  "budget archived",
+		"daily.md":         "budget #todo",
+	})
+	note := obsidian.Note{}
+
+	t.Run("Combines operators", func(t *testing.T) {
+		matches, err := note.SearchNotesQuery(vaultPath, "path:Projects tag:#todo")
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{
+			{FilePath: "Projects/plan.md", LineNumber: 5, MatchLine: "- [ ] draft budget #todo", MatchStart: 48, MatchEnd: 53},
+		}, matches)
+	})
+
+	t.Run("Frontmatter tags match", func(t *testing.T) {
+		matches, err := note.SearchNotesQuery(vaultPath, "tag:work")
+		assert.NoError(t, err)
+		assert.Len(t, matches, 1)
+		assert.Equal(t, "Projects/plan.md", matches[0].FilePath)
+		assert.Equal(t, 0, matches[0].LineNumber)
+		assert.Equal(t, "(matches query: plan.md)", matches[0].MatchLine)
+	})
+
+	t.Run("Sections are split at headings", func(t *testing.T) {
+		matches, err := note.SearchNotesQuery(vaultPath, "section:(draft approved)")
+		assert.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("Excludes and reports matching lines", func(t *testing.T) {
+		matches, err := note.SearchNotesQuery(vaultPath, "budget -file:plan")
+		assert.NoError(t, err)
+		assert.Len(t, matches, 2)
+		assert.Equal(t, "Projects/old.md", matches[0].FilePath)
+		assert.Equal(t, 1, matches[0].LineNumber)
+		assert.Equal(t, "daily.md", matches[1].FilePath)
+	})
+
+	t.Run("Text whose length changes when lowercased", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": "ȺȺȺȺ foo\n"})
+		matches, err := note.SearchNotesQuery(vaultPath, "foo")
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{
+			{FilePath: "a.md", LineNumber: 1, MatchLine: "ȺȺȺȺ foo", MatchStart: 9, MatchEnd: 12},
+		}, matches)
+	})
+
+	t.Run("Invalid query returns error", func(t *testing.T) {
+		_, err := note.SearchNotesQuery(vaultPath, "line:(open")
+		assert.Error(t, err)
+	})
+}
+
+func TestSearchNotesRegex(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"tasks.md":          "TODO(alice) review\ntodo(bob) later\nnothing",
+		"dates.md":          "Met on 2024-05-01 and 2024-06-12",
+		"2024-05 review.md": "no dates inside",
+		"multi.md":          "begin block\nmiddle\nend block",
+	})
+	note := obsidian.Note{}
+
+	t.Run("Case-insensitive by default with group highlighting", func(t *testing.T) {
+		matches, err := note.SearchNotesRegex(vaultPath, `todo\((\w+)\)`, obsidian.RegexSearchOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{
+			{FilePath: "tasks.md", LineNumber: 1, MatchLine: "TODO(«alice») review", MatchStart: 0, MatchEnd: 11},
+			{FilePath: "tasks.md", LineNumber: 2, MatchLine: "todo(«bob») later", MatchStart: 19, MatchEnd: 28},
+		}, matches)
+	})
+
+	t.Run("Case-sensitive", func(t *testing.T) {
+		matches, err := note.SearchNotesRegex(vaultPath, `TODO\((\w+)\)`, obsidian.RegexSearchOptions{CaseSensitive: true})
+		assert.NoError(t, err)
+		assert.Len(t, matches, 1)
+		assert.Equal(t, 1, matches[0].LineNumber)
+	})
+
+	t.Run("Highlights every match without groups and matches filenames", func(t *testing.T) {
+		matches, err := note.SearchNotesRegex(vaultPath, `\d{4}-\d{2}`, obsidian.RegexSearchOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{
+			{FilePath: "2024-05 review.md", LineNumber: 0, MatchLine: "(filename match: 2024-05 review.md)"},
+			{FilePath: "dates.md", LineNumber: 1, MatchLine: "Met on «2024-05»-01 and «2024-06»-12", MatchStart: 7, MatchEnd: 14},
+		}, matches)
+	})
+
+	t.Run("Multiline matches span lines", func(t *testing.T) {
+		matches, err := note.SearchNotesRegex(vaultPath, `begin.*?end`, obsidian.RegexSearchOptions{Multiline: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.NoteMatch{
+			{FilePath: "multi.md", LineNumber: 1, MatchLine: "«begin block ⏎ middle ⏎ end» block", MatchStart: 0, MatchEnd: 22},
+		}, matches)
+
+		matches, err = note.SearchNotesRegex(vaultPath, `begin.*?end`, obsidian.RegexSearchOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, matches)
+	})
+
+	t.Run("Long lines are trimmed around the match", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"long.md": strings.Repeat("a", 60) + " needle " + strings.Repeat("b", 60),
+		})
+		matches, err := note.SearchNotesRegex(vaultPath, `needle`, obsidian.RegexSearchOptions{})
+		assert.NoError(t, err)
+		assert.Equal(t, "..."+strings.Repeat("a", 19)+" «needle» "+strings.Repeat("b", 19)+"...", matches[0].MatchLine)
+	})
+
+	t.Run("Invalid pattern returns error", func(t *testing.T) {
+		_, err := note.SearchNotesRegex(vaultPath, `(`, obsidian.RegexSearchOptions{})
+		assert.Error(t, err)
+	})
+}
@@ -327,6 +327,24 @@ func TestUpdateNoteLinks_MarkdownLinks(t *testing.T) {
 	})
 }
 
+func TestUpdateNoteLinks_BlockReferences(t *testing.T) {
+	t.Run("Keep block references of moved note", func(t *testing.T) {
+		tmpDir := t.TempDir()
+
+		content := []byte("See [[oldNote#^abc123]], ![[folder/oldNote#^quote]] and [block](folder/oldNote.md#^abc123)")
+		testFile := filepath.Join(tmpDir, "test.md")
+		err := os.WriteFile(testFile, content, 0644)
+		assert.NoError(t, err)
+
+		noteManager := obsidian.Note{}
+		err = noteManager.UpdateLinks(tmpDir, "folder/oldNote", "other/newNote")
+		assert.NoError(t, err)
+
+		newContent, _ := os.ReadFile(testFile)
+		assert.Equal(t, "See [[newNote#^abc123]], ![[other/newNote#^quote]] and [block](other/newNote.md#^abc123)", string(newContent))
+	})
+}
+
 func TestUpdateNoteLinks_MixedFormats(t *testing.T) {
 	t.Run("Update mixed link formats in same file", func(t *testing.T) {
 		tmpDir := t.TempDir()
@@ -1,13 +1,12 @@
 package obsidian
 
 import (
-	"errors"
 	"path/filepath"
 	"strings"
 )
 
 // ErrPathTraversal is returned when a path attempts to escape the base directory
-var ErrPathTraversal = errors.New("path traversal detected: path must remain within vault directory")
+var ErrPathTraversal = NewError(ErrCodePathTraversal, "path traversal detected: path must remain within vault directory")
 
 // ValidatePath ensures the given relative path, when combined with basePath,
 // stays within basePath. It returns the cleaned absolute path on success.
@@ -0,0 +1,134 @@
+package obsidian
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Note periods supported by the Periodic Notes plugin.
+const (
+	PeriodDaily     = "daily"
+	PeriodWeekly    = "weekly"
+	PeriodMonthly   = "monthly"
+	PeriodQuarterly = "quarterly"
+	PeriodYearly    = "yearly"
+)
+
+// periodDefaultFormats are the Periodic Notes plugin's default note name
+// formats.
+var periodDefaultFormats = map[string]string{
+	PeriodDaily:     "YYYY-MM-DD",
+	PeriodWeekly:    "gggg-[W]ww",
+	PeriodMonthly:   "YYYY-MM",
+	PeriodQuarterly: "YYYY-[Q]Q",
+	PeriodYearly:    "YYYY",
+}
+
+// PeriodicNoteConfig represents the settings of one period in
+// .obsidian/plugins/periodic-notes/data.json.
+type PeriodicNoteConfig struct {
+	Enabled  bool   `json:"enabled"`
+	Folder   string `json:"folder"`
+	Format   string `json:"format"`
+	Template string `json:"template"`
+}
+
+// ReadPeriodicNotesConfig reads the Periodic Notes plugin config for period
+// from the vault. Returns zero-value config if unreadable.
+func ReadPeriodicNotesConfig(vaultPath, period string) PeriodicNoteConfig {
+	data, err := os.ReadFile(filepath.Join(vaultPath, ".obsidian", "plugins", "periodic-notes", "data.json"))
+	if err != nil {
+		return PeriodicNoteConfig{}
+	}
+
+	// Other top-level settings, such as showGettingStartedBanner, are not
+	// objects, so decode only the period's own entry.
+	var settings map[string]json.RawMessage
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return PeriodicNoteConfig{}
+	}
+
+	var config PeriodicNoteConfig
+	if err := json.Unmarshal(settings[period], &config); err != nil {
+		return PeriodicNoteConfig{}
+	}
+
+	return config
+}
+
+// PeriodDefaultFormat returns the Moment.js format the Periodic Notes plugin
+// uses for period when none is configured.
+func PeriodDefaultFormat(period string) string {
+	return periodDefaultFormats[period]
+}
+
+// DatedNote is a periodic note and the date its name stands for.
+type DatedNote struct {
+	Date string `json:"date"`
+	Path string `json:"path"`
+}
+
+// ListDatedNotes returns the notes under folder whose names, relative to
+// folder, are dates in the Moment.js format, ordered by date. Dates are
+// given as YYYY-MM-DD; a missing folder gives no notes.
+func ListDatedNotes(vaultPath, folder, format string) ([]DatedNote, error) {
+	root, err := ValidatePath(vaultPath, folder)
+	if err != nil {
+		return nil, err
+	}
+	// Report a format that cannot be parsed rather than matching no notes.
+	if err := ValidateMomentFormat(format); err != nil {
+		return nil, err
+	}
+
+	notes := []DatedNote{}
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if p == root && os.IsNotExist(err) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if strings.HasPrefix(d.Name(), ".") && p != root {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") {
+			return nil
+		}
+
+		relPath, err := filepath.Rel(root, p)
+		if err != nil {
+			return err
+		}
+		name := RemoveMdSuffix(filepath.ToSlash(relPath))
+		date, err := ParseMoment(name, format)
+		// Only names the format would produce for that date count, which
+		// rules out e.g. "2024-02-30" or a differently padded number.
+		if err != nil || FormatMoment(date, format) != name {
+			return nil
+		}
+
+		notePath := path.Join(filepath.ToSlash(folder), name+".md")
+		notes = append(notes, DatedNote{Date: date.Format("2006-01-02"), Path: notePath})
+		return nil
+	})
+	if err != nil {
+		return nil, WrapError(ErrCodeVaultRead, VaultReadError, err)
+	}
+
+	sort.SliceStable(notes, func(i, j int) bool {
+		if notes[i].Date != notes[j].Date {
+			return notes[i].Date < notes[j].Date
+		}
+		return notes[i].Path < notes[j].Path
+	})
+	return notes, nil
+}
@@ -0,0 +1,79 @@
+package obsidian_test
+
+import (
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadPeriodicNotesConfig(t *testing.T) {
+	t.Run("Reads the config of a period", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			".obsidian/plugins/periodic-notes/data.json": `{
+				"showGettingStartedBanner": false,
+				"weekly": {"enabled": true, "folder": "Weekly", "format": "GGGG-[W]WW", "template": "Templates/Week"}
+			}`,
+		})
+
+		config := obsidian.ReadPeriodicNotesConfig(vaultPath, obsidian.PeriodWeekly)
+		assert.Equal(t, obsidian.PeriodicNoteConfig{Enabled: true, Folder: "Weekly", Format: "GGGG-[W]WW", Template: "Templates/Week"}, config)
+		assert.Equal(t, obsidian.PeriodicNoteConfig{}, obsidian.ReadPeriodicNotesConfig(vaultPath, obsidian.PeriodMonthly))
+	})
+
+	t.Run("Returns zero config when missing or invalid", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		assert.Equal(t, obsidian.PeriodicNoteConfig{}, obsidian.ReadPeriodicNotesConfig(vaultPath, obsidian.PeriodWeekly))
+
+		writeVaultFiles(t, vaultPath, map[string]string{".obsidian/plugins/periodic-notes/data.json": `{`})
+		assert.Equal(t, obsidian.PeriodicNoteConfig{}, obsidian.ReadPeriodicNotesConfig(vaultPath, obsidian.PeriodWeekly))
+	})
+
+	t.Run("Default formats", func(t *testing.T) {
+		assert.Equal(t, "gggg-[W]ww", obsidian.PeriodDefaultFormat(obsidian.PeriodWeekly))
+		assert.Equal(t, "YYYY-[Q]Q", obsidian.PeriodDefaultFormat(obsidian.PeriodQuarterly))
+		assert.Equal(t, "", obsidian.PeriodDefaultFormat("hourly"))
+	})
+}
+
+func TestListDatedNotes(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"Daily/2024/03/2024-03-05.md":        "",
+		"Daily/2024/02/2024-02-29.md":        "",
+		"Daily/2024/02/2024-02-30.md":        "",
+		"Daily/2024/03/notes.md":             "",
+		"Daily/2024/03/2024-03-06.txt":       "",
+		"Daily/.trash/2024/03/2024-03-01.md": "",
+		"2024-03-04.md":                      "",
+	})
+
+	t.Run("Lists notes in the folder whose names are dates", func(t *testing.T) {
+		notes, err := obsidian.ListDatedNotes(vaultPath, "Daily", "YYYY/MM/YYYY-MM-DD")
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.DatedNote{
+			{Date: "2024-02-29", Path: "Daily/2024/02/2024-02-29.md"},
+			{Date: "2024-03-05", Path: "Daily/2024/03/2024-03-05.md"},
+		}, notes)
+	})
+
+	t.Run("Lists notes at the vault root", func(t *testing.T) {
+		notes, err := obsidian.ListDatedNotes(vaultPath, "", "YYYY-MM-DD")
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.DatedNote{{Date: "2024-03-04", Path: "2024-03-04.md"}}, notes)
+	})
+
+	t.Run("Missing folder", func(t *testing.T) {
+		notes, err := obsidian.ListDatedNotes(vaultPath, "Journal", "YYYY-MM-DD")
+		assert.NoError(t, err)
+		assert.Empty(t, notes)
+	})
+
+	t.Run("Invalid format", func(t *testing.T) {
+		_, err := obsidian.ListDatedNotes(vaultPath, "Daily", "YYYY-MM-DD\xff")
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+		_, err = obsidian.ListDatedNotes(vaultPath, "Daily", "[Journal]")
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
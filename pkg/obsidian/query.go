@@ -0,0 +1,58 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+	"github.com/Yakitrak/notesmd-cli/pkg/query"
+)
+
+// QueryVault runs a Dataview-style query (see query.Query for the syntax)
+// over the frontmatter of every note in the vault.
+func QueryVault(vaultPath, input string, now time.Time) (*query.Result, error) {
+	parsed, err := query.Parse(input)
+	if err != nil {
+		return nil, WrapError(ErrCodeInvalidQuery, err.Error(), err)
+	}
+
+	pages, err := queryPages(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	return parsed.Execute(pages, now), nil
+}
+
+// queryPages reads every note in the vault into a query.Page, sorted by path.
+// Notes with invalid frontmatter are included without it, and notes larger
+// than maxFileSizeBytes are not read.
+func queryPages(vaultPath string) ([]query.Page, error) {
+	files, err := scanVaultNotes(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	pages := make([]query.Page, 0, len(files))
+	for relPath, info := range files {
+		page := query.Page{Path: relPath, ModTime: info.ModTime(), Size: info.Size()}
+		if info.Size() <= maxFileSizeBytes {
+			data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(relPath)))
+			if err != nil {
+				return nil, NewError(ErrCodeVaultRead, VaultReadError)
+			}
+			content := string(data)
+			if frontmatter.HasFrontmatter(content) {
+				if fm, _, err := frontmatter.Parse(content); err == nil {
+					page.Frontmatter = fm
+					page.Tags = FrontmatterTags(fm)
+				}
+			}
+			page.Tags = append(page.Tags, ExtractInlineTags(content)...)
+		}
+		pages = append(pages, page)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Path < pages[j].Path })
+	return pages, nil
+}
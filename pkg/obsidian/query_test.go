@@ -0,0 +1,50 @@
+package obsidian_test
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryVault(t *testing.T) {
+	now := time.Date(2024, 5, 20, 12, 0, 0, 0, time.UTC)
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"Projects/alpha.md":  "---\nstatus: active\ndue: 2024-06-01\n---\n#work",
+		"Projects/beta.md":   "---\nstatus: done\ndue: 2024-05-01\n---\n",
+		"Projects/broken.md": "---\nstatus: [unclosed\n---\n#work",
+		"inbox.md":           "---\ntags: [work]\nstatus: active\n---\n",
+		".trash/old.md":      "---\nstatus: active\n---\n",
+	})
+
+	t.Run("Selects notes by folder and frontmatter", func(t *testing.T) {
+		result, err := obsidian.QueryVault(vaultPath, `TABLE status, due FROM "Projects" WHERE status != "done" SORT due DESC`, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"File", "status", "due"}, result.Columns)
+		assert.Equal(t, [][]interface{}{
+			{"Projects/alpha.md", "active", "2024-06-01"},
+			{"Projects/broken.md", nil, nil},
+		}, result.Rows)
+	})
+
+	t.Run("Selects notes by frontmatter and inline tags", func(t *testing.T) {
+		result, err := obsidian.QueryVault(vaultPath, `LIST FROM #work WHERE due < date(today) or !due`, now)
+
+		assert.NoError(t, err)
+		assert.Equal(t, [][]interface{}{{"Projects/broken.md"}, {"inbox.md"}}, result.Rows)
+	})
+
+	t.Run("Invalid query returns an error", func(t *testing.T) {
+		_, err := obsidian.QueryVault(vaultPath, `SELECT *`, now)
+		assert.Equal(t, obsidian.ErrCodeInvalidQuery, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Missing vault returns an error", func(t *testing.T) {
+		_, err := obsidian.QueryVault(filepath.Join(vaultPath, "missing"), `LIST`, now)
+		assert.Equal(t, obsidian.ErrCodeVaultAccess, obsidian.ErrorCode(err))
+	})
+}
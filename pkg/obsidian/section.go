@@ -0,0 +1,244 @@
+package obsidian
+
+import (
+	"strings"
+)
+
+// Section operations understood by EditSection.
+const (
+	SectionReplace = "replace"
+	SectionPrepend = "prepend"
+	SectionAppend  = "append"
+	SectionDelete  = "delete"
+)
+
+// Section is a heading of a note and the content under it, up to the next
+// heading of the same or a higher level. Offsets are byte offsets into the
+// note's content.
+type Section struct {
+	Heading Heading
+	// Start is the offset of the heading line and BodyStart the offset just
+	// past it; End is the offset of the next heading of the same or a
+	// higher level, or the length of the content.
+	Start     int
+	BodyStart int
+	End       int
+	Children  []*Section
+}
+
+// Body returns the content under the section's heading, subsections
+// included.
+func (s *Section) Body(content string) string {
+	return content[s.BodyStart:s.End]
+}
+
+// ownEnd returns the offset just past the last line of content that is not
+// blank under the section's heading and before its first subsection, or
+// BodyStart when there is none.
+func (s *Section) ownEnd(content string) int {
+	end := s.End
+	if len(s.Children) > 0 {
+		end = s.Children[0].Start
+	}
+	return s.BodyStart + contentEnd(content[s.BodyStart:end])
+}
+
+// ParseSections returns the tree of sections of a note, ignoring headings
+// inside frontmatter and fenced code blocks. Each heading is a child of the
+// closest heading before it with a lower level.
+func ParseSections(content string) []*Section {
+	var roots []*Section
+	var open []*Section
+	for _, line := range splitMarkdownLines(content) {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		m := headingRegex.FindStringSubmatch(strings.TrimRight(line.Text, "\r"))
+		if m == nil {
+			continue
+		}
+
+		section := &Section{
+			Heading: Heading{Level: len(m[1]), Text: m[2], Line: line.Number},
+			Start:   line.Offset,
+			End:     len(content),
+		}
+		section.BodyStart = line.Offset + len(line.Text)
+		if section.BodyStart < len(content) {
+			section.BodyStart++ // past the "\n"
+		}
+
+		for len(open) > 0 && open[len(open)-1].Heading.Level >= section.Heading.Level {
+			open[len(open)-1].End = line.Offset
+			open = open[:len(open)-1]
+		}
+		if len(open) == 0 {
+			roots = append(roots, section)
+		} else {
+			parent := open[len(open)-1]
+			parent.Children = append(parent.Children, section)
+		}
+		open = append(open, section)
+	}
+	return roots
+}
+
+// FindSection returns the section at path, a heading or headings separated
+// by ">" such as "Projects > Alpha > Notes". The first heading may be at
+// any level of the note and each following one anywhere under the previous
+// one. Headings are matched by text, ignoring case, and by level when they
+// start with "#"s. When several sections match, the first in the note wins.
+func FindSection(content, path string) (*Section, error) {
+	var headings []string
+	for _, heading := range strings.Split(path, ">") {
+		if heading = strings.TrimSpace(heading); heading != "" {
+			headings = append(headings, heading)
+		}
+	}
+	if len(headings) == 0 {
+		return nil, NewError(ErrCodeInvalidArgument, "section path cannot be empty")
+	}
+
+	if section := findSectionPath(ParseSections(content), headings); section != nil {
+		return section, nil
+	}
+	return nil, NewError(ErrCodeSectionNotFound, SectionNotFoundError)
+}
+
+// findSectionPath searches sections and their descendants, in note order,
+// for one matching headings[0] with the rest of headings under it.
+func findSectionPath(sections []*Section, headings []string) *Section {
+	level, title := ParseHeading(headings[0])
+	for _, section := range sections {
+		if (level == 0 || section.Heading.Level == level) && strings.EqualFold(section.Heading.Text, title) {
+			if len(headings) == 1 {
+				return section
+			}
+			if found := findSectionPath(section.Children, headings[1:]); found != nil {
+				return found
+			}
+		}
+		if found := findSectionPath(section.Children, headings); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// EditSection applies operation to the section at path and returns the new
+// content. SectionReplace replaces everything under the heading, including
+// subsections, with text; SectionPrepend and SectionAppend add text as new
+// lines at the start of the section or the end of its own content, before
+// any subsections; SectionDelete removes the
+// section along with its heading. Other than SectionDelete, blank lines
+// separating the section from the next heading are kept.
+func EditSection(content, path, operation, text string) (string, error) {
+	section, err := FindSection(content, path)
+	if err != nil {
+		return "", err
+	}
+
+	switch operation {
+	case SectionReplace:
+		body := section.Body(content)
+		gap := body[contentEnd(body):]
+		if text == "" {
+			return content[:section.BodyStart] + gap + content[section.End:], nil
+		}
+		return insertLines(content[:section.BodyStart]+gap+content[section.End:], section.BodyStart, text), nil
+	case SectionPrepend:
+		return insertLines(content, section.BodyStart+contentStart(section.Body(content)), text), nil
+	case SectionAppend:
+		return insertLines(content, section.ownEnd(content), text), nil
+	case SectionDelete:
+		return content[:section.Start] + content[section.End:], nil
+	}
+	return "", NewError(ErrCodeInvalidArgument, "unknown section operation: "+operation)
+}
+
+// ParseHeading splits a heading such as "## Log" into its level and text.
+// Without leading "#"s the level is 0, meaning any level.
+func ParseHeading(heading string) (int, string) {
+	heading = strings.TrimSpace(heading)
+	if m := headingRegex.FindStringSubmatch(heading); m != nil {
+		return len(m[1]), m[2]
+	}
+	return 0, heading
+}
+
+// AppendToSection inserts text as new lines at the end of the content under
+// heading, before any blank lines separating it from the next heading. The
+// heading is matched by text, ignoring case, and
+// by level when it starts with "#"s. When the note has no such heading, it
+// is added at the end of the note, as a level 2 heading if no level was
+// given. An empty heading appends text at the end of the note.
+func AppendToSection(content, heading, text string) string {
+	eol := lineEnding(content)
+	if heading != "" {
+		if section := findSectionPath(ParseSections(content), []string{heading}); section != nil {
+			return insertLines(content, section.ownEnd(content), text)
+		}
+
+		level, title := ParseHeading(heading)
+		if level == 0 {
+			level = 2
+		}
+		text = strings.Repeat("#", level) + " " + title + eol + text
+		if strings.TrimSpace(content) != "" {
+			text = eol + text
+		}
+	}
+
+	return insertLines(content, len(content), text)
+}
+
+// insertLines inserts text as whole lines at offset, which must be at the
+// start of a line or the end of content, using content's line endings.
+func insertLines(content string, offset int, text string) string {
+	eol := lineEnding(content)
+	if eol == "\r\n" {
+		text = strings.ReplaceAll(strings.ReplaceAll(text, "\r\n", "\n"), "\n", eol)
+	}
+	text = strings.TrimRight(text, "\r\n") + eol
+
+	if offset == len(content) && content != "" && !strings.HasSuffix(content, "\n") {
+		// The note ends without a trailing newline.
+		text = eol + text
+	}
+	return content[:offset] + text + content[offset:]
+}
+
+// lineEnding returns "\r\n" when content uses Windows line endings and "\n"
+// otherwise.
+func lineEnding(content string) string {
+	if strings.Contains(content, "\r\n") {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// contentStart returns the offset of the first line of body that is not
+// blank, or 0 when all lines are blank.
+func contentStart(body string) int {
+	offset := 0
+	for _, line := range strings.SplitAfter(body, "\n") {
+		if strings.TrimSpace(line) != "" {
+			return offset
+		}
+		offset += len(line)
+	}
+	return 0
+}
+
+// contentEnd returns the offset just past the last line of body that is not
+// blank, newline included, or 0 when all lines are blank.
+func contentEnd(body string) int {
+	end := len(strings.TrimRight(body, " \t\r\n"))
+	if end == 0 {
+		return 0
+	}
+	if i := strings.IndexByte(body[end:], '\n'); i >= 0 {
+		return end + i + 1
+	}
+	return len(body)
+}
@@ -0,0 +1,225 @@
+package obsidian_test
+
+import (
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppendToSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		heading  string
+		expected string
+	}{
+		{
+			"End of a section before the next heading",
+			"# Day\n\n## Log\n- a\n\n## Tasks\n- [ ] b\n",
+			"## Log",
+			"# Day\n\n## Log\n- a\n- new\n\n## Tasks\n- [ ] b\n",
+		},
+		{
+			"Text goes before subsections",
+			"## Log\n- a\n\n### Morning\n- b\n## Tasks\n",
+			"## Log",
+			"## Log\n- a\n- new\n\n### Morning\n- b\n## Tasks\n",
+		},
+		{
+			"Section with only subsections",
+			"## Log\n### Morning\n- b\n",
+			"## Log",
+			"## Log\n- new\n### Morning\n- b\n",
+		},
+		{
+			"Section at the end of the note",
+			"## Log\n- a\n\n",
+			"## Log",
+			"## Log\n- a\n- new\n\n",
+		},
+		{
+			"Empty section without a trailing newline",
+			"## Log",
+			"## Log",
+			"## Log\n- new\n",
+		},
+		{
+			"Heading text matches any level, ignoring case",
+			"### log\n- a\n",
+			"Log",
+			"### log\n- a\n- new\n",
+		},
+		{
+			"Heading level must match when given",
+			"### Log\n- a\n",
+			"## Log",
+			"### Log\n- a\n\n## Log\n- new\n",
+		},
+		{
+			"Missing heading is added at the end",
+			"# Day\nText",
+			"Log",
+			"# Day\nText\n\n## Log\n- new\n",
+		},
+		{
+			"Missing heading in an empty note",
+			"",
+			"## Log",
+			"## Log\n- new\n",
+		},
+		{
+			"Headings in code blocks are ignored",
+			"```\n## Log\n```\n",
+			"## Log",
+			"```\n## Log\n```\n\n## Log\n- new\n",
+		},
+		{
+			"No heading appends a line to the note",
+			"Text",
+			"",
+			"Text\n- new\n",
+		},
+		{
+			"Windows line endings are kept",
+			"## Log\r\n- a\r\n## Tasks\r\n",
+			"## Log",
+			"## Log\r\n- a\r\n- new\r\n## Tasks\r\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, obsidian.AppendToSection(test.content, test.heading, "- new"))
+		})
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	content := "---\ntitle: x\n---\n# Projects\nIntro\n## Alpha\n### Notes\n- a\n## Beta\n```\n# Not a heading\n```\n# Archive\n"
+	sections := obsidian.ParseSections(content)
+
+	assert.Len(t, sections, 2)
+	projects := sections[0]
+	assert.Equal(t, obsidian.Heading{Level: 1, Text: "Projects", Line: 4}, projects.Heading)
+	assert.Equal(t, "Intro\n## Alpha\n### Notes\n- a\n## Beta\n```\n# Not a heading\n```\n", projects.Body(content))
+	assert.Len(t, projects.Children, 2)
+	assert.Equal(t, "Alpha", projects.Children[0].Heading.Text)
+	assert.Equal(t, "### Notes\n- a\n", projects.Children[0].Body(content))
+	assert.Equal(t, "- a\n", projects.Children[0].Children[0].Body(content))
+	assert.Equal(t, "Archive", sections[1].Heading.Text)
+	assert.Equal(t, "", sections[1].Body(content))
+}
+
+func TestFindSection(t *testing.T) {
+	content := "# Projects\n## Alpha\n### Tasks\n- a\n## Beta\n### Notes\n- b\n# Alpha\n### Notes\n- c\n"
+
+	t.Run("Path of headings", func(t *testing.T) {
+		section, err := obsidian.FindSection(content, "Projects > Beta > Notes")
+		assert.NoError(t, err)
+		assert.Equal(t, "- b\n", section.Body(content))
+	})
+
+	t.Run("Later headings may be nested deeper", func(t *testing.T) {
+		section, err := obsidian.FindSection(content, "projects > notes")
+		assert.NoError(t, err)
+		assert.Equal(t, "- b\n", section.Body(content))
+	})
+
+	t.Run("First match that has the whole path", func(t *testing.T) {
+		section, err := obsidian.FindSection(content, "Alpha > Notes")
+		assert.NoError(t, err)
+		assert.Equal(t, "- c\n", section.Body(content))
+	})
+
+	t.Run("Level given with #s", func(t *testing.T) {
+		section, err := obsidian.FindSection(content, "# Alpha")
+		assert.NoError(t, err)
+		assert.Equal(t, 8, section.Heading.Line)
+	})
+
+	t.Run("Missing section", func(t *testing.T) {
+		_, err := obsidian.FindSection(content, "Projects > Gamma")
+		assert.Equal(t, obsidian.ErrCodeSectionNotFound, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Empty path", func(t *testing.T) {
+		_, err := obsidian.FindSection(content, " > ")
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+	})
+}
+
+func TestEditSection(t *testing.T) {
+	content := "# Day\n\n## Log\n\n- a\n### Morning\n- b\n\n## Tasks\n- [ ] c"
+	tests := []struct {
+		name      string
+		path      string
+		operation string
+		text      string
+		expected  string
+	}{
+		{
+			"Replace keeps the blank lines before the next heading",
+			"Log", obsidian.SectionReplace, "- new",
+			"# Day\n\n## Log\n- new\n\n## Tasks\n- [ ] c",
+		},
+		{
+			"Replace with nothing empties the section",
+			"Log", obsidian.SectionReplace, "",
+			"# Day\n\n## Log\n\n## Tasks\n- [ ] c",
+		},
+		{
+			"Prepend goes before the first line of content",
+			"Log", obsidian.SectionPrepend, "- new",
+			"# Day\n\n## Log\n\n- new\n- a\n### Morning\n- b\n\n## Tasks\n- [ ] c",
+		},
+		{
+			"Append goes after the last line of content, before subsections",
+			"Day > Log", obsidian.SectionAppend, "- new\n- newer",
+			"# Day\n\n## Log\n\n- a\n- new\n- newer\n### Morning\n- b\n\n## Tasks\n- [ ] c",
+		},
+		{
+			"Append to a subsection",
+			"Log > Morning", obsidian.SectionAppend, "- new",
+			"# Day\n\n## Log\n\n- a\n### Morning\n- b\n- new\n\n## Tasks\n- [ ] c",
+		},
+		{
+			"Append to a section ending the note",
+			"Tasks", obsidian.SectionAppend, "- [ ] new",
+			"# Day\n\n## Log\n\n- a\n### Morning\n- b\n\n## Tasks\n- [ ] c\n- [ ] new\n",
+		},
+		{
+			"Delete removes the heading and subsections",
+			"Log", obsidian.SectionDelete, "",
+			"# Day\n\n## Tasks\n- [ ] c",
+		},
+		{
+			"Delete a subsection",
+			"Log > Morning", obsidian.SectionDelete, "",
+			"# Day\n\n## Log\n\n- a\n## Tasks\n- [ ] c",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			result, err := obsidian.EditSection(content, test.path, test.operation, test.text)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, result)
+		})
+	}
+
+	t.Run("Append keeps the blank lines before a subsection", func(t *testing.T) {
+		result, err := obsidian.EditSection("## A\ntext\n\n### A1\nsub\n\n## B", "A", obsidian.SectionAppend, "- new")
+		assert.NoError(t, err)
+		assert.Equal(t, "## A\ntext\n- new\n\n### A1\nsub\n\n## B", result)
+	})
+
+	t.Run("Windows line endings are kept", func(t *testing.T) {
+		result, err := obsidian.EditSection("## Log\r\n- a\r\n## Tasks\r\n", "Log", obsidian.SectionReplace, "- b\n- c")
+		assert.NoError(t, err)
+		assert.Equal(t, "## Log\r\n- b\r\n- c\r\n## Tasks\r\n", result)
+	})
+
+	t.Run("Missing section", func(t *testing.T) {
+		_, err := obsidian.EditSection(content, "Notes", obsidian.SectionDelete, "")
+		assert.Equal(t, obsidian.ErrCodeSectionNotFound, obsidian.ErrorCode(err))
+	})
+}
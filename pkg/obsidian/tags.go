@@ -0,0 +1,346 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/frontmatter"
+	"gopkg.in/yaml.v3"
+)
+
+// TagCount is a tag and the number of notes that use it.
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+var (
+	validTagRegex         = regexp.MustCompile(`^[\p{L}\p{N}_\-]+(/[\p{L}\p{N}_\-]+)*$`)
+	frontmatterTokenRegex = regexp.MustCompile(`[^,\s]+`)
+)
+
+// tagEdit replaces content[start:end] with text.
+type tagEdit struct {
+	start, end int
+	text       string
+}
+
+// NormalizeTag strips a leading "#" and surrounding whitespace from tag and
+// checks that what remains is a tag Obsidian would recognise.
+func NormalizeTag(tag string) (string, error) {
+	tag = strings.TrimPrefix(strings.TrimSpace(tag), "#")
+	if !validTagRegex.MatchString(tag) || isNumeric(tag) {
+		return "", NewError(ErrCodeInvalidArgument, InvalidTagError)
+	}
+	return tag, nil
+}
+
+// ListTags returns every tag used in the vault, from note bodies and
+// frontmatter, with the number of notes using it. Tags are compared
+// case-insensitively, as in Obsidian, and reported with the spelling found
+// first. The most used tags come first; ties are ordered by name.
+func ListTags(vaultPath string) ([]TagCount, error) {
+	tagsByNote, err := vaultNoteTags(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]*TagCount)
+	for _, relPath := range sortedNotePaths(tagsByNote) {
+		seen := make(map[string]bool)
+		for _, tag := range tagsByNote[relPath] {
+			key := strings.ToLower(tag)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			if counts[key] == nil {
+				counts[key] = &TagCount{Tag: tag}
+			}
+			counts[key].Count++
+		}
+	}
+
+	tags := make([]TagCount, 0, len(counts))
+	for _, c := range counts {
+		tags = append(tags, *c)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return strings.ToLower(tags[i].Tag) < strings.ToLower(tags[j].Tag)
+	})
+	return tags, nil
+}
+
+// NotesWithTag returns the sorted paths of notes tagged with tag or with one
+// of its nested tags, so "project" also finds notes tagged "#project/alpha".
+func NotesWithTag(vaultPath, tag string) ([]string, error) {
+	tag, err := NormalizeTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	tagsByNote, err := vaultNoteTags(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	notes := []string{}
+	for _, relPath := range sortedNotePaths(tagsByNote) {
+		for _, t := range tagsByNote[relPath] {
+			if _, ok := renameTagValue(t, tag, tag); ok {
+				notes = append(notes, relPath)
+				break
+			}
+		}
+	}
+	return notes, nil
+}
+
+// RenameTag renames oldTag to newTag throughout the vault, in note bodies and
+// in frontmatter "tags"/"tag" values. Nested tags move with their parent:
+// renaming "project" turns "#project/alpha" into "#newname/alpha". Tags in
+// code are left alone. Either every affected note is rewritten or none is.
+// The sorted paths of the changed notes are returned.
+func RenameTag(vaultPath, oldTag, newTag string) ([]string, error) {
+	from, err := NormalizeTag(oldTag)
+	if err != nil {
+		return nil, err
+	}
+	to, err := NormalizeTag(newTag)
+	if err != nil {
+		return nil, err
+	}
+	tagsByNote, err := vaultNoteTags(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]string)
+	for _, relPath := range sortedNotePaths(tagsByNote) {
+		tagged := false
+		for _, t := range tagsByNote[relPath] {
+			if _, ok := renameTagValue(t, from, to); ok {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(relPath)))
+		if err != nil {
+			return nil, NewError(ErrCodeVaultRead, VaultReadError)
+		}
+		content := string(data)
+		if updated := renameTagInContent(content, from, to); updated != content {
+			updates[relPath] = updated
+		}
+	}
+
+	if err := writeNotesAtomically(vaultPath, updates); err != nil {
+		return nil, err
+	}
+	renamed := make([]string, 0, len(updates))
+	for relPath := range updates {
+		renamed = append(renamed, relPath)
+	}
+	sort.Strings(renamed)
+	return renamed, nil
+}
+
+// vaultNoteTags returns the tags of every note, keyed by vault-relative path.
+// Tags come from the index when there is one.
+func vaultNoteTags(vaultPath string) (map[string][]string, error) {
+	tags := make(map[string][]string)
+	if idx := freshIndex(vaultPath); idx != nil {
+		for relPath, entry := range idx.Notes {
+			tags[relPath] = entry.Tags
+		}
+		return tags, nil
+	}
+
+	files, err := scanVaultNotes(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	for relPath, info := range files {
+		if info.Size() > maxFileSizeBytes {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(relPath)))
+		if err != nil {
+			return nil, NewError(ErrCodeVaultRead, VaultReadError)
+		}
+		tags[relPath] = noteTags(string(data))
+	}
+	return tags, nil
+}
+
+func sortedNotePaths(notes map[string][]string) []string {
+	paths := make([]string, 0, len(notes))
+	for relPath := range notes {
+		paths = append(paths, relPath)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// renameTagValue reports whether tag is from or nested under it and, if so,
+// returns it with the from prefix replaced by to. Comparison ignores case.
+func renameTagValue(tag, from, to string) (string, bool) {
+	if len(tag) < len(from) || !strings.EqualFold(tag[:len(from)], from) {
+		return "", false
+	}
+	rest := tag[len(from):]
+	if rest != "" && rest[0] != '/' {
+		return "", false
+	}
+	return to + rest, true
+}
+
+// renameTagInContent rewrites inline and frontmatter occurrences of from (and
+// its nested tags) in a note.
+func renameTagInContent(content, from, to string) string {
+	lines := splitMarkdownLines(content)
+	edits := frontmatterTagEdits(content, lines, from, to)
+
+	for _, line := range lines {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		for _, loc := range inlineTagRegex.FindAllStringSubmatchIndex(stripInlineCode(line.Text), -1) {
+			tag := line.Text[loc[4]:loc[5]]
+			if renamed, ok := renameTagValue(tag, from, to); ok {
+				edits = append(edits, tagEdit{start: line.Offset + loc[4], end: line.Offset + loc[5], text: renamed})
+			}
+		}
+	}
+	return applyTagEdits(content, edits)
+}
+
+// frontmatterTagEdits locates the values of the "tags" and "tag" frontmatter
+// keys in the raw note text, so they can be renamed in place without
+// reformatting the rest of the frontmatter. Notes whose frontmatter is not
+// valid YAML are left alone.
+func frontmatterTagEdits(content string, lines []markdownLine, from, to string) []tagEdit {
+	if !frontmatter.HasFrontmatter(content) {
+		return nil
+	}
+	end := 1
+	for end < len(lines) && lines[end].InFrontmatter && !isFrontmatterEnd(lines[end].Text) {
+		end++
+	}
+	if end >= len(lines) {
+		return nil
+	}
+	block := make([]string, 0, end-1)
+	for _, line := range lines[1:end] {
+		block = append(block, line.Text)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(strings.Join(block, "\n")), &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var edits []tagEdit
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if key := root.Content[i].Value; key != "tags" && key != "tag" {
+			continue
+		}
+		switch value := root.Content[i+1]; value.Kind {
+		case yaml.ScalarNode:
+			edits = appendScalarTagEdit(edits, lines, value, from, to, true)
+		case yaml.SequenceNode:
+			for _, item := range value.Content {
+				if item.Kind == yaml.ScalarNode {
+					edits = appendScalarTagEdit(edits, lines, item, from, to, false)
+				}
+			}
+		}
+	}
+	return edits
+}
+
+// appendScalarTagEdit renames the tags held by a frontmatter scalar. With
+// split set, the scalar may hold several comma or space separated tags.
+// Scalars spanning several lines are not rewritten.
+func appendScalarTagEdit(edits []tagEdit, lines []markdownLine, node *yaml.Node, from, to string, split bool) []tagEdit {
+	// The YAML block starts on the line after the opening "---".
+	if node.Line < 1 || node.Line >= len(lines) || node.Value == "" {
+		return edits
+	}
+	line := lines[node.Line]
+	col := runeByteOffset(line.Text, node.Column-1)
+	i := strings.Index(line.Text[col:], node.Value)
+	if i == -1 {
+		return edits
+	}
+
+	renameToken := func(token string) string {
+		hash := ""
+		if strings.HasPrefix(token, "#") {
+			hash, token = "#", token[1:]
+		}
+		if renamed, ok := renameTagValue(token, from, to); ok {
+			return hash + renamed
+		}
+		return hash + token
+	}
+	renamed := renameToken(node.Value)
+	if split {
+		renamed = frontmatterTokenRegex.ReplaceAllStringFunc(node.Value, renameToken)
+	}
+	if renamed == node.Value {
+		return edits
+	}
+
+	start := line.Offset + col + i
+	return append(edits, tagEdit{start: start, end: start + len(node.Value), text: renamed})
+}
+
+func isFrontmatterEnd(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return trimmed == "---" || trimmed == "..."
+}
+
+// runeByteOffset returns the byte offset of the n-th rune of s.
+func runeByteOffset(s string, n int) int {
+	offset := 0
+	for i := 0; i < n && offset < len(s); i++ {
+		_, size := utf8.DecodeRuneInString(s[offset:])
+		offset += size
+	}
+	return offset
+}
+
+func applyTagEdits(content string, edits []tagEdit) string {
+	if len(edits) == 0 {
+		return content
+	}
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start < edits[j].start })
+
+	var b strings.Builder
+	last := 0
+	for _, e := range edits {
+		if e.start < last {
+			continue
+		}
+		b.WriteString(content[last:e.start])
+		b.WriteString(e.text)
+		last = e.end
+	}
+	b.WriteString(content[last:])
+	return b.String()
+}
@@ -0,0 +1,182 @@
+package obsidian_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTag(t *testing.T) {
+	t.Run("Strips leading hash and whitespace", func(t *testing.T) {
+		tag, err := obsidian.NormalizeTag("  #project/alpha ")
+		assert.NoError(t, err)
+		assert.Equal(t, "project/alpha", tag)
+	})
+
+	t.Run("Rejects invalid tags", func(t *testing.T) {
+		for _, tag := range []string{"", "#", "two words", "123", "a//b", "trailing/"} {
+			_, err := obsidian.NormalizeTag(tag)
+			assert.Error(t, err, tag)
+			assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err), tag)
+		}
+	})
+}
+
+func TestListTags(t *testing.T) {
+	t.Run("Counts notes per tag from bodies and frontmatter", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a.md":         "---\ntags: [project, Work]\n---\nSee #project/alpha and #project again",
+			"b.md":         "#work #todo\n```\n#notatag\n```\n`#alsonot`",
+			"c.md":         "---\ntags: todo, reading\n---\n#123 is not a tag",
+			"sub/d.md":     "Nothing here",
+			".hidden/e.md": "#hidden",
+		})
+
+		tags, err := obsidian.ListTags(vaultPath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.TagCount{
+			{Tag: "todo", Count: 2},
+			{Tag: "Work", Count: 2},
+			{Tag: "project", Count: 1},
+			{Tag: "project/alpha", Count: 1},
+			{Tag: "reading", Count: 1},
+		}, tags)
+	})
+
+	t.Run("Uses the index when present", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": "#indexed"})
+		_, err := obsidian.RebuildIndex(vaultPath)
+		assert.NoError(t, err)
+		writeVaultFiles(t, vaultPath, map[string]string{"b.md": "#indexed #fresh"})
+
+		tags, err := obsidian.ListTags(vaultPath)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []obsidian.TagCount{{Tag: "indexed", Count: 2}, {Tag: "fresh", Count: 1}}, tags)
+	})
+
+	t.Run("Missing vault returns an error", func(t *testing.T) {
+		_, err := obsidian.ListTags(filepath.Join(t.TempDir(), "missing"))
+		assert.Equal(t, obsidian.ErrCodeVaultAccess, obsidian.ErrorCode(err))
+	})
+}
+
+func TestNotesWithTag(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"a.md": "#project",
+		"b.md": "#Project/alpha",
+		"c.md": "#projects",
+		"d.md": "---\ntags:\n  - project/beta\n---\n",
+	})
+
+	t.Run("Includes nested tags and ignores case", func(t *testing.T) {
+		notes, err := obsidian.NotesWithTag(vaultPath, "#project")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md", "b.md", "d.md"}, notes)
+	})
+
+	t.Run("Nested tag only matches its own subtree", func(t *testing.T) {
+		notes, err := obsidian.NotesWithTag(vaultPath, "project/alpha")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"b.md"}, notes)
+	})
+
+	t.Run("Unknown tag returns no notes", func(t *testing.T) {
+		notes, err := obsidian.NotesWithTag(vaultPath, "missing")
+		assert.NoError(t, err)
+		assert.Empty(t, notes)
+	})
+}
+
+func TestRenameTag(t *testing.T) {
+	readNote := func(t *testing.T, vaultPath, name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("Renames body and frontmatter tags including nested tags", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a.md": "---\ntitle: A # keep this comment\ntags: [project, other]\n---\n#project and #Project/alpha, not #projects\n`#project` stays\n```\n#project\n```\n",
+			"b.md": "---\ntags:\n  - \"#project/beta\"\n  - other\naliases: [x]\n---\nBody",
+			"c.md": "---\ntag: other, project\n---\n",
+			"d.md": "#other only",
+		})
+
+		changed, err := obsidian.RenameTag(vaultPath, "project", "#work/projects")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md", "b.md", "c.md"}, changed)
+		assert.Equal(t, "---\ntitle: A # keep this comment\ntags: [work/projects, other]\n---\n#work/projects and #work/projects/alpha, not #projects\n`#project` stays\n```\n#project\n```\n", readNote(t, vaultPath, "a.md"))
+		assert.Equal(t, "---\ntags:\n  - \"#work/projects/beta\"\n  - other\naliases: [x]\n---\nBody", readNote(t, vaultPath, "b.md"))
+		assert.Equal(t, "---\ntag: other, work/projects\n---\n", readNote(t, vaultPath, "c.md"))
+		assert.Equal(t, "#other only", readNote(t, vaultPath, "d.md"))
+
+		notes, err := obsidian.NotesWithTag(vaultPath, "project")
+		assert.NoError(t, err)
+		assert.Empty(t, notes)
+	})
+
+	t.Run("Renaming a nested tag leaves its parent alone", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": "#project #project/alpha #project/alpha/x"})
+
+		changed, err := obsidian.RenameTag(vaultPath, "project/alpha", "alpha")
+
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a.md"}, changed)
+		assert.Equal(t, "#project #alpha #alpha/x", readNote(t, vaultPath, "a.md"))
+	})
+
+	t.Run("Unused tag changes nothing", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": "#other"})
+
+		changed, err := obsidian.RenameTag(vaultPath, "missing", "new")
+
+		assert.NoError(t, err)
+		assert.Empty(t, changed)
+		assert.Equal(t, "#other", readNote(t, vaultPath, "a.md"))
+	})
+
+	t.Run("Invalid new tag is rejected before any note is touched", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"a.md": "#project"})
+
+		_, err := obsidian.RenameTag(vaultPath, "project", "new tag")
+
+		assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err))
+		assert.Equal(t, "#project", readNote(t, vaultPath, "a.md"))
+	})
+
+	t.Run("No note is changed when one cannot be written", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{
+			"a.md":        "#project",
+			"locked/b.md": "#project",
+		})
+		lockedDir := filepath.Join(vaultPath, "locked")
+		if err := os.Chmod(lockedDir, 0555); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Chmod(lockedDir, 0755)
+
+		_, err := obsidian.RenameTag(vaultPath, "project", "work")
+
+		assert.Equal(t, obsidian.ErrCodeVaultWrite, obsidian.ErrorCode(err))
+		assert.Equal(t, "#project", readNote(t, vaultPath, "a.md"))
+		assert.Equal(t, "#project", readNote(t, vaultPath, "locked/b.md"))
+		assert.NoFileExists(t, filepath.Join(vaultPath, "a.md.notesmd-tmp"))
+	})
+}
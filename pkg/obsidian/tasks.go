@@ -0,0 +1,344 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Task statuses, derived from the character between a checkbox's brackets.
+const (
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in-progress"
+	TaskStatusDone       = "done"
+	TaskStatusCancelled  = "cancelled"
+)
+
+// Task priorities, as set by the Tasks plugin's priority emoji.
+const (
+	TaskPriorityHighest = "highest"
+	TaskPriorityHigh    = "high"
+	TaskPriorityMedium  = "medium"
+	TaskPriorityLow     = "low"
+	TaskPriorityLowest  = "lowest"
+)
+
+// Task is a Markdown checkbox item ("- [ ] ...") along with the metadata the
+// Obsidian Tasks plugin stores as emoji, e.g. "📅 2024-05-01" for a due date.
+// Dates are kept as written, in YYYY-MM-DD form.
+type Task struct {
+	Path        string   `json:"path"`
+	Line        int      `json:"line"`
+	Status      string   `json:"status"`
+	Symbol      string   `json:"symbol"`
+	Text        string   `json:"text"`
+	Description string   `json:"description"`
+	Priority    string   `json:"priority,omitempty"`
+	Due         string   `json:"due,omitempty"`
+	Scheduled   string   `json:"scheduled,omitempty"`
+	Start       string   `json:"start,omitempty"`
+	Created     string   `json:"created,omitempty"`
+	Completed   string   `json:"completed,omitempty"`
+	Cancelled   string   `json:"cancelled,omitempty"`
+	Recurrence  string   `json:"recurrence,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+}
+
+// TaskFilter selects tasks in FindTasks. Empty fields match every task.
+type TaskFilter struct {
+	// Status is a task status, "open" (todo or in progress) or "all".
+	Status string
+	// DueBefore and DueAfter exclude tasks due on or after, respectively on
+	// or before, the given YYYY-MM-DD date. DueOn keeps tasks due that day.
+	// Tasks without a due date never match a due date filter.
+	DueBefore string
+	DueAfter  string
+	DueOn     string
+	// Path keeps tasks in notes at or below a vault-relative path.
+	Path string
+	// Tag keeps tasks tagged with the tag or one of its nested tags.
+	Tag string
+}
+
+// Task status filters accepted in addition to the statuses themselves.
+const (
+	TaskFilterOpen = "open"
+	TaskFilterAll  = "all"
+)
+
+var (
+	taskLineRegex = regexp.MustCompile(`^((?:\s*>)*\s*(?:[-*+]|\d+[.)])\s+\[)(.)\](.*)$`)
+	taskDateRegex = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}`)
+	doneDateRegex = regexp.MustCompile(`\s*✅\x{FE0F}?\s*\d{4}-\d{2}-\d{2}`)
+)
+
+const taskDateFormat = "2006-01-02"
+
+// taskMarkers maps each Tasks plugin emoji to the field it introduces.
+var taskMarkers = []struct {
+	emoji string
+	field string
+}{
+	{"📅", "due"}, {"📆", "due"}, {"🗓", "due"},
+	{"⏳", "scheduled"}, {"⌛", "scheduled"},
+	{"🛫", "start"},
+	{"➕", "created"},
+	{"✅", "completed"},
+	{"❌", "cancelled"},
+	{"🔁", "recurrence"},
+	{"🔺", TaskPriorityHighest}, {"⏫", TaskPriorityHigh}, {"🔼", TaskPriorityMedium},
+	{"🔽", TaskPriorityLow}, {"⏬", TaskPriorityLowest},
+	{"🆔", "id"}, {"⛔", "depends"}, {"🏁", "on-completion"},
+}
+
+// ParseTasks returns the checkbox items in content, skipping frontmatter and
+// code blocks. Path is left empty.
+func ParseTasks(content string) []Task {
+	var tasks []Task
+	for _, line := range splitMarkdownLines(content) {
+		if line.InCode || line.InFrontmatter {
+			continue
+		}
+		if task, ok := parseTaskLine(line.Text); ok {
+			task.Line = line.Number
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+func parseTaskLine(line string) (Task, bool) {
+	m := taskLineRegex.FindStringSubmatch(strings.TrimSuffix(line, "\r"))
+	if m == nil {
+		return Task{}, false
+	}
+	description := strings.TrimSpace(m[3])
+	task := Task{
+		Status:      taskStatus(m[2]),
+		Symbol:      m[2],
+		Text:        description,
+		Description: description,
+		Tags:        ExtractInlineTags(description),
+	}
+
+	markers := findTaskMarkers(description)
+	if len(markers) == 0 {
+		return task, true
+	}
+
+	task.Text = strings.TrimSpace(description[:markers[0].start])
+	for i, mk := range markers {
+		valueEnd := len(description)
+		if i+1 < len(markers) {
+			valueEnd = markers[i+1].start
+		}
+		value := strings.TrimSpace(strings.TrimPrefix(description[mk.end:valueEnd], "\uFE0F"))
+		date := taskDateRegex.FindString(value)
+
+		switch mk.field {
+		case "due":
+			task.Due = date
+		case "scheduled":
+			task.Scheduled = date
+		case "start":
+			task.Start = date
+		case "created":
+			task.Created = date
+		case "completed":
+			task.Completed = date
+		case "cancelled":
+			task.Cancelled = date
+		case "recurrence":
+			if j := strings.Index(value, " #"); j != -1 {
+				value = value[:j]
+			}
+			task.Recurrence = value
+		case TaskPriorityHighest, TaskPriorityHigh, TaskPriorityMedium, TaskPriorityLow, TaskPriorityLowest:
+			task.Priority = mk.field
+		}
+	}
+	return task, true
+}
+
+// taskMarker is the position of a Tasks plugin emoji in a task description.
+type taskMarker struct {
+	start, end int
+	field      string
+}
+
+// findTaskMarkers returns the metadata emoji in description, in order.
+func findTaskMarkers(description string) []taskMarker {
+	var markers []taskMarker
+	for _, tm := range taskMarkers {
+		for offset := 0; ; {
+			i := strings.Index(description[offset:], tm.emoji)
+			if i == -1 {
+				break
+			}
+			start := offset + i
+			offset = start + len(tm.emoji)
+			markers = append(markers, taskMarker{start: start, end: offset, field: tm.field})
+		}
+	}
+	sort.Slice(markers, func(i, j int) bool { return markers[i].start < markers[j].start })
+	return markers
+}
+
+func taskStatus(symbol string) string {
+	switch symbol {
+	case "x", "X":
+		return TaskStatusDone
+	case "/":
+		return TaskStatusInProgress
+	case "-":
+		return TaskStatusCancelled
+	}
+	return TaskStatusTodo
+}
+
+// ValidTaskStatusFilter reports whether status is accepted by TaskFilter.
+func ValidTaskStatusFilter(status string) bool {
+	switch status {
+	case "", TaskFilterOpen, TaskFilterAll, TaskStatusTodo, TaskStatusInProgress, TaskStatusDone, TaskStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// Matches reports whether task is selected by the filter.
+func (f TaskFilter) Matches(task Task) bool {
+	switch f.Status {
+	case "", TaskFilterAll:
+	case TaskFilterOpen:
+		if task.Status != TaskStatusTodo && task.Status != TaskStatusInProgress {
+			return false
+		}
+	default:
+		if task.Status != f.Status {
+			return false
+		}
+	}
+
+	if f.DueBefore != "" || f.DueAfter != "" || f.DueOn != "" {
+		if task.Due == "" ||
+			(f.DueBefore != "" && task.Due >= f.DueBefore) ||
+			(f.DueAfter != "" && task.Due <= f.DueAfter) ||
+			(f.DueOn != "" && task.Due != f.DueOn) {
+			return false
+		}
+	}
+
+	if f.Path != "" {
+		prefix := strings.Trim(normalizePathSeparators(f.Path), "/")
+		if task.Path != prefix && task.Path != AddMdSuffix(prefix) && !strings.HasPrefix(task.Path, prefix+"/") {
+			return false
+		}
+	}
+
+	if f.Tag != "" {
+		tag := strings.TrimPrefix(f.Tag, "#")
+		tagged := false
+		for _, t := range task.Tags {
+			if _, ok := renameTagValue(t, tag, tag); ok {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			return false
+		}
+	}
+	return true
+}
+
+// FindTasks returns the tasks in every note of the vault that match filter,
+// ordered by note path and line.
+func FindTasks(vaultPath string, filter TaskFilter) ([]Task, error) {
+	files, err := scanVaultNotes(vaultPath)
+	if err != nil {
+		return nil, err
+	}
+	relPaths := make([]string, 0, len(files))
+	for relPath, info := range files {
+		if info.Size() <= maxFileSizeBytes {
+			relPaths = append(relPaths, relPath)
+		}
+	}
+	sort.Strings(relPaths)
+
+	tasks := []Task{}
+	for _, relPath := range relPaths {
+		data, err := os.ReadFile(filepath.Join(vaultPath, filepath.FromSlash(relPath)))
+		if err != nil {
+			return nil, NewError(ErrCodeVaultRead, VaultReadError)
+		}
+		for _, task := range ParseTasks(string(data)) {
+			task.Path = relPath
+			if filter.Matches(task) {
+				tasks = append(tasks, task)
+			}
+		}
+	}
+	return tasks, nil
+}
+
+// ToggleTask flips the checkbox on the given 1-based line of a note between
+// done and not done, and returns the updated task. Tasks that carry Tasks
+// plugin metadata get a "✅ YYYY-MM-DD" completion date when checked off,
+// which is removed again when they are unchecked.
+func ToggleTask(vaultPath, noteName string, line int, today time.Time) (Task, error) {
+	notePath, err := findNotePath(vaultPath, noteName)
+	if err != nil {
+		return Task{}, err
+	}
+	relPath, err := filepath.Rel(vaultPath, notePath)
+	if err != nil {
+		return Task{}, NewError(ErrCodeNoteNotFound, NoteDoesNotExistError)
+	}
+	relPath = normalizePathSeparators(relPath)
+
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		return Task{}, NewError(ErrCodeVaultRead, VaultReadError)
+	}
+	content := string(data)
+
+	var target *markdownLine
+	lines := splitMarkdownLines(content)
+	if line >= 1 && line <= len(lines) && !lines[line-1].InCode && !lines[line-1].InFrontmatter {
+		target = &lines[line-1]
+	}
+	if target == nil {
+		return Task{}, NewError(ErrCodeInvalidArgument, NotATaskError)
+	}
+	task, ok := parseTaskLine(target.Text)
+	if !ok {
+		return Task{}, NewError(ErrCodeInvalidArgument, NotATaskError)
+	}
+
+	text := strings.TrimSuffix(target.Text, "\r")
+	cr := target.Text[len(text):]
+	loc := taskLineRegex.FindStringSubmatchIndex(text)
+	var updated string
+	if task.Status == TaskStatusDone {
+		updated = text[:loc[4]] + " " + doneDateRegex.ReplaceAllString(text[loc[5]:], "")
+	} else {
+		updated = text[:loc[4]] + "x" + text[loc[5]:]
+		if len(findTaskMarkers(task.Description)) > 0 && task.Completed == "" {
+			updated = strings.TrimRight(updated, " \t") + " ✅ " + today.Format(taskDateFormat)
+		}
+	}
+
+	newContent := content[:target.Offset] + updated + cr + content[target.Offset+len(target.Text):]
+	if err := writeNotesAtomically(vaultPath, map[string]string{relPath: newContent}); err != nil {
+		return Task{}, err
+	}
+
+	toggled, _ := parseTaskLine(updated)
+	toggled.Path = relPath
+	toggled.Line = line
+	return toggled, nil
+}
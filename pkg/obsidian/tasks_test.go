@@ -0,0 +1,159 @@
+package obsidian_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTasks(t *testing.T) {
+	t.Run("Parses checkboxes and Tasks plugin metadata", func(t *testing.T) {
+		content := "---\ntodo: - [ ] not a task\n---\n" +
+			"- [ ] Plain task #work\n" +
+			"  * [x] Done task ✅ 2024-05-02\n" +
+			"1. [/] Started\n" +
+			"> - [-] Dropped ❌ 2024-05-03\n" +
+			"- [ ] Pay rent ⏫ 🔁 every month #home 📅 2024-06-01 ⏳ 2024-05-30 🛫 2024-05-25 ➕ 2024-05-01\n" +
+			"- [ ] Variation selector 🗓️ 2024-07-01 🔽\n" +
+			"```\n- [ ] in code\n```\n" +
+			"- not a task\n"
+
+		tasks := obsidian.ParseTasks(content)
+
+		assert.Equal(t, []obsidian.Task{
+			{Line: 4, Status: "todo", Symbol: " ", Text: "Plain task #work", Description: "Plain task #work", Tags: []string{"work"}},
+			{Line: 5, Status: "done", Symbol: "x", Text: "Done task", Description: "Done task ✅ 2024-05-02", Completed: "2024-05-02"},
+			{Line: 6, Status: "in-progress", Symbol: "/", Text: "Started", Description: "Started"},
+			{Line: 7, Status: "cancelled", Symbol: "-", Text: "Dropped", Description: "Dropped ❌ 2024-05-03", Cancelled: "2024-05-03"},
+			{
+				Line: 8, Status: "todo", Symbol: " ", Text: "Pay rent",
+				Description: "Pay rent ⏫ 🔁 every month #home 📅 2024-06-01 ⏳ 2024-05-30 🛫 2024-05-25 ➕ 2024-05-01",
+				Priority:    "high", Recurrence: "every month", Due: "2024-06-01", Scheduled: "2024-05-30",
+				Start: "2024-05-25", Created: "2024-05-01", Tags: []string{"home"},
+			},
+			{Line: 9, Status: "todo", Symbol: " ", Text: "Variation selector", Description: "Variation selector 🗓️ 2024-07-01 🔽", Due: "2024-07-01", Priority: "low"},
+		}, tasks)
+	})
+}
+
+func TestFindTasks(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		"Daily/2024-05-01.md":  "- [ ] Call bob 📅 2024-05-01\n- [x] Write report 📅 2024-04-30\n",
+		"Projects/alpha.md":    "- [/] Build it #project/alpha 📅 2024-05-10\n- [ ] No due date #project\n",
+		"Projects/alphabet.md": "- [-] Cancelled 📅 2024-05-02\n",
+		"inbox.md":             "- [ ] Someday\n",
+	})
+
+	paths := func(tasks []obsidian.Task) []string {
+		var out []string
+		for _, task := range tasks {
+			out = append(out, task.Path+":"+task.Text)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name     string
+		filter   obsidian.TaskFilter
+		expected []string
+	}{
+		{"All tasks", obsidian.TaskFilter{}, []string{
+			"Daily/2024-05-01.md:Call bob", "Daily/2024-05-01.md:Write report",
+			"Projects/alpha.md:Build it #project/alpha", "Projects/alpha.md:No due date #project",
+			"Projects/alphabet.md:Cancelled", "inbox.md:Someday",
+		}},
+		{"Open tasks", obsidian.TaskFilter{Status: "open"}, []string{
+			"Daily/2024-05-01.md:Call bob", "Projects/alpha.md:Build it #project/alpha",
+			"Projects/alpha.md:No due date #project", "inbox.md:Someday",
+		}},
+		{"Done tasks", obsidian.TaskFilter{Status: "done"}, []string{"Daily/2024-05-01.md:Write report"}},
+		{"Due before", obsidian.TaskFilter{DueBefore: "2024-05-02"}, []string{"Daily/2024-05-01.md:Call bob", "Daily/2024-05-01.md:Write report"}},
+		{"Due after", obsidian.TaskFilter{DueAfter: "2024-05-02"}, []string{"Projects/alpha.md:Build it #project/alpha"}},
+		{"Due on", obsidian.TaskFilter{DueOn: "2024-05-02"}, []string{"Projects/alphabet.md:Cancelled"}},
+		{"Folder path", obsidian.TaskFilter{Path: "Projects/"}, []string{
+			"Projects/alpha.md:Build it #project/alpha", "Projects/alpha.md:No due date #project", "Projects/alphabet.md:Cancelled",
+		}},
+		{"Note path without extension", obsidian.TaskFilter{Path: "Projects/alpha"}, []string{
+			"Projects/alpha.md:Build it #project/alpha", "Projects/alpha.md:No due date #project",
+		}},
+		{"Nested tag", obsidian.TaskFilter{Tag: "#project"}, []string{
+			"Projects/alpha.md:Build it #project/alpha", "Projects/alpha.md:No due date #project",
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tasks, err := obsidian.FindTasks(vaultPath, test.filter)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, paths(tasks))
+		})
+	}
+
+	t.Run("Missing vault returns an error", func(t *testing.T) {
+		_, err := obsidian.FindTasks(filepath.Join(vaultPath, "missing"), obsidian.TaskFilter{})
+		assert.Equal(t, obsidian.ErrCodeVaultAccess, obsidian.ErrorCode(err))
+	})
+}
+
+func TestToggleTask(t *testing.T) {
+	today := time.Date(2024, 5, 20, 9, 0, 0, 0, time.UTC)
+	readNote := func(t *testing.T, path string) string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	t.Run("Checks off a plain task and unchecks it again", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"Projects/todo.md": "# Todo\n- [ ] Plain\r\n- [ ] Other\n"})
+		notePath := filepath.Join(vaultPath, "Projects", "todo.md")
+
+		task, err := obsidian.ToggleTask(vaultPath, "todo", 2, today)
+		assert.NoError(t, err)
+		assert.Equal(t, obsidian.Task{Path: "Projects/todo.md", Line: 2, Status: "done", Symbol: "x", Text: "Plain", Description: "Plain"}, task)
+		assert.Equal(t, "# Todo\n- [x] Plain\r\n- [ ] Other\n", readNote(t, notePath))
+
+		task, err = obsidian.ToggleTask(vaultPath, "Projects/todo.md", 2, today)
+		assert.NoError(t, err)
+		assert.Equal(t, "todo", task.Status)
+		assert.Equal(t, "# Todo\n- [ ] Plain\r\n- [ ] Other\n", readNote(t, notePath))
+	})
+
+	t.Run("Adds and removes the completion date on Tasks plugin tasks", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"todo.md": "- [ ] Pay rent 📅 2024-06-01"})
+		notePath := filepath.Join(vaultPath, "todo.md")
+
+		task, err := obsidian.ToggleTask(vaultPath, "todo", 1, today)
+		assert.NoError(t, err)
+		assert.Equal(t, "2024-05-20", task.Completed)
+		assert.Equal(t, "- [x] Pay rent 📅 2024-06-01 ✅ 2024-05-20", readNote(t, notePath))
+
+		_, err = obsidian.ToggleTask(vaultPath, "todo", 1, today)
+		assert.NoError(t, err)
+		assert.Equal(t, "- [ ] Pay rent 📅 2024-06-01", readNote(t, notePath))
+	})
+
+	t.Run("Line that is not a task returns an error", func(t *testing.T) {
+		vaultPath := t.TempDir()
+		writeVaultFiles(t, vaultPath, map[string]string{"todo.md": "# Todo\n```\n- [ ] in code\n```\n"})
+
+		for _, line := range []int{0, 1, 3, 99} {
+			_, err := obsidian.ToggleTask(vaultPath, "todo", line, today)
+			assert.Equal(t, obsidian.ErrCodeInvalidArgument, obsidian.ErrorCode(err), line)
+		}
+		assert.Equal(t, "# Todo\n```\n- [ ] in code\n```\n", readNote(t, filepath.Join(vaultPath, "todo.md")))
+	})
+
+	t.Run("Missing note returns an error", func(t *testing.T) {
+		_, err := obsidian.ToggleTask(t.TempDir(), "missing", 1, today)
+		assert.Equal(t, obsidian.ErrCodeNoteNotFound, obsidian.ErrorCode(err))
+	})
+}
@@ -0,0 +1,105 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Default formats of the {{date}} and {{time}} variables, as in Obsidian's
+// core Templates plugin.
+const (
+	DefaultTemplateDateFormat = "YYYY-MM-DD"
+	DefaultTemplateTimeFormat = "HH:mm"
+)
+
+// templateVariable matches {{name}} and {{name:format}}, allowing spaces
+// around the name.
+var templateVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*(?::([^}]*))?\}\}`)
+
+// TemplateData holds the values substituted into a template.
+type TemplateData struct {
+	// Title is the name of the note being created, without folder or
+	// extension.
+	Title string
+	// Now is the moment {{date}} and {{time}} refer to.
+	Now time.Time
+	// DateFormat and TimeFormat are the Moment.js formats of {{date}} and
+	// {{time}} without an explicit format. Empty means the defaults.
+	DateFormat string
+	TimeFormat string
+	// Vars are user-supplied variables. They take precedence over the
+	// built-in ones.
+	Vars map[string]string
+}
+
+// ExpandTemplate substitutes the Obsidian core template variables {{title}},
+// {{date}}, {{time}}, {{date:FORMAT}} and {{time:FORMAT}}, and any user
+// variables, into content. Unknown variables are left as they are.
+func ExpandTemplate(content string, data TemplateData) string {
+	return templateVariable.ReplaceAllStringFunc(content, func(match string) string {
+		groups := templateVariable.FindStringSubmatch(match)
+		name, format := groups[1], strings.TrimSpace(groups[2])
+		hasFormat := strings.Contains(match, ":")
+
+		if value, ok := data.Vars[name]; ok && !hasFormat {
+			return value
+		}
+
+		switch strings.ToLower(name) {
+		case "title":
+			if hasFormat {
+				return match
+			}
+			return data.Title
+		case "date":
+			if format == "" {
+				format = data.DateFormat
+			}
+			if format == "" {
+				format = DefaultTemplateDateFormat
+			}
+			return FormatMoment(data.Now, format)
+		case "time":
+			if format == "" {
+				format = data.TimeFormat
+			}
+			if format == "" {
+				format = DefaultTemplateTimeFormat
+			}
+			return FormatMoment(data.Now, format)
+		}
+		return match
+	})
+}
+
+// ReadTemplate returns the content of the template called name. The name is
+// looked up in the templates folder configured in .obsidian/templates.json
+// first and then relative to the vault root; the .md suffix is optional.
+func ReadTemplate(vaultPath, name string) (string, error) {
+	var candidates []string
+	if folder := ReadTemplatesConfig(vaultPath).Folder; folder != "" {
+		candidates = append(candidates, strings.TrimSuffix(folder, "/")+"/"+name)
+	}
+	candidates = append(candidates, name)
+
+	for _, candidate := range candidates {
+		templatePath, err := ValidatePath(vaultPath, AddMdSuffix(candidate))
+		if err != nil {
+			return "", err
+		}
+		content, err := os.ReadFile(templatePath)
+		if err == nil {
+			return string(content), nil
+		}
+	}
+	return "", NewError(ErrCodeTemplateNotFound, TemplateNotFoundError)
+}
+
+// NoteTitle returns the title Obsidian gives the note at notePath: its file
+// name without folder or extension.
+func NoteTitle(notePath string) string {
+	return RemoveMdSuffix(filepath.Base(filepath.FromSlash(notePath)))
+}
@@ -0,0 +1,66 @@
+package obsidian_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/obsidian"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandTemplate(t *testing.T) {
+	now := time.Date(2024, time.March, 5, 14, 7, 0, 0, time.UTC)
+	data := obsidian.TemplateData{Title: "Meeting", Now: now, Vars: map[string]string{"project": "Apollo"}}
+
+	tests := []struct {
+		name     string
+		template string
+		data     obsidian.TemplateData
+		expected string
+	}{
+		{"Title", "# {{title}}", data, "# Meeting"},
+		{"Default date and time", "{{date}} {{time}}", data, "2024-03-05 14:07"},
+		{"Explicit formats", "{{date:dddd, MMMM D}} at {{time:h:mm A}}", data, "Tuesday, March 5 at 2:07 PM"},
+		{"Configured formats", "{{date}} {{time}}", obsidian.TemplateData{Now: now, DateFormat: "DD/MM/YYYY", TimeFormat: "HH.mm"}, "05/03/2024 14.07"},
+		{"Spaces inside braces", "{{ title }}", data, "Meeting"},
+		{"User variables", "Project: {{project}}", data, "Project: Apollo"},
+		{"User variables override built-ins", "{{title}}", obsidian.TemplateData{Title: "x", Vars: map[string]string{"title": "y"}}, "y"},
+		{"Unknown variables are kept", "{{unknown}} <% tp.file.title %>", data, "{{unknown}} <% tp.file.title %>"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, obsidian.ExpandTemplate(test.template, test.data))
+		})
+	}
+}
+
+func TestReadTemplate(t *testing.T) {
+	vaultPath := t.TempDir()
+	writeVaultFiles(t, vaultPath, map[string]string{
+		".obsidian/templates.json": `{"folder": "Templates"}`,
+		"Templates/Meeting.md":     "# {{title}}",
+		"Other/Book.md":            "Book",
+	})
+
+	t.Run("Reads from the templates folder", func(t *testing.T) {
+		content, err := obsidian.ReadTemplate(vaultPath, "Meeting")
+		assert.NoError(t, err)
+		assert.Equal(t, "# {{title}}", content)
+	})
+
+	t.Run("Falls back to the vault root", func(t *testing.T) {
+		content, err := obsidian.ReadTemplate(vaultPath, "Other/Book.md")
+		assert.NoError(t, err)
+		assert.Equal(t, "Book", content)
+	})
+
+	t.Run("Missing template", func(t *testing.T) {
+		_, err := obsidian.ReadTemplate(vaultPath, "Missing")
+		assert.Equal(t, obsidian.ErrCodeTemplateNotFound, obsidian.ErrorCode(err))
+	})
+
+	t.Run("Template outside the vault", func(t *testing.T) {
+		_, err := obsidian.ReadTemplate(vaultPath, "../../etc/passwd")
+		assert.Error(t, err)
+	})
+}
@@ -1,7 +1,6 @@
 package obsidian
 
 import (
-	"errors"
 	"github.com/skratchdot/open-golang/open"
 	"net/url"
 )
@@ -34,7 +33,7 @@ func (u *Uri) Execute(uri string) error {
 	//fmt.Println("Opening URI: ", uri)
 	err := Run(uri)
 	if err != nil {
-		return errors.New(ExecuteUriError)
+		return NewError(ErrCodeExecuteUri, ExecuteUriError)
 
 	}
 	return nil
@@ -81,6 +81,9 @@ func GenerateBacklinkSearchPatterns(notePath string) []string {
 // - Simple wikilinks: [[note]], [[note|alias]], [[note#heading]]
 // - Path-based wikilinks: [[folder/note]], [[folder/note|alias]], [[folder/note#heading]]
 // - Markdown links: [text](folder/note.md), [text](./folder/note.md)
+//
+// Deprecated: Note.UpdateLinks resolves each parsed link instead of replacing
+// fixed patterns.
 func GenerateLinkReplacements(oldNotePath, newNotePath string) map[string]string {
 	replacements := make(map[string]string)
 
@@ -123,6 +126,9 @@ func GenerateLinkReplacements(oldNotePath, newNotePath string) map[string]string
 	return replacements
 }
 
+// ReplaceContent replaces every occurrence of each key of replacements.
+//
+// Deprecated: Note.UpdateLinks no longer uses pattern replacements.
 func ReplaceContent(content []byte, replacements map[string]string) []byte {
 	for o, n := range replacements {
 		content = bytes.ReplaceAll(content, []byte(o), []byte(n))
@@ -178,7 +184,7 @@ func OpenInEditor(filePath string) error {
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to open file in editor '%s': %w", editor, err)
+		return WrapError(ErrCodeEditor, fmt.Sprintf("failed to open file in editor '%s': %v", editor, err), err)
 	}
 
 	return nil
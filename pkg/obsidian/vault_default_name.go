@@ -2,7 +2,6 @@ package obsidian
 
 import (
 	"encoding/json"
-	"errors"
 	"github.com/Yakitrak/notesmd-cli/pkg/config"
 	"os"
 )
@@ -24,7 +23,7 @@ func (v *Vault) DefaultName() (string, error) {
 	// read file
 	content, err := os.ReadFile(cliConfigFile)
 	if err != nil {
-		return "", errors.New(ObsidianCLIConfigReadError)
+		return "", NewError(ErrCodeCLIConfigNotFound, ObsidianCLIConfigReadError)
 	}
 
 	// unmarshal json
@@ -32,11 +31,11 @@ func (v *Vault) DefaultName() (string, error) {
 	err = json.Unmarshal(content, &cliConfig)
 
 	if err != nil {
-		return "", errors.New(ObsidianCLIConfigParseError)
+		return "", NewError(ErrCodeCLIConfigInvalid, ObsidianCLIConfigParseError)
 	}
 
 	if cliConfig.DefaultVaultName == "" {
-		return "", errors.New(ObsidianCLIConfigParseError)
+		return "", NewError(ErrCodeCLIConfigInvalid, ObsidianCLIConfigParseError)
 	}
 
 	v.Name = cliConfig.DefaultVaultName
@@ -61,19 +60,19 @@ func (v *Vault) SetDefaultName(name string) error {
 	// marshal to json
 	jsonContent, err := JsonMarshal(cliConfig)
 	if err != nil {
-		return errors.New(ObsidianCLIConfigGenerateJSONError)
+		return NewError(ErrCodeCLIConfigWrite, ObsidianCLIConfigGenerateJSONError)
 	}
 
 	// create directory
 	err = os.MkdirAll(obsConfigDir, os.ModePerm)
 	if err != nil {
-		return errors.New(ObsidianCLIConfigDirWriteEror)
+		return NewError(ErrCodeCLIConfigWrite, ObsidianCLIConfigDirWriteEror)
 	}
 
 	// create and write file
 	err = os.WriteFile(obsConfigFile, jsonContent, 0644)
 	if err != nil {
-		return errors.New(ObsidianCLIConfigWriteError)
+		return NewError(ErrCodeCLIConfigWrite, ObsidianCLIConfigWriteError)
 	}
 
 	v.Name = name
@@ -120,15 +119,15 @@ func (v *Vault) SetDefaultOpenType(openType string) error {
 
 	jsonContent, err := JsonMarshal(cliConfig)
 	if err != nil {
-		return errors.New(ObsidianCLIConfigGenerateJSONError)
+		return NewError(ErrCodeCLIConfigWrite, ObsidianCLIConfigGenerateJSONError)
 	}
 
 	if err := os.MkdirAll(obsConfigDir, os.ModePerm); err != nil {
-		return errors.New(ObsidianCLIConfigDirWriteEror)
+		return NewError(ErrCodeCLIConfigWrite, ObsidianCLIConfigDirWriteEror)
 	}
 
 	if err := os.WriteFile(obsConfigFile, jsonContent, 0644); err != nil {
-		return errors.New(ObsidianCLIConfigWriteError)
+		return NewError(ErrCodeCLIConfigWrite, ObsidianCLIConfigWriteError)
 	}
 
 	return nil
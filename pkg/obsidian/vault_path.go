@@ -2,7 +2,6 @@ package obsidian
 
 import (
 	"encoding/json"
-	"errors"
 	"github.com/Yakitrak/notesmd-cli/pkg/config"
 	"os"
 	"strings"
@@ -19,7 +18,7 @@ func (v *Vault) Path() (string, error) {
 
 	content, err := os.ReadFile(obsidianConfigFile)
 	if err != nil {
-		return "", errors.New(ObsidianConfigReadError)
+		return "", NewError(ErrCodeObsidianConfigNotFound, ObsidianConfigReadError)
 	}
 
 	path, err := getPathForVault(content, v.Name)
@@ -47,7 +46,7 @@ func adjustForWslMount(dir string) string {
 func getPathForVault(content []byte, name string) (string, error) {
 	vaultsContent := ObsidianVaultConfig{}
 	if json.Unmarshal(content, &vaultsContent) != nil {
-		return "", errors.New(ObsidianConfigParseError)
+		return "", NewError(ErrCodeObsidianConfigInvalid, ObsidianConfigParseError)
 	}
 
 	for _, element := range vaultsContent.Vaults {
@@ -56,5 +55,5 @@ func getPathForVault(content []byte, name string) (string, error) {
 		}
 	}
 
-	return "", errors.New(ObsidianConfigVaultNotFoundError)
+	return "", NewError(ErrCodeVaultNotFound, ObsidianConfigVaultNotFoundError)
 }
\ No newline at end of file
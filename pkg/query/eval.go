@@ -0,0 +1,532 @@
+package query
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Page is a note as seen by a query.
+type Page struct {
+	// Path is the note's vault-relative, slash-separated path.
+	Path        string
+	Frontmatter map[string]interface{}
+	// Tags are the note's frontmatter and inline tags, without "#".
+	Tags    []string
+	ModTime time.Time
+	Size    int64
+}
+
+// env is what an expression is evaluated against.
+type env struct {
+	page *Page
+	now  time.Time
+}
+
+// expr is a node of a parsed expression. Values are nil, bool, float64,
+// string, []interface{} or map[string]interface{}.
+type expr interface {
+	eval(e *env) interface{}
+}
+
+type literalExpr struct{ value interface{} }
+type fieldExpr struct{ name string }
+type notExpr struct{ inner expr }
+type andExpr struct{ left, right expr }
+type orExpr struct{ left, right expr }
+
+type compareExpr struct {
+	op          string
+	left, right expr
+}
+
+type callExpr struct {
+	name string
+	fn   function
+	args []expr
+}
+
+func (x literalExpr) eval(e *env) interface{} { return x.value }
+func (x notExpr) eval(e *env) interface{}     { return !truthy(x.inner.eval(e)) }
+func (x andExpr) eval(e *env) interface{}     { return truthy(x.left.eval(e)) && truthy(x.right.eval(e)) }
+func (x orExpr) eval(e *env) interface{}      { return truthy(x.left.eval(e)) || truthy(x.right.eval(e)) }
+
+func (x fieldExpr) eval(e *env) interface{} {
+	p := e.page
+	switch strings.ToLower(x.name) {
+	case "file.name":
+		return strings.TrimSuffix(path.Base(p.Path), path.Ext(p.Path))
+	case "file.path":
+		return p.Path
+	case "file.folder":
+		if dir := path.Dir(p.Path); dir != "." {
+			return dir
+		}
+		return ""
+	case "file.ext":
+		return strings.TrimPrefix(path.Ext(p.Path), ".")
+	case "file.tags":
+		return normalize(p.Tags)
+	case "file.mtime":
+		return formatTime(p.ModTime)
+	case "file.size":
+		return float64(p.Size)
+	}
+	return lookup(p.Frontmatter, x.name)
+}
+
+func (x compareExpr) eval(e *env) interface{} {
+	left, right := x.left.eval(e), x.right.eval(e)
+	switch x.op {
+	case "=":
+		return equal(left, right)
+	case "!=":
+		return !equal(left, right)
+	}
+	c, ok := compare(left, right)
+	if !ok {
+		return false
+	}
+	switch x.op {
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	}
+	return c >= 0
+}
+
+func (x callExpr) eval(e *env) interface{} {
+	args := make([]interface{}, len(x.args))
+	for i, arg := range x.args {
+		args[i] = arg.eval(e)
+	}
+	return x.fn.call(e, args)
+}
+
+// lookup finds a frontmatter value. A dotted name first matches a key
+// containing the dots, then walks nested mappings. Keys are matched exactly
+// when possible and case-insensitively otherwise.
+func lookup(fm map[string]interface{}, name string) interface{} {
+	if v, ok := lookupKey(fm, name); ok {
+		return v
+	}
+	var current interface{} = fm
+	for _, part := range strings.Split(name, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if current, ok = lookupKey(m, part); !ok {
+			return nil
+		}
+	}
+	return current
+}
+
+func lookupKey(m map[string]interface{}, key string) (interface{}, bool) {
+	if v, ok := m[key]; ok {
+		return normalize(v), true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return normalize(v), true
+		}
+	}
+	return nil, false
+}
+
+// normalize converts decoded YAML into the value types used by expressions.
+// Dates become YYYY-MM-DD strings (or RFC 3339 when they have a time) so that
+// they compare correctly as text.
+func normalize(v interface{}) interface{} {
+	switch val := v.(type) {
+	case int:
+		return float64(val)
+	case int64:
+		return float64(val)
+	case uint64:
+		return float64(val)
+	case float32:
+		return float64(val)
+	case time.Time:
+		return formatTime(val)
+	case []string:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = item
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = normalize(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[k] = normalize(item)
+		}
+		return out
+	case map[interface{}]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, item := range val {
+			out[fmt.Sprint(k)] = normalize(item)
+		}
+		return out
+	}
+	return v
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 && t.Nanosecond() == 0 {
+		return t.Format("2006-01-02")
+	}
+	return t.Format(time.RFC3339)
+}
+
+func truthy(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return false
+	case bool:
+		return val
+	case float64:
+		return val != 0
+	case string:
+		return val != ""
+	case []interface{}:
+		return len(val) > 0
+	case map[string]interface{}:
+		return len(val) > 0
+	}
+	return true
+}
+
+func equal(a, b interface{}) bool {
+	if c, ok := compare(a, b); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compare orders two values of the same type. Numbers and strings that look
+// like numbers compare numerically with each other.
+func compare(a, b interface{}) (int, bool) {
+	switch x := a.(type) {
+	case float64:
+		if y, ok := toNumber(b); ok {
+			return compareFloats(x, y), true
+		}
+	case string:
+		switch y := b.(type) {
+		case string:
+			return strings.Compare(x, y), true
+		case float64:
+			if n, ok := toNumber(x); ok {
+				return compareFloats(n, y), true
+			}
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0, true
+			case !x:
+				return -1, true
+			}
+			return 1, true
+		}
+	}
+	return 0, false
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	switch val := v.(type) {
+	case float64:
+		return val, true
+	case string:
+		n, err := strconv.ParseFloat(strings.TrimSpace(val), 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// source selects pages in a FROM clause.
+type source interface {
+	matches(p *Page) bool
+}
+
+type folderSource string
+type tagSource string
+type notSource struct{ inner source }
+type andSource struct{ left, right source }
+type orSource struct{ left, right source }
+
+func (s folderSource) matches(p *Page) bool {
+	folder := strings.Trim(string(s), "/")
+	if folder == "" {
+		return true
+	}
+	return p.Path == folder || p.Path == folder+".md" || strings.HasPrefix(p.Path, folder+"/")
+}
+
+func (s tagSource) matches(p *Page) bool {
+	for _, tag := range p.Tags {
+		if strings.EqualFold(tag, string(s)) || (len(tag) > len(s) && strings.EqualFold(tag[:len(s)], string(s)) && tag[len(s)] == '/') {
+			return true
+		}
+	}
+	return false
+}
+
+func (s notSource) matches(p *Page) bool { return !s.inner.matches(p) }
+func (s andSource) matches(p *Page) bool { return s.left.matches(p) && s.right.matches(p) }
+func (s orSource) matches(p *Page) bool  { return s.left.matches(p) || s.right.matches(p) }
+
+// function is a built-in function callable from expressions.
+type function struct {
+	arity int
+	call  func(e *env, args []interface{}) interface{}
+}
+
+var functions = map[string]function{
+	"contains": {2, func(e *env, args []interface{}) interface{} {
+		return contains(args[0], args[1], false)
+	}},
+	"icontains": {2, func(e *env, args []interface{}) interface{} {
+		return contains(args[0], args[1], true)
+	}},
+	"startswith": {2, func(e *env, args []interface{}) interface{} {
+		s, ok1 := args[0].(string)
+		prefix, ok2 := args[1].(string)
+		return ok1 && ok2 && strings.HasPrefix(s, prefix)
+	}},
+	"endswith": {2, func(e *env, args []interface{}) interface{} {
+		s, ok1 := args[0].(string)
+		suffix, ok2 := args[1].(string)
+		return ok1 && ok2 && strings.HasSuffix(s, suffix)
+	}},
+	"length": {1, func(e *env, args []interface{}) interface{} {
+		switch val := args[0].(type) {
+		case string:
+			return float64(utf8.RuneCountInString(val))
+		case []interface{}:
+			return float64(len(val))
+		case map[string]interface{}:
+			return float64(len(val))
+		}
+		return float64(0)
+	}},
+	"lower": {1, func(e *env, args []interface{}) interface{} {
+		if s, ok := args[0].(string); ok {
+			return strings.ToLower(s)
+		}
+		return args[0]
+	}},
+	"upper": {1, func(e *env, args []interface{}) interface{} {
+		if s, ok := args[0].(string); ok {
+			return strings.ToUpper(s)
+		}
+		return args[0]
+	}},
+	"default": {2, func(e *env, args []interface{}) interface{} {
+		if args[0] == nil {
+			return args[1]
+		}
+		return args[0]
+	}},
+	"number": {1, func(e *env, args []interface{}) interface{} {
+		if n, ok := toNumber(args[0]); ok {
+			return n
+		}
+		return nil
+	}},
+	"date": {1, func(e *env, args []interface{}) interface{} {
+		s, ok := args[0].(string)
+		if !ok {
+			return nil
+		}
+		switch s {
+		case "today":
+			return e.now.Format("2006-01-02")
+		case "tomorrow":
+			return e.now.AddDate(0, 0, 1).Format("2006-01-02")
+		case "yesterday":
+			return e.now.AddDate(0, 0, -1).Format("2006-01-02")
+		case "now":
+			return e.now.Format(time.RFC3339)
+		}
+		for _, layout := range []string{"2006-01-02", time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04"} {
+			if t, err := time.Parse(layout, s); err == nil {
+				return formatTime(t)
+			}
+		}
+		return nil
+	}},
+}
+
+func isDateKeyword(word string) bool {
+	switch strings.ToLower(word) {
+	case "today", "tomorrow", "yesterday", "now":
+		return true
+	}
+	return false
+}
+
+// contains reports whether a string contains a substring, a list contains an
+// element or a mapping contains a key.
+func contains(haystack, needle interface{}, ignoreCase bool) bool {
+	fold := func(s string) string {
+		if ignoreCase {
+			return strings.ToLower(s)
+		}
+		return s
+	}
+	switch h := haystack.(type) {
+	case string:
+		n, ok := needle.(string)
+		return ok && strings.Contains(fold(h), fold(n))
+	case []interface{}:
+		for _, item := range h {
+			if s, ok := item.(string); ok {
+				if n, ok := needle.(string); ok && fold(s) == fold(n) {
+					return true
+				}
+				continue
+			}
+			if equal(item, needle) {
+				return true
+			}
+		}
+	case map[string]interface{}:
+		if n, ok := needle.(string); ok {
+			_, found := lookupKey(h, n)
+			return found
+		}
+	}
+	return false
+}
+
+// Execute runs the query over pages. now is the moment date(today) and
+// similar expressions refer to.
+func (q *Query) Execute(pages []Page, now time.Time) *Result {
+	type row struct {
+		page *Page
+		env  *env
+		keys []interface{}
+	}
+
+	var rows []row
+	for i := range pages {
+		page := &pages[i]
+		if q.from != nil && !q.from.matches(page) {
+			continue
+		}
+		e := &env{page: page, now: now}
+		selected := true
+		for _, cond := range q.where {
+			if !truthy(cond.eval(e)) {
+				selected = false
+				break
+			}
+		}
+		if !selected {
+			continue
+		}
+		r := row{page: page, env: e}
+		for _, key := range q.sort {
+			r.keys = append(r.keys, key.expr.eval(e))
+		}
+		rows = append(rows, r)
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for k, key := range q.sort {
+			c := compareForSort(rows[i].keys[k], rows[j].keys[k], key.descending)
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return rows[i].page.Path < rows[j].page.Path
+	})
+	if q.limit > 0 && len(rows) > q.limit {
+		rows = rows[:q.limit]
+	}
+
+	result := &Result{Kind: q.kind, Columns: []string{}, Rows: [][]interface{}{}}
+	showID := !q.withoutID || len(q.fields) == 0
+	if showID {
+		result.Columns = append(result.Columns, FileColumn)
+	}
+	for _, f := range q.fields {
+		result.Columns = append(result.Columns, f.name)
+	}
+	for _, r := range rows {
+		var values []interface{}
+		if showID {
+			values = append(values, r.page.Path)
+		}
+		for _, f := range q.fields {
+			values = append(values, f.expr.eval(r.env))
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	return result
+}
+
+// compareForSort orders sort keys. Missing values always sort last, and
+// values of different types are grouped by type.
+func compareForSort(a, b interface{}, descending bool) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	c, ok := compare(a, b)
+	if !ok {
+		c = strings.Compare(typeRank(a)+FormatValue(a), typeRank(b)+FormatValue(b))
+	}
+	if descending {
+		return -c
+	}
+	return c
+}
+
+func typeRank(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return "0"
+	case float64:
+		return "1"
+	case string:
+		return "2"
+	case []interface{}:
+		return "3"
+	}
+	return "4"
+}
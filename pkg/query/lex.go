@@ -0,0 +1,137 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenTag
+	tokenSymbol
+)
+
+// token is a lexical element of a query. start and end are byte offsets in
+// the query text.
+type token struct {
+	kind       tokenKind
+	value      string
+	start, end int
+}
+
+// symbols lists the operators and punctuation of the language, longest first
+// so that "<=" is not read as "<" followed by "=".
+var symbols = []string{"!=", "<=", ">=", "==", "&&", "||", "=", "<", ">", "!", "(", ")", ",", "-"}
+
+func lex(input string) ([]token, error) {
+	var tokens []token
+	i := 0
+	for i < len(input) {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '"' || r == '\'':
+			value, end, err := lexString(input, i)
+			if err != nil {
+				return nil, err
+			}
+			tokens = append(tokens, token{kind: tokenString, value: value, start: i, end: end})
+			i = end
+		case isDigit(r) || (r == '-' && i+1 < len(input) && isDigit(rune(input[i+1])) && !followsValue(tokens)):
+			start := i
+			i++
+			for i < len(input) && (isDigit(rune(input[i])) || strings.IndexByte(".-:T", input[i]) != -1) {
+				i++
+			}
+			kind := tokenNumber
+			if strings.ContainsAny(input[start+1:i], "-:T") {
+				// Dates and times such as 2024-05-01 are compared as text.
+				kind = tokenString
+			}
+			tokens = append(tokens, token{kind: kind, value: input[start:i], start: start, end: i})
+		case r == '#':
+			start := i
+			i++
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if !isIdentRune(r) && r != '/' {
+					break
+				}
+				i += size
+			}
+			if i == start+1 {
+				return nil, fmt.Errorf("expected a tag name after '#' at position %d", start+1)
+			}
+			tokens = append(tokens, token{kind: tokenTag, value: input[start+1 : i], start: start, end: i})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(input) {
+				r, size := utf8.DecodeRuneInString(input[i:])
+				if !isIdentRune(r) && r != '.' {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, token{kind: tokenIdent, value: input[start:i], start: start, end: i})
+		default:
+			matched := false
+			for _, s := range symbols {
+				if strings.HasPrefix(input[i:], s) {
+					tokens = append(tokens, token{kind: tokenSymbol, value: s, start: i, end: i + len(s)})
+					i += len(s)
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected character %q at position %d", r, i+1)
+			}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, start: len(input), end: len(input)}), nil
+}
+
+// lexString reads the quoted string starting at input[start], handling
+// backslash escapes, and returns its value and the offset just past it.
+func lexString(input string, start int) (string, int, error) {
+	quote := input[start]
+	var b strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch c := input[i]; {
+		case c == '\\' && i+1 < len(input):
+			i++
+			b.WriteByte(input[i])
+		case c == quote:
+			return b.String(), i + 1, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated string starting at position %d", start+1)
+}
+
+// followsValue reports whether the last token ends an operand, in which case
+// a following "-" is an operator rather than the sign of a number.
+func followsValue(tokens []token) bool {
+	if len(tokens) == 0 {
+		return false
+	}
+	last := tokens[len(tokens)-1]
+	return last.kind != tokenSymbol || last.value == ")"
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isIdentRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '-'
+}
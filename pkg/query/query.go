@@ -0,0 +1,470 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EmptyQueryError = "query is empty"
+	QueryTypeError  = "query must start with TABLE or LIST"
+)
+
+// Query types.
+const (
+	KindTable = "table"
+	KindList  = "list"
+)
+
+// Query is a parsed Dataview-style query over note frontmatter.
+//
+// Supported syntax:
+//
+//	TABLE [WITHOUT ID] expr [AS "Name"], ...
+//	LIST [WITHOUT ID] [expr]
+//	FROM "folder" | #tag          combined with and, or, - and parentheses
+//	WHERE expr
+//	SORT expr [ASC|DESC], ...
+//	LIMIT n
+//
+// Expressions refer to frontmatter keys by name (nested keys with dots) and to
+// file.name, file.path, file.folder, file.ext, file.tags, file.mtime and
+// file.size. They support strings, numbers, true, false and null, the
+// comparisons = != < <= > >=, and/or/! and the functions contains, icontains,
+// startswith, endswith, length, lower, upper, default, number and date.
+// Keywords are case-insensitive. WHERE, SORT and LIMIT may appear in any
+// order; repeated WHERE clauses must all hold.
+type Query struct {
+	kind      string
+	withoutID bool
+	fields    []column
+	from      source
+	where     []expr
+	sort      []sortKey
+	limit     int
+}
+
+// column is a TABLE column or the LIST value.
+type column struct {
+	name string
+	expr expr
+}
+
+type sortKey struct {
+	expr       expr
+	descending bool
+}
+
+// Parse parses a query string.
+func Parse(input string) (*Query, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{input: input, tokens: tokens}
+	return p.parseQuery()
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// keyword reports whether the next token is the given keyword and, if so,
+// consumes it.
+func (p *parser) keyword(word string) bool {
+	if t := p.peek(); t.kind == tokenIdent && strings.EqualFold(t.value, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) symbol(s string) bool {
+	if t := p.peek(); t.kind == tokenSymbol && t.value == s {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	where := "at end of query"
+	if t.kind != tokenEOF {
+		where = fmt.Sprintf("at position %d", t.start+1)
+	}
+	return fmt.Errorf("%s %s", fmt.Sprintf(format, args...), where)
+}
+
+// atClause reports whether the next token starts a clause or ends the query.
+func (p *parser) atClause() bool {
+	t := p.peek()
+	if t.kind == tokenEOF {
+		return true
+	}
+	if t.kind != tokenIdent {
+		return false
+	}
+	switch strings.ToUpper(t.value) {
+	case "FROM", "WHERE", "SORT", "LIMIT":
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseQuery() (*Query, error) {
+	if p.peek().kind == tokenEOF {
+		return nil, errors.New(EmptyQueryError)
+	}
+	q := &Query{}
+	switch {
+	case p.keyword("TABLE"):
+		q.kind = KindTable
+	case p.keyword("LIST"):
+		q.kind = KindList
+	default:
+		return nil, errors.New(QueryTypeError)
+	}
+	if p.keyword("WITHOUT") {
+		if !p.keyword("ID") {
+			return nil, p.errorf(p.peek(), "expected ID after WITHOUT")
+		}
+		q.withoutID = true
+	}
+
+	if err := p.parseColumns(q); err != nil {
+		return nil, err
+	}
+
+	if p.keyword("FROM") {
+		from, err := p.parseSourceOr()
+		if err != nil {
+			return nil, err
+		}
+		q.from = from
+	}
+
+	for p.peek().kind != tokenEOF {
+		switch {
+		case p.keyword("WHERE"):
+			cond, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			q.where = append(q.where, cond)
+		case p.keyword("SORT"):
+			keys, err := p.parseSortKeys()
+			if err != nil {
+				return nil, err
+			}
+			q.sort = append(q.sort, keys...)
+		case p.keyword("LIMIT"):
+			t := p.next()
+			n, err := strconv.Atoi(t.value)
+			if t.kind != tokenNumber || err != nil || n < 0 {
+				return nil, p.errorf(t, "expected a whole number after LIMIT")
+			}
+			q.limit = n
+		default:
+			return nil, p.errorf(p.peek(), "expected WHERE, SORT or LIMIT")
+		}
+	}
+	return q, nil
+}
+
+func (p *parser) parseColumns(q *Query) error {
+	if p.atClause() {
+		return nil
+	}
+	for {
+		start := p.peek()
+		e, err := p.parseOr()
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSpace(p.input[start.start:p.tokens[p.pos-1].end])
+		if p.keyword("AS") {
+			t := p.next()
+			if t.kind != tokenString && t.kind != tokenIdent {
+				return p.errorf(t, "expected a column name after AS")
+			}
+			name = t.value
+		}
+		q.fields = append(q.fields, column{name: name, expr: e})
+
+		if q.kind == KindList {
+			if !p.atClause() {
+				return p.errorf(p.peek(), "LIST takes a single expression")
+			}
+			return nil
+		}
+		if !p.symbol(",") {
+			if !p.atClause() {
+				return p.errorf(p.peek(), "expected ',' or FROM, WHERE, SORT or LIMIT")
+			}
+			return nil
+		}
+	}
+}
+
+func (p *parser) parseSortKeys() ([]sortKey, error) {
+	var keys []sortKey
+	for {
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		key := sortKey{expr: e}
+		if p.keyword("DESC") || p.keyword("DESCENDING") {
+			key.descending = true
+		} else if !p.keyword("ASC") {
+			p.keyword("ASCENDING")
+		}
+		keys = append(keys, key)
+		if !p.symbol(",") {
+			return keys, nil
+		}
+	}
+}
+
+func (p *parser) parseSourceOr() (source, error) {
+	left, err := p.parseSourceAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") || p.symbol("||") {
+		right, err := p.parseSourceAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orSource{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSourceAnd() (source, error) {
+	left, err := p.parseSourceUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") || p.symbol("&&") {
+		right, err := p.parseSourceUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andSource{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseSourceUnary() (source, error) {
+	if p.symbol("-") || p.symbol("!") || p.keyword("not") {
+		inner, err := p.parseSourceUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notSource{inner}, nil
+	}
+	t := p.next()
+	switch {
+	case t.kind == tokenString:
+		return folderSource(t.value), nil
+	case t.kind == tokenTag:
+		return tagSource(t.value), nil
+	case t.kind == tokenSymbol && t.value == "(":
+		inner, err := p.parseSourceOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.symbol(")") {
+			return nil, p.errorf(p.peek(), "expected ')'")
+		}
+		return inner, nil
+	}
+	return nil, p.errorf(t, "expected a \"folder\" or #tag")
+}
+
+func (p *parser) parseOr() (expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") || p.symbol("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") || p.symbol("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andExpr{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (expr, error) {
+	if p.symbol("!") || p.keyword("not") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{inner}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (expr, error) {
+	left, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokenSymbol {
+		return left, nil
+	}
+	op := t.value
+	switch op {
+	case "==":
+		op = "="
+	case "=", "!=", "<", "<=", ">", ">=":
+	default:
+		return left, nil
+	}
+	p.pos++
+	right, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	return compareExpr{op: op, left: left, right: right}, nil
+}
+
+func (p *parser) parsePrimary() (expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenString:
+		return literalExpr{t.value}, nil
+	case tokenNumber:
+		n, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			return nil, p.errorf(t, "invalid number %q", t.value)
+		}
+		return literalExpr{n}, nil
+	case tokenSymbol:
+		if t.value == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if !p.symbol(")") {
+				return nil, p.errorf(p.peek(), "expected ')'")
+			}
+			return inner, nil
+		}
+	case tokenIdent:
+		switch strings.ToLower(t.value) {
+		case "true":
+			return literalExpr{true}, nil
+		case "false":
+			return literalExpr{false}, nil
+		case "null":
+			return literalExpr{nil}, nil
+		}
+		if p.symbol("(") {
+			return p.parseCall(t)
+		}
+		return fieldExpr{name: t.value}, nil
+	}
+	return nil, p.errorf(t, "expected a value")
+}
+
+func (p *parser) parseCall(name token) (expr, error) {
+	fn, ok := functions[strings.ToLower(name.value)]
+	if !ok {
+		return nil, p.errorf(name, "unknown function %q", name.value)
+	}
+	call := callExpr{name: strings.ToLower(name.value), fn: fn}
+	if !p.symbol(")") {
+		for {
+			// date(today) and friends name a date rather than a field.
+			if t := p.peek(); call.name == "date" && t.kind == tokenIdent && isDateKeyword(t.value) {
+				p.pos++
+				call.args = append(call.args, literalExpr{strings.ToLower(t.value)})
+			} else {
+				arg, err := p.parseOr()
+				if err != nil {
+					return nil, err
+				}
+				call.args = append(call.args, arg)
+			}
+			if p.symbol(")") {
+				break
+			}
+			if !p.symbol(",") {
+				return nil, p.errorf(p.peek(), "expected ',' or ')'")
+			}
+		}
+	}
+	if len(call.args) != fn.arity {
+		return nil, p.errorf(name, "%s expects %d argument(s)", call.name, fn.arity)
+	}
+	return call, nil
+}
+
+// Condition is a standalone WHERE expression, used to select notes outside of
+// a full query.
+type Condition struct {
+	expr expr
+}
+
+// ParseCondition parses an expression in the syntax of a WHERE clause.
+func ParseCondition(input string) (*Condition, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+	if tokens[0].kind == tokenEOF {
+		return nil, errors.New(EmptyQueryError)
+	}
+	p := &parser{input: input, tokens: tokens}
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.errorf(t, "unexpected %q", t.value)
+	}
+	return &Condition{expr: e}, nil
+}
+
+// Matches reports whether the condition holds for page.
+func (c *Condition) Matches(page *Page, now time.Time) bool {
+	return truthy(c.expr.eval(&env{page: page, now: now}))
+}
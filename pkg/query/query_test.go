@@ -0,0 +1,191 @@
+package query_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/query"
+	"github.com/stretchr/testify/assert"
+)
+
+var now = time.Date(2024, 5, 20, 12, 0, 0, 0, time.UTC)
+
+func testPages() []query.Page {
+	return []query.Page{
+		{
+			Path:        "Projects/alpha.md",
+			Frontmatter: map[string]interface{}{"status": "active", "due": time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), "priority": 2, "owner": map[string]interface{}{"name": "Ann"}},
+			Tags:        []string{"project", "work/q2"},
+		},
+		{
+			Path:        "Projects/beta.md",
+			Frontmatter: map[string]interface{}{"status": "done", "due": "2024-05-01", "priority": 1},
+			Tags:        []string{"project"},
+		},
+		{
+			Path:        "Projects/Archive/gamma.md",
+			Frontmatter: map[string]interface{}{"Status": "active", "priority": 10, "aliases": []interface{}{"g", "Gamma"}},
+		},
+		{
+			Path: "inbox.md",
+			Tags: []string{"work"},
+		},
+	}
+}
+
+func run(t *testing.T, input string) *query.Result {
+	t.Helper()
+	q, err := query.Parse(input)
+	if err != nil {
+		t.Fatalf("parse %q: %v", input, err)
+	}
+	return q.Execute(testPages(), now)
+}
+
+func TestParse(t *testing.T) {
+	valid := []string{
+		`TABLE status, due FROM "Projects" WHERE status != "done" SORT due`,
+		`table without id file.name as "Name", length(aliases) AS count`,
+		`LIST`,
+		`LIST status FROM #project and -"Projects/Archive"`,
+		`TABLE WHERE (status = "active" or priority >= 2) && !contains(file.tags, "x") LIMIT 5`,
+		`TABLE due SORT due DESC, file.name ASC WHERE due < date(today)`,
+		`TABLE priority WHERE priority > -1`,
+	}
+	for _, input := range valid {
+		_, err := query.Parse(input)
+		assert.NoError(t, err, input)
+	}
+
+	invalid := map[string]string{
+		``:                           query.EmptyQueryError,
+		`SELECT status`:              query.QueryTypeError,
+		`TABLE status FROM`:          `expected a "folder" or #tag at end of query`,
+		`TABLE "unterminated`:        `unterminated string starting at position 7`,
+		`TABLE status LIMIT many`:    `expected a whole number after LIMIT at position 20`,
+		`TABLE missing(status)`:      `unknown function "missing" at position 7`,
+		`TABLE contains(status)`:     `contains expects 2 argument(s) at position 7`,
+		`LIST status, due`:           `LIST takes a single expression at position 12`,
+		`TABLE status due`:           `expected ',' or FROM, WHERE, SORT or LIMIT at position 14`,
+		`TABLE WHERE (status = "x"`:  `expected ')' at end of query`,
+		`TABLE WITHOUT status`:       `expected ID after WITHOUT at position 15`,
+		`TABLE status WHERE x GROUP`: `expected WHERE, SORT or LIMIT at position 22`,
+		`TABLE status WHERE x = @`:   `unexpected character '@' at position 24`,
+		`TABLE FROM # WHERE x`:       `expected a tag name after '#' at position 12`,
+	}
+	for input, message := range invalid {
+		_, err := query.Parse(input)
+		assert.EqualError(t, err, message, input)
+	}
+}
+
+func TestExecute(t *testing.T) {
+	t.Run("Table with folder, filter and sort", func(t *testing.T) {
+		result := run(t, `TABLE status, due FROM "Projects" WHERE status != "done" SORT due`)
+
+		assert.Equal(t, query.KindTable, result.Kind)
+		assert.Equal(t, []string{"File", "status", "due"}, result.Columns)
+		assert.Equal(t, [][]interface{}{
+			{"Projects/alpha.md", "active", "2024-06-01"},
+			{"Projects/Archive/gamma.md", "active", nil},
+		}, result.Rows)
+	})
+
+	t.Run("Table without id, aliases, functions and nested keys", func(t *testing.T) {
+		result := run(t, `TABLE WITHOUT ID file.name AS "Name", owner.name AS Owner, length(aliases), upper(default(status, "none")) SORT priority DESC`)
+
+		assert.Equal(t, []string{"Name", "Owner", "length(aliases)", `upper(default(status, "none"))`}, result.Columns)
+		assert.Equal(t, [][]interface{}{
+			{"gamma", nil, 2.0, "ACTIVE"},
+			{"alpha", "Ann", 0.0, "ACTIVE"},
+			{"beta", nil, 0.0, "DONE"},
+			{"inbox", nil, 0.0, "NONE"},
+		}, result.Rows)
+	})
+
+	t.Run("List from tags with negated folder", func(t *testing.T) {
+		result := run(t, `LIST FROM #work or (#project and -"Projects/beta")`)
+
+		assert.Equal(t, query.KindList, result.Kind)
+		assert.Equal(t, []string{"File"}, result.Columns)
+		assert.Equal(t, [][]interface{}{{"Projects/alpha.md"}, {"inbox.md"}}, result.Rows)
+	})
+
+	t.Run("List with a value", func(t *testing.T) {
+		result := run(t, `LIST status WHERE status LIMIT 2`)
+
+		assert.Equal(t, []string{"File", "status"}, result.Columns)
+		assert.Equal(t, [][]interface{}{{"Projects/Archive/gamma.md", "active"}, {"Projects/alpha.md", "active"}}, result.Rows)
+	})
+
+	t.Run("Dates, numbers and list membership", func(t *testing.T) {
+		tests := map[string][]string{
+			`LIST WHERE due < date(today)`:                           {"Projects/beta.md"},
+			`LIST WHERE due >= 2024-05-20`:                           {"Projects/alpha.md"},
+			`LIST WHERE priority >= 2`:                               {"Projects/Archive/gamma.md", "Projects/alpha.md"},
+			`LIST WHERE priority = "10"`:                             {"Projects/Archive/gamma.md"},
+			`LIST WHERE contains(aliases, "g")`:                      {"Projects/Archive/gamma.md"},
+			`LIST WHERE icontains(file.path, "ARCHIVE")`:             {"Projects/Archive/gamma.md"},
+			`LIST WHERE contains(file.tags, "work")`:                 {"inbox.md"},
+			`LIST WHERE !status`:                                     {"inbox.md"},
+			`LIST WHERE file.folder = "Projects"`:                    {"Projects/alpha.md", "Projects/beta.md"},
+			`LIST WHERE startswith(file.name, "b") or priority = 10`: {"Projects/Archive/gamma.md", "Projects/beta.md"},
+		}
+		for input, expected := range tests {
+			var paths []string
+			for _, row := range run(t, input).Rows {
+				paths = append(paths, row[0].(string))
+			}
+			assert.Equal(t, expected, paths, input)
+		}
+	})
+
+	t.Run("No matches gives an empty result", func(t *testing.T) {
+		result := run(t, `TABLE status WHERE status = "missing"`)
+		assert.Equal(t, []string{"File", "status"}, result.Columns)
+		assert.Empty(t, result.Rows)
+	})
+}
+
+func TestResultJSON(t *testing.T) {
+	result := run(t, `TABLE WITHOUT ID status, file.name, aliases FROM "Projects/Archive"`)
+
+	data, err := json.Marshal(result)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"columns":["status","file.name","aliases"],"rows":[{"status":"active","file.name":"gamma","aliases":["g","Gamma"]}]}`, string(data))
+}
+
+func TestFormatValue(t *testing.T) {
+	assert.Equal(t, "", query.FormatValue(nil))
+	assert.Equal(t, "true", query.FormatValue(true))
+	assert.Equal(t, "3", query.FormatValue(3.0))
+	assert.Equal(t, "1.5", query.FormatValue(1.5))
+	assert.Equal(t, "a, 2", query.FormatValue([]interface{}{"a", 2.0}))
+	assert.Equal(t, `{"k":"v"}`, query.FormatValue(map[string]interface{}{"k": "v"}))
+}
+
+func TestCondition(t *testing.T) {
+	t.Run("Matches pages", func(t *testing.T) {
+		cond, err := query.ParseCondition(`status = "active" and priority < 5`)
+		assert.NoError(t, err)
+
+		var matched []string
+		for _, page := range testPages() {
+			page := page
+			if cond.Matches(&page, now) {
+				matched = append(matched, page.Path)
+			}
+		}
+		assert.Equal(t, []string{"Projects/alpha.md"}, matched)
+	})
+
+	t.Run("Invalid conditions return an error", func(t *testing.T) {
+		_, err := query.ParseCondition(``)
+		assert.EqualError(t, err, query.EmptyQueryError)
+
+		_, err = query.ParseCondition(`status = "a" SORT status`)
+		assert.EqualError(t, err, `unexpected "SORT" at position 14`)
+	})
+}
@@ -0,0 +1,93 @@
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// FileColumn names the column holding each note's path.
+const FileColumn = "File"
+
+// Result is the output of a query: one row per selected note, with a value
+// for each column. A LIST result has the file column and at most one more.
+type Result struct {
+	Kind    string
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// Row is a result row whose JSON encoding is an object with the result's
+// column names as keys, in column order.
+type Row struct {
+	Columns []string
+	Values  []interface{}
+}
+
+// Records returns the rows of r as Rows.
+func (r *Result) Records() []Row {
+	records := make([]Row, len(r.Rows))
+	for i, values := range r.Rows {
+		records[i] = Row{Columns: r.Columns, Values: values}
+	}
+	return records
+}
+
+// MarshalJSON encodes the result as {"columns": [...], "rows": [{...}]}.
+func (r *Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Columns []string `json:"columns"`
+		Rows    []Row    `json:"rows"`
+	}{r.Columns, r.Records()})
+}
+
+// MarshalJSON encodes the row as an object that keeps column order.
+func (row Row) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, column := range row.Columns {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(column)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(row.Values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// FormatValue renders a value as text: null as an empty string, whole
+// numbers without a decimal point and lists as comma-separated items.
+func FormatValue(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case bool:
+		return strconv.FormatBool(val)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case string:
+		return val
+	case []interface{}:
+		items := make([]string, len(val))
+		for i, item := range val {
+			items[i] = FormatValue(item)
+		}
+		return strings.Join(items, ", ")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,185 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// BM25 parameters. k1 controls term frequency saturation and b how strongly
+// scores are normalized by document length.
+const (
+	bm25K1     = 1.2
+	bm25B      = 0.75
+	titleBoost = 2.0
+)
+
+// Document is a unit of searchable text. Title matches are weighted more
+// heavily than Body matches.
+type Document struct {
+	ID    string
+	Title string
+	Body  string
+}
+
+// Hit is the byte span of a match within a document body.
+type Hit struct {
+	Start int
+	End   int
+}
+
+// Result is a document matching a query together with its relevance score
+// and the positions of matching terms in its body and title.
+type Result struct {
+	ID        string
+	Score     float64
+	Hits      []Hit
+	TitleHits []Hit
+}
+
+// Engine ranks documents against queries using BM25.
+type Engine struct {
+	docs        []indexedDoc
+	vocabulary  []string
+	avgBodyLen  float64
+	avgTitleLen float64
+}
+
+type indexedDoc struct {
+	id    string
+	title field
+	body  field
+}
+
+// field is the tokenized form of a piece of text with a positional index.
+type field struct {
+	tokens    []Token
+	positions map[string][]int
+}
+
+func newField(text string) field {
+	f := field{tokens: Tokenize(text), positions: make(map[string][]int)}
+	for i, t := range f.tokens {
+		f.positions[t.Term] = append(f.positions[t.Term], i)
+	}
+	return f
+}
+
+// hits converts token positions into byte spans.
+func (f *field) hits(positions []int) []Hit {
+	hits := make([]Hit, 0, len(positions))
+	for _, p := range positions {
+		hits = append(hits, Hit{Start: f.tokens[p].Start, End: f.tokens[p].End})
+	}
+	return hits
+}
+
+// NewEngine tokenizes and indexes the given documents.
+func NewEngine(docs []Document) *Engine {
+	e := &Engine{docs: make([]indexedDoc, len(docs))}
+	vocab := make(map[string]struct{})
+	var bodyTotal, titleTotal int
+	for i, d := range docs {
+		e.docs[i] = indexedDoc{id: d.ID, title: newField(d.Title), body: newField(d.Body)}
+		bodyTotal += len(e.docs[i].body.tokens)
+		titleTotal += len(e.docs[i].title.tokens)
+		for term := range e.docs[i].body.positions {
+			vocab[term] = struct{}{}
+		}
+		for term := range e.docs[i].title.positions {
+			vocab[term] = struct{}{}
+		}
+	}
+
+	e.vocabulary = make([]string, 0, len(vocab))
+	for term := range vocab {
+		e.vocabulary = append(e.vocabulary, term)
+	}
+	sort.Strings(e.vocabulary)
+
+	if len(docs) > 0 {
+		e.avgBodyLen = float64(bodyTotal) / float64(len(docs))
+		e.avgTitleLen = float64(titleTotal) / float64(len(docs))
+	}
+	return e
+}
+
+// expandPrefix returns all indexed terms starting with prefix.
+func (e *Engine) expandPrefix(prefix string) []string {
+	i := sort.SearchStrings(e.vocabulary, prefix)
+	var terms []string
+	for ; i < len(e.vocabulary) && strings.HasPrefix(e.vocabulary[i], prefix); i++ {
+		terms = append(terms, e.vocabulary[i])
+	}
+	return terms
+}
+
+// Search returns the documents matching q, most relevant first. Documents
+// with equal scores are ordered by ID so results are stable.
+func (e *Engine) Search(q *Query) []Result {
+	leaves := q.root.positives()
+
+	// Count, per scoring leaf, how many documents contain it at all.
+	docFreq := make([]int, len(leaves))
+	bodyHits := make([][][]Hit, len(leaves))
+	titleHits := make([][][]Hit, len(leaves))
+	for li, l := range leaves {
+		bodyHits[li] = make([][]Hit, len(e.docs))
+		titleHits[li] = make([][]Hit, len(e.docs))
+		for di := range e.docs {
+			doc := &e.docs[di]
+			bodyHits[li][di] = l.occurrences(&doc.body, e)
+			titleHits[li][di] = l.occurrences(&doc.title, e)
+			if len(bodyHits[li][di]) > 0 || len(titleHits[li][di]) > 0 {
+				docFreq[li]++
+			}
+		}
+	}
+
+	var results []Result
+	n := float64(len(e.docs))
+	for di := range e.docs {
+		doc := &e.docs[di]
+		if !q.root.match(doc, e) {
+			continue
+		}
+
+		result := Result{ID: doc.id}
+		for li := range leaves {
+			idf := math.Log(1 + (n-float64(docFreq[li])+0.5)/(float64(docFreq[li])+0.5))
+			bodyScore := bm25(len(bodyHits[li][di]), len(doc.body.tokens), e.avgBodyLen)
+			titleScore := bm25(len(titleHits[li][di]), len(doc.title.tokens), e.avgTitleLen)
+			result.Score += idf * (bodyScore + titleBoost*titleScore)
+			result.Hits = append(result.Hits, bodyHits[li][di]...)
+			result.TitleHits = append(result.TitleHits, titleHits[li][di]...)
+		}
+		sortHits(result.Hits)
+		sortHits(result.TitleHits)
+		results = append(results, result)
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results
+}
+
+// bm25 returns the length-normalized term frequency component of BM25.
+func bm25(tf, docLen int, avgLen float64) float64 {
+	if tf == 0 {
+		return 0
+	}
+	norm := 1.0
+	if avgLen > 0 {
+		norm = 1 - bm25B + bm25B*float64(docLen)/avgLen
+	}
+	f := float64(tf)
+	return f * (bm25K1 + 1) / (f + bm25K1*norm)
+}
+
+func sortHits(hits []Hit) {
+	sort.Slice(hits, func(i, j int) bool { return hits[i].Start < hits[j].Start })
+}
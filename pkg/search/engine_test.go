@@ -0,0 +1,89 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func searchIDs(t *testing.T, docs []search.Document, query string) []string {
+	t.Helper()
+	q, err := search.ParseQuery(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range search.NewEngine(docs).Search(q) {
+		ids = append(ids, r.ID)
+	}
+	return ids
+}
+
+func TestEngineSearch(t *testing.T) {
+	docs := []search.Document{
+		{ID: "alpha.md", Title: "alpha", Body: "Project alpha kickoff meeting. The weekly sync covers alpha status."},
+		{ID: "beta.md", Title: "beta", Body: "Project beta notes. Weekly planning and sync."},
+		{ID: "gamma.md", Title: "gamma", Body: "Unrelated gardening notes about tomatoes."},
+		{ID: "draft.md", Title: "draft", Body: "Draft: alpha ideas, not ready."},
+	}
+
+	tests := []struct {
+		testName string
+		query    string
+		expected []string
+	}{
+		{"Single term ranks by frequency", "alpha", []string{"alpha.md", "draft.md"}},
+		{"Implicit AND prefers shorter notes", "project sync", []string{"beta.md", "alpha.md"}},
+		{"Explicit AND", "project AND notes", []string{"beta.md"}},
+		{"OR", "tomatoes OR kickoff", []string{"gamma.md", "alpha.md"}},
+		{"Dash excludes", "alpha -draft", []string{"alpha.md"}},
+		{"NOT excludes", "notes NOT gardening", []string{"beta.md"}},
+		{"Phrase requires order", `"weekly sync"`, []string{"alpha.md"}},
+		{"Negated phrase", `project -"weekly sync"`, []string{"beta.md"}},
+		{"Prefix wildcard", "garden*", []string{"gamma.md"}},
+		{"Grouping", "(tomatoes OR kickoff) project", []string{"alpha.md"}},
+		{"Title matches count", "gamma", []string{"gamma.md"}},
+		{"No matches", "nonexistent", nil},
+	}
+
+	for _, test := range tests {
+		t.Run(test.testName, func(t *testing.T) {
+			assert.Equal(t, test.expected, searchIDs(t, docs, test.query))
+		})
+	}
+}
+
+func TestEngineSearchScoring(t *testing.T) {
+	t.Run("Shorter documents with the same matches rank higher", func(t *testing.T) {
+		docs := []search.Document{
+			{ID: "long.md", Body: "term " + "filler words that pad out the document quite a lot more "},
+			{ID: "short.md", Body: "term here"},
+		}
+		assert.Equal(t, []string{"short.md", "long.md"}, searchIDs(t, docs, "term"))
+	})
+
+	t.Run("Title matches outrank body matches", func(t *testing.T) {
+		docs := []search.Document{
+			{ID: "body.md", Title: "other", Body: "budget review"},
+			{ID: "title.md", Title: "budget", Body: "review"},
+		}
+		assert.Equal(t, []string{"title.md", "body.md"}, searchIDs(t, docs, "budget"))
+	})
+
+	t.Run("Equal scores are ordered by ID", func(t *testing.T) {
+		docs := []search.Document{
+			{ID: "b.md", Body: "same"},
+			{ID: "a.md", Body: "same"},
+		}
+		assert.Equal(t, []string{"a.md", "b.md"}, searchIDs(t, docs, "same"))
+	})
+
+	t.Run("Hits point at matching terms in the body", func(t *testing.T) {
+		q, _ := search.ParseQuery(`"big cat" OR dog*`)
+		results := search.NewEngine([]search.Document{{ID: "a", Body: "A big cat and dogs"}}).Search(q)
+		assert.Len(t, results, 1)
+		assert.Equal(t, []search.Hit{{Start: 2, End: 9}, {Start: 14, End: 18}}, results[0].Hits)
+		assert.Greater(t, results[0].Score, 0.0)
+	})
+}
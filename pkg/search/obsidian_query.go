@@ -0,0 +1,510 @@
+package search
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MissingOperandError    = "search operator is missing a value"
+	UnterminatedRegexError = "search query has an unterminated /regex/"
+)
+
+// QueryNote is the view of a note that an ObsidianQuery is evaluated against.
+// Sections holds the text of each heading-delimited section, heading line
+// included; Tags holds the note's tags without the leading "#".
+type QueryNote struct {
+	Path     string
+	Content  string
+	Tags     []string
+	Sections []string
+}
+
+// LineMatch is a content line satisfying a query, with the byte span of the
+// first match within the line.
+type LineMatch struct {
+	Line  int // 1-based
+	Start int
+	End   int
+}
+
+// ObsidianQuery is a parsed query in Obsidian's search syntax:
+//
+//	meeting notes        both words, anywhere in the note or its path
+//	"exact phrase"       phrase, case-insensitive
+//	alpha OR beta        either side
+//	-word, -(a OR b)     exclude
+//	/\d{4}-\d{2}/        Go (RE2) regular expression
+//	path:Projects        note path contains the value
+//	file:meeting         file name contains the value
+//	tag:#todo            note has the tag or one of its nested tags
+//	content:word         only the note content is searched
+//	line:(foo bar)       all terms on the same line
+//	block:(foo bar)      all terms in the same paragraph
+//	section:(foo bar)    all terms under the same heading
+//
+// Operator values may be words, "phrases", /regexes/ or parenthesised groups.
+type ObsidianQuery struct {
+	root oqNode
+}
+
+// oqScope is the text an expression is evaluated against.
+type oqScope struct {
+	text  string
+	lower string
+	// whole is set when the scope is the entire note (content and path).
+	whole bool
+}
+
+func newScope(text string, whole bool) oqScope {
+	return oqScope{text: text, lower: strings.ToLower(text), whole: whole}
+}
+
+type oqNode interface {
+	match(note *QueryNote, scope oqScope) bool
+	// highlights returns the leaves whose matches should be reported as lines.
+	highlights() []oqLeaf
+}
+
+type oqLeaf interface {
+	oqNode
+	// find returns the byte span of the first match within text, or -1.
+	find(text string) (int, int)
+}
+
+type oqWord struct{ lower string }
+type oqRegex struct{ re *regexp.Regexp }
+type oqTag struct{ tag string }
+type oqAnd struct{ children []oqNode }
+type oqOr struct{ children []oqNode }
+type oqNot struct{ child oqNode }
+type oqOperator struct {
+	op    string
+	child oqNode
+}
+
+var oqOperatorRegex = regexp.MustCompile(`^(path|file|tag|content|line|block|section):`)
+
+// ParseObsidianQuery parses a query written in Obsidian's search syntax.
+func ParseObsidianQuery(input string) (*ObsidianQuery, error) {
+	tokens, err := lexObsidianQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &oqParser{tokens: tokens}
+	root, err := p.parseOr(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.New(UnbalancedParensError)
+	}
+	if root == nil {
+		return nil, errors.New(EmptyQueryError)
+	}
+	return &ObsidianQuery{root: root}, nil
+}
+
+// Match reports whether the note satisfies the query.
+func (q *ObsidianQuery) Match(note *QueryNote) bool {
+	return q.root.match(note, newScope(note.Path+"\n"+note.Content, true))
+}
+
+// MatchLines returns the content lines that contain a positive search term,
+// used to show where a matching note satisfied the query.
+func (q *ObsidianQuery) MatchLines(note *QueryNote) []LineMatch {
+	leaves := q.root.highlights()
+	var matches []LineMatch
+	for i, line := range strings.Split(note.Content, "\n") {
+		for _, l := range leaves {
+			if start, end := l.find(line); start != -1 {
+				matches = append(matches, LineMatch{Line: i + 1, Start: start, End: end})
+				break
+			}
+		}
+	}
+	return matches
+}
+
+// oqToken kinds: '(' and ')', '-' negation, '"' phrase, '/' regex,
+// 'o' operator and 'w' word.
+type oqToken struct {
+	kind  byte
+	value string
+}
+
+func lexObsidianQuery(input string) ([]oqToken, error) {
+	var tokens []oqToken
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, oqToken{kind: c})
+			i++
+		case c == '-' && i+1 < len(input) && strings.IndexByte(" \t\n\r", input[i+1]) == -1:
+			tokens = append(tokens, oqToken{kind: '-'})
+			i++
+		case c == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end == -1 {
+				return nil, errors.New(UnterminatedQuoteError)
+			}
+			tokens = append(tokens, oqToken{kind: '"', value: input[i+1 : i+1+end]})
+			i += end + 2
+		case c == '/':
+			end := i + 1
+			for end < len(input) && input[end] != '/' {
+				if input[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(input) {
+				return nil, errors.New(UnterminatedRegexError)
+			}
+			tokens = append(tokens, oqToken{kind: '/', value: input[i+1 : end]})
+			i = end + 1
+		default:
+			if m := oqOperatorRegex.FindString(input[i:]); m != "" {
+				tokens = append(tokens, oqToken{kind: 'o', value: strings.TrimSuffix(m, ":")})
+				i += len(m)
+				if i >= len(input) || strings.IndexByte(" \t\n\r)", input[i]) != -1 {
+					return nil, errors.New(MissingOperandError)
+				}
+				continue
+			}
+			start := i
+			for i < len(input) && strings.IndexByte(" \t\n\r()\"", input[i]) == -1 {
+				i++
+			}
+			tokens = append(tokens, oqToken{kind: 'w', value: input[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+type oqParser struct {
+	tokens []oqToken
+	pos    int
+}
+
+func (p *oqParser) peek() *oqToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *oqParser) parseOr(inTag bool) (oqNode, error) {
+	var children []oqNode
+	for {
+		child, err := p.parseAnd(inTag)
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			children = append(children, child)
+		}
+		if t := p.peek(); t != nil && t.kind == 'w' && t.value == "OR" {
+			p.pos++
+			continue
+		}
+		break
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return &oqOr{children: children}, nil
+}
+
+func (p *oqParser) parseAnd(inTag bool) (oqNode, error) {
+	var children []oqNode
+	for {
+		t := p.peek()
+		if t == nil || t.kind == ')' || (t.kind == 'w' && t.value == "OR") {
+			break
+		}
+		if t.kind == 'w' && t.value == "AND" {
+			p.pos++
+			continue
+		}
+		child, err := p.parseUnary(inTag)
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return &oqAnd{children: children}, nil
+}
+
+func (p *oqParser) parseUnary(inTag bool) (oqNode, error) {
+	if p.peek().kind == '-' {
+		p.pos++
+		if p.peek() == nil {
+			return nil, errors.New(EmptyQueryError)
+		}
+		child, err := p.parseUnary(inTag)
+		if err != nil || child == nil {
+			return nil, err
+		}
+		return &oqNot{child: child}, nil
+	}
+	return p.parsePrimary(inTag)
+}
+
+func (p *oqParser) parsePrimary(inTag bool) (oqNode, error) {
+	t := p.peek()
+	p.pos++
+	switch t.kind {
+	case '(':
+		inner, err := p.parseOr(inTag)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != ')' {
+			return nil, errors.New(UnbalancedParensError)
+		}
+		p.pos++
+		return inner, nil
+	case ')':
+		return nil, errors.New(UnbalancedParensError)
+	case 'o':
+		if p.peek() == nil {
+			return nil, errors.New(MissingOperandError)
+		}
+		child, err := p.parseUnary(inTag || t.value == "tag")
+		if err != nil {
+			return nil, err
+		}
+		if child == nil {
+			return nil, errors.New(MissingOperandError)
+		}
+		if t.value == "tag" {
+			return child, nil
+		}
+		return &oqOperator{op: t.value, child: child}, nil
+	case '/':
+		re, err := regexp.Compile(t.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression /%s/: %w", t.value, err)
+		}
+		return &oqRegex{re: re}, nil
+	default:
+		if t.value == "" {
+			return nil, nil
+		}
+		if inTag {
+			return &oqTag{tag: strings.ToLower(strings.TrimPrefix(t.value, "#"))}, nil
+		}
+		return &oqWord{lower: strings.ToLower(t.value)}, nil
+	}
+}
+
+func (n *oqWord) match(_ *QueryNote, scope oqScope) bool {
+	return strings.Contains(scope.lower, n.lower)
+}
+
+func (n *oqWord) find(text string) (int, int) {
+	return indexFold(text, n.lower)
+}
+
+func (n *oqRegex) match(_ *QueryNote, scope oqScope) bool {
+	return n.re.MatchString(scope.text)
+}
+
+func (n *oqRegex) find(text string) (int, int) {
+	loc := n.re.FindStringIndex(text)
+	if loc == nil {
+		return -1, -1
+	}
+	return loc[0], loc[1]
+}
+
+// match checks the note's tags when evaluated against the whole note, and
+// looks for the literal "#tag" within narrower scopes such as line:(...).
+func (n *oqTag) match(note *QueryNote, scope oqScope) bool {
+	if !scope.whole {
+		start, _ := n.find(scope.text)
+		return start != -1
+	}
+	for _, tag := range note.Tags {
+		tag = strings.ToLower(tag)
+		if tag == n.tag || strings.HasPrefix(tag, n.tag+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *oqTag) find(text string) (int, int) {
+	needle := "#" + n.tag
+	for offset := 0; ; {
+		i, j := indexFold(text[offset:], needle)
+		if i == -1 {
+			return -1, -1
+		}
+		start, end := offset+i, offset+j
+		if end == len(text) || !isTagRune(toLowerASCII(text[end])) {
+			return start, end
+		}
+		offset = end
+	}
+}
+
+// indexFold returns the start and end offsets in s of the first match of
+// lower, an already lowercased string, ignoring case. The offsets are into
+// s itself, which lowercasing may have made longer or shorter.
+func indexFold(s, lower string) (int, int) {
+	for i := range s {
+		j, k := i, 0
+		for k < len(lower) && j < len(s) {
+			r, n := utf8.DecodeRuneInString(s[j:])
+			lr, ln := utf8.DecodeRuneInString(lower[k:])
+			if unicode.ToLower(r) != lr {
+				break
+			}
+			j, k = j+n, k+ln
+		}
+		if k == len(lower) {
+			return i, j
+		}
+	}
+	if lower == "" {
+		return 0, 0
+	}
+	return -1, -1
+}
+
+func toLowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+	return c
+}
+
+// isTagRune reports whether c would continue a tag name. A "/" is allowed
+// after the match so that searching for a tag also finds its nested tags.
+func isTagRune(c byte) bool {
+	return c == '_' || c == '-' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 0x80
+}
+
+func (n *oqAnd) match(note *QueryNote, scope oqScope) bool {
+	for _, c := range n.children {
+		if !c.match(note, scope) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *oqOr) match(note *QueryNote, scope oqScope) bool {
+	for _, c := range n.children {
+		if c.match(note, scope) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *oqNot) match(note *QueryNote, scope oqScope) bool {
+	return !n.child.match(note, scope)
+}
+
+func (n *oqOperator) match(note *QueryNote, _ oqScope) bool {
+	switch n.op {
+	case "path":
+		return n.child.match(note, newScope(note.Path, false))
+	case "file":
+		return n.child.match(note, newScope(path.Base(note.Path), false))
+	case "content":
+		return n.child.match(note, newScope(note.Content, false))
+	case "line":
+		return n.matchAny(note, strings.Split(note.Content, "\n"))
+	case "block":
+		return n.matchAny(note, splitParagraphs(note.Content))
+	case "section":
+		sections := note.Sections
+		if sections == nil {
+			sections = []string{note.Content}
+		}
+		return n.matchAny(note, sections)
+	}
+	return false
+}
+
+func (n *oqOperator) matchAny(note *QueryNote, parts []string) bool {
+	for _, part := range parts {
+		if n.child.match(note, newScope(part, false)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *oqWord) highlights() []oqLeaf  { return []oqLeaf{n} }
+func (n *oqRegex) highlights() []oqLeaf { return []oqLeaf{n} }
+func (n *oqTag) highlights() []oqLeaf   { return []oqLeaf{n} }
+func (n *oqNot) highlights() []oqLeaf   { return nil }
+
+func (n *oqAnd) highlights() []oqLeaf {
+	var leaves []oqLeaf
+	for _, c := range n.children {
+		leaves = append(leaves, c.highlights()...)
+	}
+	return leaves
+}
+
+func (n *oqOr) highlights() []oqLeaf {
+	var leaves []oqLeaf
+	for _, c := range n.children {
+		leaves = append(leaves, c.highlights()...)
+	}
+	return leaves
+}
+
+// highlights skips path: and file: since they never match note content.
+func (n *oqOperator) highlights() []oqLeaf {
+	if n.op == "path" || n.op == "file" {
+		return nil
+	}
+	return n.child.highlights()
+}
+
+// splitParagraphs splits content into blocks separated by blank lines.
+func splitParagraphs(content string) []string {
+	var blocks []string
+	var current []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				blocks = append(blocks, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		blocks = append(blocks, strings.Join(current, "\n"))
+	}
+	return blocks
+}
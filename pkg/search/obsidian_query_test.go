@@ -0,0 +1,129 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseObsidianQuery(t *testing.T) {
+	invalid := []struct {
+		query    string
+		expected string
+	}{
+		{"", search.EmptyQueryError},
+		{"path: foo", search.MissingOperandError},
+		{"tag:", search.MissingOperandError},
+		{`"open`, search.UnterminatedQuoteError},
+		{"/open", search.UnterminatedRegexError},
+		{"line:(a b", search.UnbalancedParensError},
+	}
+	for _, test := range invalid {
+		t.Run("Invalid: "+test.query, func(t *testing.T) {
+			_, err := search.ParseObsidianQuery(test.query)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+
+	t.Run("Invalid regex", func(t *testing.T) {
+		_, err := search.ParseObsidianQuery("/(/")
+		assert.Error(t, err)
+	})
+}
+
+func TestObsidianQueryMatch(t *testing.T) {
+	note := &search.QueryNote{
+		Path:    "Projects/Meeting Notes.md",
+		Content: "# Agenda\nDiscuss budget for 2024-05\n\n# Actions\n- [ ] follow up #todo/later\nsend summary",
+		Tags:    []string{"work", "todo/later"},
+		Sections: []string{
+			"# Agenda\nDiscuss budget for 2024-05\n",
+			"# Actions\n- [ ] follow up #todo/later\nsend summary",
+		},
+	}
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"budget", true},
+		{"BUDGET summary", true},
+		{"budget missing", false},
+		{"budget OR missing", true},
+		{"-budget", false},
+		{"-(missing OR absent)", true},
+		{`"discuss budget"`, true},
+		{`"budget discuss"`, false},
+		{"meeting", true},
+		{"content:meeting", false},
+		{"path:projects", true},
+		{"path:Archive", false},
+		{`file:"meeting notes"`, true},
+		{"file:projects", false},
+		{"tag:#work", true},
+		{"tag:todo", true},
+		{"tag:#to", false},
+		{"tag:(missing OR work)", true},
+		{"-tag:work", false},
+		{"line:(budget 2024)", true},
+		{"line:(budget summary)", false},
+		{"line:(follow tag:#todo)", true},
+		{"block:(follow summary)", true},
+		{"block:(budget follow)", false},
+		{"section:(follow summary)", true},
+		{"section:(budget summary)", false},
+		{`/\d{4}-\d{2}/`, true},
+		{`/^send/`, false},
+		{`line:/^send/`, true},
+		{"path:Projects line:(budget -2023)", true},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			q, err := search.ParseObsidianQuery(test.query)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, q.Match(note))
+		})
+	}
+}
+
+func TestObsidianQueryMatchLines(t *testing.T) {
+	note := &search.QueryNote{
+		Path:    "plan.md",
+		Content: "alpha\nnothing\nsee #todo here\nbeta alpha",
+		Tags:    []string{"todo"},
+	}
+
+	t.Run("Reports lines with positive terms", func(t *testing.T) {
+		q, err := search.ParseObsidianQuery("alpha -gamma")
+		assert.NoError(t, err)
+		assert.Equal(t, []search.LineMatch{
+			{Line: 1, Start: 0, End: 5},
+			{Line: 4, Start: 5, End: 10},
+		}, q.MatchLines(note))
+	})
+
+	t.Run("Reports tag lines", func(t *testing.T) {
+		q, err := search.ParseObsidianQuery("tag:todo")
+		assert.NoError(t, err)
+		assert.Equal(t, []search.LineMatch{{Line: 3, Start: 4, End: 9}}, q.MatchLines(note))
+	})
+
+	t.Run("Offsets are into the original line when lowercasing changes its length", func(t *testing.T) {
+		// "Ⱥ" is two bytes but its lowercase "ⱥ" is three.
+		note := &search.QueryNote{Path: "a.md", Content: "ȺȺȺȺ foo\nȺȺ #Todo\nxȺy"}
+		q, err := search.ParseObsidianQuery("foo OR tag:todo OR ⱥy")
+		assert.NoError(t, err)
+		assert.Equal(t, []search.LineMatch{
+			{Line: 1, Start: 9, End: 12},
+			{Line: 2, Start: 5, End: 10},
+			{Line: 3, Start: 1, End: 4},
+		}, q.MatchLines(note))
+	})
+
+	t.Run("Path operators report no lines", func(t *testing.T) {
+		q, err := search.ParseObsidianQuery("path:plan")
+		assert.NoError(t, err)
+		assert.Empty(t, q.MatchLines(note))
+	})
+}
@@ -0,0 +1,320 @@
+package search
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	EmptyQueryError        = "search query is empty"
+	UnterminatedQuoteError = "search query has an unterminated quote"
+	UnbalancedParensError  = "search query has unbalanced parentheses"
+)
+
+// Query is a parsed full-text search query.
+//
+// Supported syntax:
+//   - words are ANDed together: `meeting notes`
+//   - "quoted phrases" must appear in order: `"weekly sync"`
+//   - OR between terms: `alpha OR beta`
+//   - NOT or a leading dash excludes: `NOT draft`, `-draft`
+//   - AND may be written explicitly: `alpha AND beta`
+//   - a trailing * matches by prefix: `proj*`
+//   - parentheses group: `(alpha OR beta) -draft`
+//
+// Operators are case-sensitive so that "and", "or" and "not" can still be
+// searched for as ordinary words.
+type Query struct {
+	root node
+}
+
+// node is an element of a parsed query tree.
+type node interface {
+	match(doc *indexedDoc, e *Engine) bool
+	// positives returns the term and phrase leaves that contribute to scoring,
+	// i.e. those not beneath a NOT.
+	positives() []leaf
+}
+
+// leaf is a scoring leaf: a single term, a prefix term or a phrase.
+type leaf interface {
+	node
+	// occurrences returns the byte spans of the leaf within the given field.
+	occurrences(f *field, e *Engine) []Hit
+}
+
+type termNode struct {
+	term   string
+	prefix bool
+}
+
+type phraseNode struct {
+	terms []string
+}
+
+type andNode struct{ children []node }
+type orNode struct{ children []node }
+type notNode struct{ child node }
+
+// ParseQuery parses a full-text query string.
+func ParseQuery(input string) (*Query, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.New(UnbalancedParensError)
+	}
+	if root == nil {
+		return nil, errors.New(EmptyQueryError)
+	}
+	return &Query{root: root}, nil
+}
+
+// queryToken is a lexical element of a query string.
+type queryToken struct {
+	kind  byte // '(' , ')', '"' for phrases, 'w' for words
+	value string
+}
+
+func lexQuery(input string) ([]queryToken, error) {
+	var tokens []queryToken
+	i := 0
+	for i < len(input) {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, queryToken{kind: c})
+			i++
+		case c == '"' || (c == '-' && i+1 < len(input) && input[i+1] == '"'):
+			negate := c == '-'
+			if negate {
+				i++
+			}
+			end := strings.IndexByte(input[i+1:], '"')
+			if end == -1 {
+				return nil, errors.New(UnterminatedQuoteError)
+			}
+			if negate {
+				tokens = append(tokens, queryToken{kind: 'w', value: "NOT"})
+			}
+			tokens = append(tokens, queryToken{kind: '"', value: input[i+1 : i+1+end]})
+			i += end + 2
+		default:
+			start := i
+			for i < len(input) && !strings.ContainsRune(" \t\n\r()\"", rune(input[i])) {
+				i++
+			}
+			tokens = append(tokens, queryToken{kind: 'w', value: input[start:i]})
+		}
+	}
+	return tokens, nil
+}
+
+type queryParser struct {
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryParser) peek() *queryToken {
+	if p.pos >= len(p.tokens) {
+		return nil
+	}
+	return &p.tokens[p.pos]
+}
+
+func (p *queryParser) parseOr() (node, error) {
+	var children []node
+	for {
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			children = append(children, child)
+		}
+		if t := p.peek(); t != nil && t.kind == 'w' && t.value == "OR" {
+			p.pos++
+			continue
+		}
+		break
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return &orNode{children: children}, nil
+}
+
+func (p *queryParser) parseAnd() (node, error) {
+	var children []node
+	for {
+		t := p.peek()
+		if t == nil || t.kind == ')' || (t.kind == 'w' && t.value == "OR") {
+			break
+		}
+		if t.kind == 'w' && t.value == "AND" {
+			p.pos++
+			continue
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+	switch len(children) {
+	case 0:
+		return nil, nil
+	case 1:
+		return children[0], nil
+	}
+	return &andNode{children: children}, nil
+}
+
+func (p *queryParser) parseUnary() (node, error) {
+	t := p.peek()
+	if t.kind == 'w' && (t.value == "NOT" || (strings.HasPrefix(t.value, "-") && len(t.value) > 1)) {
+		if t.value == "NOT" {
+			p.pos++
+		} else {
+			t.value = t.value[1:]
+		}
+		if p.peek() == nil {
+			return nil, errors.New(EmptyQueryError)
+		}
+		child, err := p.parseUnary()
+		if err != nil || child == nil {
+			return nil, err
+		}
+		return &notNode{child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *queryParser) parsePrimary() (node, error) {
+	t := p.peek()
+	p.pos++
+	switch t.kind {
+	case '(':
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.peek(); closing == nil || closing.kind != ')' {
+			return nil, errors.New(UnbalancedParensError)
+		}
+		p.pos++
+		return inner, nil
+	case ')':
+		return nil, errors.New(UnbalancedParensError)
+	case '"':
+		return newPhrase(Terms(t.value), false), nil
+	default:
+		prefix := strings.HasSuffix(t.value, "*")
+		return newPhrase(Terms(strings.TrimRight(t.value, "*")), prefix), nil
+	}
+}
+
+// newPhrase builds the node for a run of terms. A single term becomes a term
+// node; words that tokenize into several terms (e.g. "e-mail") are phrases.
+func newPhrase(terms []string, prefix bool) node {
+	switch len(terms) {
+	case 0:
+		return nil
+	case 1:
+		return &termNode{term: terms[0], prefix: prefix}
+	}
+	return &phraseNode{terms: terms}
+}
+
+func (n *termNode) positives() []leaf   { return []leaf{n} }
+func (n *phraseNode) positives() []leaf { return []leaf{n} }
+func (n *notNode) positives() []leaf    { return nil }
+
+func (n *andNode) positives() []leaf {
+	var leaves []leaf
+	for _, c := range n.children {
+		leaves = append(leaves, c.positives()...)
+	}
+	return leaves
+}
+
+func (n *orNode) positives() []leaf {
+	var leaves []leaf
+	for _, c := range n.children {
+		leaves = append(leaves, c.positives()...)
+	}
+	return leaves
+}
+
+func (n *termNode) match(doc *indexedDoc, e *Engine) bool {
+	return len(n.occurrences(&doc.title, e)) > 0 || len(n.occurrences(&doc.body, e)) > 0
+}
+
+func (n *phraseNode) match(doc *indexedDoc, e *Engine) bool {
+	return len(n.occurrences(&doc.title, e)) > 0 || len(n.occurrences(&doc.body, e)) > 0
+}
+
+func (n *notNode) match(doc *indexedDoc, e *Engine) bool {
+	return !n.child.match(doc, e)
+}
+
+func (n *andNode) match(doc *indexedDoc, e *Engine) bool {
+	for _, c := range n.children {
+		if !c.match(doc, e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *orNode) match(doc *indexedDoc, e *Engine) bool {
+	for _, c := range n.children {
+		if c.match(doc, e) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *termNode) occurrences(f *field, e *Engine) []Hit {
+	if !n.prefix {
+		return f.hits(f.positions[n.term])
+	}
+	var hits []Hit
+	for _, term := range e.expandPrefix(n.term) {
+		hits = append(hits, f.hits(f.positions[term])...)
+	}
+	return hits
+}
+
+func (n *phraseNode) occurrences(f *field, e *Engine) []Hit {
+	var hits []Hit
+	for _, start := range f.positions[n.terms[0]] {
+		matched := true
+		for offset, term := range n.terms[1:] {
+			pos := start + offset + 1
+			if pos >= len(f.tokens) || f.tokens[pos].Term != term {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			last := f.tokens[start+len(n.terms)-1]
+			hits = append(hits, Hit{Start: f.tokens[start].Start, End: last.End})
+		}
+	}
+	return hits
+}
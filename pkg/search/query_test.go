@@ -0,0 +1,48 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseQuery(t *testing.T) {
+	valid := []string{
+		"word",
+		"two words",
+		`"a phrase" AND other`,
+		"a OR b OR c",
+		"-excluded included",
+		"NOT excluded",
+		"(a OR b) -c",
+		"pre*",
+		"lowercase and or not are terms",
+	}
+	for _, query := range valid {
+		t.Run("Valid: "+query, func(t *testing.T) {
+			q, err := search.ParseQuery(query)
+			assert.NoError(t, err)
+			assert.NotNil(t, q)
+		})
+	}
+
+	invalid := []struct {
+		query    string
+		expected string
+	}{
+		{"", search.EmptyQueryError},
+		{"   ", search.EmptyQueryError},
+		{"!!!", search.EmptyQueryError},
+		{"NOT", search.EmptyQueryError},
+		{`"unterminated`, search.UnterminatedQuoteError},
+		{"(a OR b", search.UnbalancedParensError},
+		{"a OR b)", search.UnbalancedParensError},
+	}
+	for _, test := range invalid {
+		t.Run("Invalid: "+test.query, func(t *testing.T) {
+			_, err := search.ParseQuery(test.query)
+			assert.EqualError(t, err, test.expected)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package search_test
+
+import (
+	"testing"
+
+	"github.com/Yakitrak/notesmd-cli/pkg/search"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenize(t *testing.T) {
+	t.Run("Lowercases and records byte offsets", func(t *testing.T) {
+		tokens := search.Tokenize("Hello, World_2!")
+		assert.Equal(t, []search.Token{
+			{Term: "hello", Start: 0, End: 5},
+			{Term: "world_2", Start: 7, End: 14},
+		}, tokens)
+	})
+
+	t.Run("Handles unicode letters", func(t *testing.T) {
+		assert.Equal(t, []string{"café", "naïve"}, search.Terms("Café — naïve"))
+	})
+
+	t.Run("Returns nil for punctuation only", func(t *testing.T) {
+		assert.Nil(t, search.Tokenize("--- ... !!!"))
+	})
+}